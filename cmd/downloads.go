@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/josephlewis42/honeyssh/core/payload"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+var downloadsCmd = &cobra.Command{
+	Use:     "downloads",
+	Aliases: []string{"download", "payloads"},
+	Short:   "Explore files captured by the honeypot.",
+}
+
+var downloadsLsCommand = &cobra.Command{
+	Use:   "ls",
+	Short: "List captured files.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		store, err := loadPayloadStore()
+		if err != nil {
+			return err
+		}
+
+		entries, err := store.List()
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "SHA256\tSIZE\tSIGHTINGS\tFIRST SEEN\tLAST SEEN\tSOURCE")
+		for _, entry := range entries {
+			var source string
+			if len(entry.Sightings) > 0 {
+				source = entry.Sightings[len(entry.Sightings)-1].Source
+			}
+			fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\t%s\n",
+				entry.SHA256,
+				entry.Size,
+				len(entry.Sightings),
+				entry.FirstSeen().UTC().Format(time.RFC3339),
+				entry.LastSeen().UTC().Format(time.RFC3339),
+				source,
+			)
+		}
+		return w.Flush()
+	},
+}
+
+var downloadsShowCommand = &cobra.Command{
+	Use:   "show HASH",
+	Short: "Show the hashes and sightings of a captured file.",
+	Long: `Show the hashes and sightings of a captured file. HASH may be a
+SHA-256, SHA-1 or MD5 hash, or a unique prefix of the SHA-256.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		store, err := loadPayloadStore()
+		if err != nil {
+			return err
+		}
+
+		sha256Sum, err := store.Find(args[0])
+		if err != nil {
+			return err
+		}
+
+		entry, err := store.Get(sha256Sum)
+		if err != nil {
+			return err
+		}
+
+		out, err := yaml.Marshal(entry)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), string(out))
+		return nil
+	},
+}
+
+var downloadsExportOutput *string
+
+var downloadsExportCommand = &cobra.Command{
+	Use:   "export HASH",
+	Short: "Write the contents of a captured file.",
+	Long: `Write the contents of a captured file. HASH may be a SHA-256, SHA-1
+or MD5 hash, or a unique prefix of the SHA-256.
+
+Captured files are likely malicious, handle them with care.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		store, err := loadPayloadStore()
+		if err != nil {
+			return err
+		}
+
+		sha256Sum, err := store.Find(args[0])
+		if err != nil {
+			return err
+		}
+
+		fd, err := store.Open(sha256Sum)
+		if err != nil {
+			return err
+		}
+		defer fd.Close()
+
+		out := cmd.OutOrStdout()
+		if *downloadsExportOutput != "" && *downloadsExportOutput != "-" {
+			outFd, err := os.OpenFile(*downloadsExportOutput, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+			if err != nil {
+				return err
+			}
+			defer outFd.Close()
+			out = outFd
+		}
+
+		_, err = io.Copy(out, fd)
+		return err
+	},
+}
+
+func loadPayloadStore() (*payload.Store, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return payload.NewStore(config.DownloadFs()), nil
+}
+
+func init() {
+	rootCmd.AddCommand(downloadsCmd)
+	downloadsCmd.AddCommand(downloadsLsCommand)
+	downloadsCmd.AddCommand(downloadsShowCommand)
+	downloadsCmd.AddCommand(downloadsExportCommand)
+
+	downloadsExportOutput = downloadsExportCommand.Flags().StringP("output", "o", "", "File to write the payload to, stdout if blank.")
+}
@@ -1,11 +1,23 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"strings"
 	"time"
 
+	"github.com/josephlewis42/honeyssh/core/config"
+	"github.com/josephlewis42/honeyssh/core/export"
 	"github.com/josephlewis42/honeyssh/core/logger"
+	"github.com/josephlewis42/honeyssh/core/payload"
+	"github.com/josephlewis42/honeyssh/core/ttylog"
+	"github.com/josephlewis42/honeyssh/core/vos"
 	"github.com/spf13/cobra"
 	"sigs.k8s.io/yaml"
 )
@@ -14,18 +26,20 @@ var (
 	eventsFilter func(*logger.LogEntry) bool
 	sinceTime    *string
 	since        *time.Duration
+	untilTime    *string
 )
 
 var eventsCmd = &cobra.Command{
 	Use:   "events",
 	Short: "Explore the honeypot event log.",
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		var sinceFilter func(*logger.LogEntry) bool
 		switch {
 		case *since > 0 && *sinceTime != "":
 			return errors.New("can't supply both since and since-time")
 		case *since > 0:
 			sinceMicros := time.Now().UnixMicro() - since.Microseconds()
-			eventsFilter = func(le *logger.LogEntry) bool {
+			sinceFilter = func(le *logger.LogEntry) bool {
 				return le.TimestampMicros >= sinceMicros
 			}
 		case *sinceTime != "":
@@ -33,15 +47,26 @@ var eventsCmd = &cobra.Command{
 			if err != nil {
 				return fmt.Errorf("couldn't parse since-time: %v", err)
 			}
-			eventsFilter = func(le *logger.LogEntry) bool {
+			sinceFilter = func(le *logger.LogEntry) bool {
 				return le.TimestampMicros >= parsedSinceTime.UnixMicro()
 			}
 		default:
-			eventsFilter = func(*logger.LogEntry) bool {
+			sinceFilter = func(*logger.LogEntry) bool {
 				return true
 			}
 		}
 
+		eventsFilter = sinceFilter
+		if *untilTime != "" {
+			parsedUntilTime, err := time.Parse(time.RFC3339, *untilTime)
+			if err != nil {
+				return fmt.Errorf("couldn't parse until-time: %v", err)
+			}
+			eventsFilter = func(le *logger.LogEntry) bool {
+				return sinceFilter(le) && le.TimestampMicros < parsedUntilTime.UnixMicro()
+			}
+		}
+
 		return nil
 	},
 }
@@ -108,6 +133,7 @@ var interactionsCommand = &cobra.Command{
 		}); err != nil {
 			return err
 		}
+		addTypedLines(config, report)
 
 		out, err := yaml.Marshal(report)
 		if err != nil {
@@ -120,6 +146,77 @@ var interactionsCommand = &cobra.Command{
 	},
 }
 
+// addTypedLines reconstructs the lines typed in each session from its TTY log,
+// sessions with unreadable logs are skipped.
+func addTypedLines(config *config.Configuration, report *logger.InteractionReport) {
+	for _, sessionID := range report.SessionIDs() {
+		session := report.Session(sessionID)
+		if session.TTYLog == "" {
+			continue
+		}
+
+		logFd, err := config.OpenSessionLog(session.TTYLog)
+		if err != nil {
+			continue
+		}
+		lines, err := ttylog.AnalyzeKeystrokes(createLogSource(session.TTYLog, logFd))
+		logFd.Close()
+		if err != nil {
+			continue
+		}
+
+		for _, line := range lines {
+			session.TypedLines = append(session.TypedLines, logger.TypedLine{
+				TimestampMicros: line.TimestampMicros,
+				Text:            line.Text,
+				Cadence:         string(line.Cadence),
+			})
+		}
+	}
+}
+
+// redactTypedLines redacts the lines typed in each session.
+func redactTypedLines(config *config.Configuration, report *logger.InteractionReport) error {
+	for _, sessionID := range report.SessionIDs() {
+		session := report.Session(sessionID)
+		if len(session.TypedLines) == 0 {
+			continue
+		}
+
+		redactor, err := newRedactor(config, sessionID)
+		if err != nil {
+			return err
+		}
+		for i, line := range session.TypedLines {
+			session.TypedLines[i].Text = string(redactor.Redact([]byte(line.Text)))
+		}
+	}
+	return nil
+}
+
+// ttyLogSessionIDs maps the names of recordings to the sessions that
+// recorded them.
+func ttyLogSessionIDs(report *logger.InteractionReport) map[string]string {
+	out := make(map[string]string)
+	for _, sessionID := range report.SessionIDs() {
+		if name := report.Session(sessionID).TTYLog; name != "" {
+			out[name] = sessionID
+		}
+	}
+	return out
+}
+
+// newRedactor creates a redactor for the recording of a session from the
+// configured redactions. Honeytoken values that vary by session are only
+// redacted if the session is known.
+func newRedactor(config *config.Configuration, sessionID string) (*ttylog.Redactor, error) {
+	var literals []string
+	if config.Redaction.Honeytokens {
+		literals = vos.HoneytokenSecrets(config.Honeytokens, sessionID)
+	}
+	return ttylog.NewRedactor(config.Redaction.Patterns, literals, config.Redaction.Replacement)
+}
+
 var bugsCommand = &cobra.Command{
 	Use:   "bugs",
 	Short: "Show events that may have been caused by bugs in the Honeypot.",
@@ -157,12 +254,251 @@ var bugsCommand = &cobra.Command{
 	},
 }
 
+var (
+	htmlOutput *string
+	htmlRedact *bool
+)
+
+var htmlCommand = &cobra.Command{
+	Use:   "html",
+	Short: "Generate a self-contained HTML report.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		config, err := loadConfig()
+		if err != nil {
+			return err
+		}
+
+		fd, err := config.ReadAppLog()
+		if err != nil {
+			return err
+		}
+		defer fd.Close()
+
+		report := &logger.HTMLReport{
+			Summary:      &logger.Report{},
+			Interactions: &logger.InteractionReport{},
+			Bugs:         logger.NewBugReport(),
+			GeneratedAt:  time.Now(),
+			OpenDownload: func(name string) (io.ReadCloser, error) {
+				return config.OpenDownload(name + ".download")
+			},
+		}
+		report.OpenTTYLog = func(name string) (io.ReadCloser, error) {
+			logFd, err := config.OpenSessionLog(name)
+			if err != nil {
+				return nil, err
+			}
+			defer logFd.Close()
+
+			// Convert recordings to asciicast so the report's player can
+			// read them.
+			buf := &bytes.Buffer{}
+			sink := ttylog.NewAsciicastLogSink(buf)
+			if *htmlRedact {
+				redactor, err := newRedactor(config, ttyLogSessionIDs(report.Interactions)[name])
+				if err != nil {
+					return nil, err
+				}
+				sink = ttylog.NewRedactingAdapter(redactor, sink)
+			}
+			if err := ttylog.Replay(createLogSource(name, logFd), sink); err != nil {
+				return nil, err
+			}
+			return io.NopCloser(buf), nil
+		}
+		if err := logger.ReadJSONLinesLog(fd, func(le *logger.LogEntry) {
+			if eventsFilter(le) {
+				report.Summary.Update(le)
+				report.Interactions.Update(le)
+				report.Bugs.Update(le)
+			}
+		}); err != nil {
+			return err
+		}
+		addTypedLines(config, report.Interactions)
+		if *htmlRedact {
+			if err := redactTypedLines(config, report.Interactions); err != nil {
+				return err
+			}
+		}
+
+		out := cmd.OutOrStdout()
+		if *htmlOutput != "" && *htmlOutput != "-" {
+			outFd, err := os.Create(*htmlOutput)
+			if err != nil {
+				return err
+			}
+			defer outFd.Close()
+			out = outFd
+		}
+
+		return report.Render(out)
+	},
+}
+
+var (
+	exportFormat *string
+	exportOutput *string
+)
+
+var exportCommand = &cobra.Command{
+	Use:   "export",
+	Short: "Export indicators as STIX 2.1 or MISP JSON.",
+	Long: `Export attacker IPs, download URLs, payload hashes, SSH client
+fingerprints and credential pairs as threat intelligence.
+
+Indicators are de-duplicated and carry the first and last time they were seen
+within the selected time window.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		var write func(io.Writer, []*export.Observable, export.Options) error
+		switch *exportFormat {
+		case "stix":
+			write = export.WriteSTIX
+		case "misp":
+			write = export.WriteMISP
+		default:
+			return fmt.Errorf("unknown format %q, expected stix or misp", *exportFormat)
+		}
+
+		config, err := loadConfig()
+		if err != nil {
+			return err
+		}
+
+		fd, err := config.ReadAppLog()
+		if err != nil {
+			return err
+		}
+		defer fd.Close()
+
+		collector := &export.Collector{
+			HashDownload: func(name string) (string, string, error) {
+				downloadFd, err := config.OpenDownload(name + ".download")
+				if err != nil {
+					return "", "", err
+				}
+				defer downloadFd.Close()
+
+				info, err := payload.HashReader(downloadFd)
+				if err != nil {
+					return "", "", err
+				}
+				return info.SHA256, info.MD5, nil
+			},
+		}
+		if err := logger.ReadJSONLinesLog(fd, func(le *logger.LogEntry) {
+			if eventsFilter(le) {
+				collector.Update(le)
+			}
+		}); err != nil {
+			return err
+		}
+
+		// Include downloads missing from the log e.g. if it was rotated.
+		if err := addStoredDownloads(config, collector); err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		if *exportOutput != "" && *exportOutput != "-" {
+			outFd, err := os.Create(*exportOutput)
+			if err != nil {
+				return err
+			}
+			defer outFd.Close()
+			out = outFd
+		}
+
+		return write(out, collector.Observables(), export.Options{
+			Name:    config.Uname.Nodename,
+			Created: time.Now(),
+		})
+	},
+}
+
+// addStoredDownloads adds sightings from the payload store and legacy
+// download metadata files in the time window to the collector.
+func addStoredDownloads(configuration *config.Configuration, collector *export.Collector) error {
+	entries, err := payload.NewStore(configuration.DownloadFs()).List()
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		for _, sighting := range entry.Sightings {
+			if !eventsFilter(&logger.LogEntry{TimestampMicros: sighting.Time.UnixMicro()}) {
+				continue
+			}
+			collector.AddDownload(export.Download{
+				Name:   sighting.ID,
+				Source: sighting.Source,
+				Time:   sighting.Time,
+				SHA256: entry.SHA256,
+				MD5:    entry.MD5,
+			})
+		}
+	}
+
+	files, err := configuration.ListDownloads()
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil
+	case err != nil:
+		return err
+	}
+
+	for _, file := range files {
+		base, ok := strings.CutSuffix(file.Name(), vos.DownloadMetadataSuffix)
+		if !ok {
+			continue
+		}
+		timestamp, err := time.Parse(time.RFC3339Nano, base)
+		if err != nil {
+			continue
+		}
+		if !eventsFilter(&logger.LogEntry{TimestampMicros: timestamp.UnixMicro()}) {
+			continue
+		}
+
+		metadataFd, err := configuration.OpenDownload(file.Name())
+		if err != nil {
+			return err
+		}
+		var info vos.DownloadInfo
+		err = json.NewDecoder(metadataFd).Decode(&info)
+		metadataFd.Close()
+		if err != nil {
+			log.Printf("couldn't read %s: %v", file.Name(), err)
+			continue
+		}
+
+		collector.AddDownload(export.Download{
+			Name:   base,
+			Source: info.Source,
+			Time:   timestamp,
+		})
+	}
+	return nil
+}
+
 func init() {
 	rootCmd.AddCommand(eventsCmd)
 	eventsCmd.AddCommand(summaryCommand)
 	eventsCmd.AddCommand(interactionsCommand)
 	eventsCmd.AddCommand(bugsCommand)
+	eventsCmd.AddCommand(htmlCommand)
+	eventsCmd.AddCommand(exportCommand)
+
+	htmlOutput = htmlCommand.Flags().StringP("output", "o", "", "File to write the report to, stdout if blank.")
+	htmlRedact = htmlCommand.Flags().Bool("redact", false, "Mask the patterns and honeytokens configured in redaction in recordings and typed lines.")
+
+	exportFormat = exportCommand.Flags().String("format", "stix", "Export format, one of stix or misp.")
+	exportOutput = exportCommand.Flags().StringP("output", "o", "", "File to write the export to, stdout if blank.")
 
 	since = eventsCmd.PersistentFlags().Duration("since", -1, "Display events newer than a relative duration. e.g. 24h")
 	sinceTime = eventsCmd.PersistentFlags().String("since-time", "", "Display events after a specific date (RFC3339).")
+	untilTime = eventsCmd.PersistentFlags().String("until-time", "", "Display events before a specific date (RFC3339).")
 }
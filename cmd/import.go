@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/josephlewis42/honeyssh/core/classify"
+	"github.com/josephlewis42/honeyssh/core/importer"
+	"github.com/josephlewis42/honeyssh/core/logger"
+	"github.com/spf13/cobra"
+)
+
+var importOutput *string
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import logs from other honeypots.",
+}
+
+var importCowrieCommand = &cobra.Command{
+	Use:   "cowrie FILE...",
+	Short: "Convert Cowrie JSON logs into honeypot events.",
+	Long: `Converts Cowrie's JSON event logs (cowrie.json) into the format of app.log so
+they can be explored with the events commands. Files ending in .gz are
+decompressed. Sessions can span files so list rotated logs oldest first.
+
+Commands are classified with the builtin rules. Cowrie events without an
+equivalent, like key exchanges, are skipped and counted.
+
+Cowrie's TTY logs can be replayed with the logs commands as they are, copy
+them to session_logs to find them from the events.`,
+	Example: `honeyssh import cowrie -o app.log cowrie.json.2023-01-01 cowrie.json.2023-01-02 cowrie.json
+honeyssh import cowrie cowrie.json.gz | jq -c 'select(.runCommand)'`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		out := cmd.OutOrStdout()
+		if *importOutput != "" {
+			// Events are appended like the honeypot does to app.log.
+			outFd, err := os.OpenFile(*importOutput, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+			if err != nil {
+				return err
+			}
+			defer outFd.Close()
+			out = outFd
+		}
+
+		cowrie := importer.NewCowrieImporter(classify.Default(), logger.NewJsonLinesLogRecorder(out).Record)
+		for _, name := range args {
+			if err := importCowrieFile(cowrie, name); err != nil {
+				return fmt.Errorf("%s: %v", name, err)
+			}
+		}
+		if err := cowrie.Flush(); err != nil {
+			return err
+		}
+
+		skipped := 0
+		var skippedIDs []string
+		for id, count := range cowrie.Skipped {
+			skipped += count
+			skippedIDs = append(skippedIDs, fmt.Sprintf("%s (%d)", id, count))
+		}
+		sort.Strings(skippedIDs)
+		fmt.Fprintf(cmd.ErrOrStderr(), "Read %d events, skipped %d\n", cowrie.Events, skipped)
+		for _, id := range skippedIDs {
+			fmt.Fprintf(cmd.ErrOrStderr(), "- %s\n", id)
+		}
+		return nil
+	},
+}
+
+func importCowrieFile(cowrie *importer.CowrieImporter, name string) error {
+	fd, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	var r io.Reader = fd
+	if strings.HasSuffix(name, ".gz") {
+		gzipReader, err := gzip.NewReader(fd)
+		if err != nil {
+			return err
+		}
+		r = gzipReader
+	}
+	return cowrie.Import(r)
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.AddCommand(importCowrieCommand)
+
+	importOutput = importCowrieCommand.Flags().StringP("output", "o", "", "File to append the events to e.g. app.log, stdout if blank.")
+}
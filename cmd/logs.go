@@ -1,12 +1,20 @@
 package cmd
 
 import (
+	"compress/gzip"
+	"errors"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"text/tabwriter"
 	"time"
 
+	"github.com/abiosoft/readline"
+	"github.com/josephlewis42/honeyssh/core/config"
+	"github.com/josephlewis42/honeyssh/core/logger"
 	"github.com/josephlewis42/honeyssh/core/ttylog"
 	"github.com/spf13/cobra"
 )
@@ -14,6 +22,23 @@ import (
 var (
 	fixKippoQuirks bool
 	idleTimeLimit  time.Duration
+	convertFrom    string
+	convertTo      string
+	interactive    bool
+	jumpDuration   time.Duration
+	renderFormat   string
+	renderEvery    time.Duration
+	renderFPS      float64
+	renderOutput   string
+	convertRedact  bool
+	windowFrom     time.Duration
+	windowTo       time.Duration
+	stdinOnly      bool
+	stdoutOnly     bool
+	grepPattern    string
+	grepRegexp     *regexp.Regexp
+	stripANSI      bool
+	jsonOutput     bool
 )
 
 var logsCmd = &cobra.Command{
@@ -26,8 +51,23 @@ var logsCmd = &cobra.Command{
 var playCommand = &cobra.Command{
 	Use:   "play",
 	Short: "Replay a recorded interactive session in the terminal.",
-	Long:  `Plays a recorded interactive session back to the current terminal.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Plays a recorded interactive session back to the current terminal.
+
+With --interactive, playback is controlled from the keyboard:
+
+  space        pause/resume
+  + or ]       speed up
+  - or [       slow down
+  right or l   jump forward
+  left or h    jump back
+  n            jump to the next command
+  i            show/hide keystrokes
+  q            quit`,
+	Example: `honeyssh logs play path/to/some.cast
+honeyssh logs play --from 2m --to 5m path/to/some.cast
+honeyssh logs play --grep 'wget|curl' path/to/some.cast`,
+	Args:    cobra.ExactArgs(1),
+	PreRunE: parseFilterFlags,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cmd.SilenceUsage = true
 		fd, err := os.Open(args[0])
@@ -36,17 +76,87 @@ var playCommand = &cobra.Command{
 		}
 		source := createLogSource(args[0], fd)
 
-		sink := ttylog.NewClientOutput(cmd.OutOrStdout())
+		if interactive {
+			return playInteractive(cmd.OutOrStdout(), source)
+		}
+
+		sink := newClientSink(cmd.OutOrStdout())
+		sink = ttylog.NewClientResize(cmd.OutOrStdout(), sink)
 		sink = ttylog.NewRealTimePlayback(idleTimeLimit, sink)
 		return ttylog.Replay(source, applyMiddleware(sink))
 	},
 }
 
+// playInteractive plays the log, reading controls from stdin.
+func playInteractive(out io.Writer, source ttylog.LogSource) error {
+	stdinFd := int(os.Stdin.Fd())
+	if !readline.IsTerminal(stdinFd) {
+		return errors.New("interactive playback needs stdin to be a terminal")
+	}
+
+	player := ttylog.NewPlayer(out)
+	player.Jump = jumpDuration
+	player.MaxIdle = idleTimeLimit
+	if err := ttylog.Replay(source, applyMiddleware(player.Add)); err != nil {
+		return err
+	}
+
+	state, err := readline.MakeRaw(stdinFd)
+	if err != nil {
+		return err
+	}
+	defer readline.Restore(stdinFd, state)
+
+	controls := make(chan ttylog.PlayerControl)
+	go func() {
+		buf := make([]byte, 16)
+		for {
+			n, err := os.Stdin.Read(buf)
+			if err != nil {
+				close(controls)
+				return
+			}
+			if control, ok := playerKeys[string(buf[:n])]; ok {
+				controls <- control
+			}
+		}
+	}()
+
+	return player.Play(controls)
+}
+
+// playerKeys maps keypresses to player controls.
+var playerKeys = map[string]ttylog.PlayerControl{
+	" ":      ttylog.PlayerTogglePause,
+	"+":      ttylog.PlayerFaster,
+	"=":      ttylog.PlayerFaster,
+	"]":      ttylog.PlayerFaster,
+	"-":      ttylog.PlayerSlower,
+	"[":      ttylog.PlayerSlower,
+	"\x1b[C": ttylog.PlayerForward,
+	"l":      ttylog.PlayerForward,
+	"\x1b[D": ttylog.PlayerBack,
+	"h":      ttylog.PlayerBack,
+	"n":      ttylog.PlayerNextCommand,
+	"i":      ttylog.PlayerToggleInput,
+	"q":      ttylog.PlayerQuit,
+	"\x03":   ttylog.PlayerQuit, // Ctrl+C
+}
+
 // catCommand represents the playLog command
 var catCommand = &cobra.Command{
 	Use:   "cat",
 	Short: "Print full output of recorded log to a terminal.",
-	Args:  cobra.ExactArgs(1),
+	Long: `Prints the output of a recorded log, or the keystrokes with --stdin-only.
+
+Use --from and --to to print part of the log, --grep to start at the first
+output matching a regular expression, --strip-ansi to export plain text and
+--json to print each entry as a line of JSON for scripting.`,
+	Example: `honeyssh logs cat --strip-ansi path/to/some.cast > session.txt
+honeyssh logs cat --from 1m30s --to 2m path/to/some.cast
+honeyssh logs cat --json --stdin-only path/to/some.cast | jq .`,
+	Args:    cobra.ExactArgs(1),
+	PreRunE: parseFilterFlags,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cmd.SilenceUsage = true
 		fd, err := os.Open(args[0])
@@ -55,18 +165,23 @@ var catCommand = &cobra.Command{
 		}
 
 		source := createLogSource(args[0], fd)
-		sink := ttylog.NewClientOutput(cmd.OutOrStdout())
+		sink := newClientSink(cmd.OutOrStdout())
+		if jsonOutput {
+			sink = ttylog.NewJSONLinesSink(cmd.OutOrStdout())
+		}
 
 		return ttylog.Replay(source, applyMiddleware(sink))
 	},
 }
 
-// asciicastCmd converts a log to the asciicast format
-var asciicastCmd = &cobra.Command{
-	Use:   "asciicast INPUT.log > OUTPUT.cast",
-	Short: "Convert a log to asciicast (asciinema) format.",
-	Long:  `Convert a recorded terminal log to asciicast (asciinema) format.`,
-	Args:  cobra.ExactArgs(1),
+// keystrokesCommand prints the lines typed in a log.
+var keystrokesCommand = &cobra.Command{
+	Use:   "keystrokes FILE",
+	Short: "Reconstruct the lines typed in a recorded log from its keystrokes.",
+	Long: `Replays the keystrokes in a recorded log through a line editor to show each
+line that was submitted, including ones typed at prompts other than the shell.
+Each line is labeled with how it was typed: human, pasted or scripted.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cmd.SilenceUsage = true
 		fd, err := os.Open(args[0])
@@ -75,23 +190,301 @@ var asciicastCmd = &cobra.Command{
 		}
 		defer fd.Close()
 
-		source := ttylog.NewUMLLogSource(fd)
-		sink := ttylog.NewAsciicastLogSink(cmd.OutOrStdout())
+		lines, err := ttylog.AnalyzeKeystrokes(createLogSource(args[0], fd))
+		if err != nil {
+			return err
+		}
 
-		return ttylog.Replay(source, applyMiddleware(sink))
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "TIME\tCADENCE\tKEYS\tDURATION\tLINE")
+		for _, line := range lines {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%q\n",
+				time.UnixMicro(line.TimestampMicros).UTC().Format(time.RFC3339),
+				line.Cadence,
+				line.Keystrokes,
+				time.Duration(line.DurationMicros)*time.Microsecond,
+				line.Text,
+			)
+		}
+		return w.Flush()
 	},
 }
 
-func createLogSource(name string, r io.Reader) ttylog.LogSource {
+// renderCommand renders what the terminal showed.
+var renderCommand = &cobra.Command{
+	Use:   "render FILE",
+	Short: "Render a recorded log's screen as text or an animated SVG.",
+	Long: `Replays a recorded log on a virtual terminal to show what the attacker saw.
+
+The text format prints the final screen, or a snapshot of the screen at most
+every --every if it's set. The svg format renders an animated SVG at --fps
+frames per second with pauses capped by --idle-time-limit.`,
+	Example: `honeyssh logs render path/to/some.cast
+honeyssh logs render --every 1m path/to/some.cast
+honeyssh logs render --format svg -o session.svg path/to/some.cast`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		fd, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer fd.Close()
+		source := createLogSource(args[0], fd)
+
+		var interval time.Duration
+		switch renderFormat {
+		case renderFormatText:
+			interval = renderEvery
+		case renderFormatSVG:
+			if renderFPS <= 0 {
+				return errors.New("--fps must be greater than zero")
+			}
+			interval = time.Duration(float64(time.Second) / renderFPS)
+		default:
+			return fmt.Errorf("unknown render format %q", renderFormat)
+		}
+
+		frames, err := ttylog.RenderFrames(source, interval)
+		if err != nil {
+			return err
+		}
+
+		var w io.Writer = cmd.OutOrStdout()
+		if renderOutput != "" && renderOutput != "-" {
+			outFd, err := os.Create(renderOutput)
+			if err != nil {
+				return err
+			}
+			defer outFd.Close()
+			w = outFd
+		}
+
+		switch {
+		case renderFormat == renderFormatSVG:
+			return ttylog.WriteSVG(w, frames, idleTimeLimit)
+		case len(frames) == 0:
+			return nil
+		case renderEvery == 0:
+			_, err := fmt.Fprintln(w, frames[len(frames)-1].Screen)
+			return err
+		default:
+			for _, frame := range frames {
+				timestamp := time.UnixMicro(frame.TimestampMicros).UTC().Format(time.RFC3339)
+				if _, err := fmt.Fprintf(w, "--- %s ---\n%s\n\n", timestamp, frame.Screen); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	},
+}
+
+// Render formats.
+const (
+	renderFormatText = "text"
+	renderFormatSVG  = "svg"
+)
+
+// convertCmd converts logs between formats.
+var convertCmd = &cobra.Command{
+	Use:   "convert INPUT OUTPUT",
+	Short: "Convert a log between asciicast, UML, Cowrie and protobuf formats.",
+	Long: `Convert a recorded terminal log between formats. Formats are picked by file
+extension: .cast is asciicast (asciinema), .ttylog is protobuf, .gz is a
+compressed Cowrie log and anything else is UML (Kippo and Cowrie). Use - as the
+OUTPUT to write to stdout, asciicast is written unless --to is set.
+
+With --redact, text matching the redaction section of config.yaml is masked
+before it's written, including the honeytoken values of the session that
+recorded INPUT.`,
+	Example: `honeyssh logs convert path/to/some.log out.cast
+honeyssh logs convert --to ttylog path/to/some.cast - > out.ttylog
+honeyssh logs convert --redact session_logs/some.cast shared.cast`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		in, out := args[0], args[1]
+
+		fromFormat := logFormat(in)
+		if convertFrom != "" {
+			fromFormat = convertFrom
+		}
+		toFormat := logFormat(out)
+		if out == "-" {
+			toFormat = logFormatAsciicast
+		}
+		if convertTo != "" {
+			toFormat = convertTo
+		}
+
+		fd, err := os.Open(in)
+		if err != nil {
+			return err
+		}
+		defer fd.Close()
+		source, err := newLogSource(fromFormat, fd)
+		if err != nil {
+			return err
+		}
+
+		var w io.Writer = cmd.OutOrStdout()
+		if out != "-" {
+			outFd, err := os.Create(out)
+			if err != nil {
+				return err
+			}
+			defer outFd.Close()
+			w = outFd
+		}
+		sink, closeSink, err := newLogSink(toFormat, w)
+		if err != nil {
+			return err
+		}
+		if convertRedact {
+			config, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			redactor, err := newRedactor(config, ttyLogSessionID(config, filepath.Base(in)))
+			if err != nil {
+				return err
+			}
+			sink = ttylog.NewRedactingAdapter(redactor, sink)
+		}
+
+		if err := ttylog.Replay(source, applyMiddleware(sink)); err != nil {
+			return err
+		}
+		return closeSink()
+	},
+}
+
+// ttyLogSessionID returns the ID of the session that recorded the named log,
+// it's empty if the recording isn't in the app log.
+func ttyLogSessionID(config *config.Configuration, name string) string {
+	fd, err := config.ReadAppLog()
+	if err != nil {
+		return ""
+	}
+	defer fd.Close()
+
+	var sessionID string
+	logger.ReadJSONLinesLog(fd, func(le *logger.LogEntry) {
+		if le.GetOpenTtyLog().GetName() == name {
+			sessionID = le.GetSessionId()
+		}
+	})
+	return sessionID
+}
+
+// Log formats.
+const (
+	logFormatAsciicast = "asciicast"
+	logFormatProto     = "ttylog"
+	logFormatUML       = "uml"
+	logFormatCowrie    = "cowrie"
+)
+
+// logFormat returns the format of the log based on its name.
+func logFormat(name string) string {
 	switch strings.TrimPrefix(filepath.Ext(name), ".") {
 	case ttylog.AsciicastFileExt:
-		return ttylog.NewAsciicastLogSource(r)
+		return logFormatAsciicast
+	case ttylog.ProtoFileExt:
+		return logFormatProto
+	case ttylog.CowrieFileExt:
+		return logFormatCowrie
 	default:
-		return ttylog.NewUMLLogSource(r)
+		return logFormatUML
 	}
 }
 
+func createLogSource(name string, r io.Reader) ttylog.LogSource {
+	// Formats from names are always valid.
+	source, _ := newLogSource(logFormat(name), r)
+	return source
+}
+
+func newLogSource(format string, r io.Reader) (ttylog.LogSource, error) {
+	switch format {
+	case logFormatAsciicast:
+		return ttylog.NewAsciicastLogSource(r), nil
+	case logFormatProto:
+		return ttylog.NewProtoLogSource(r), nil
+	case logFormatUML:
+		return ttylog.NewUMLLogSource(r), nil
+	case logFormatCowrie:
+		return ttylog.NewCowrieLogSource(r), nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q", format)
+	}
+}
+
+// newLogSink creates a sink for the format, closeSink must be called after the
+// last entry is written.
+func newLogSink(format string, w io.Writer) (sink ttylog.LogSink, closeSink func() error, err error) {
+	noClose := func() error { return nil }
+	switch format {
+	case logFormatAsciicast:
+		return ttylog.NewAsciicastLogSink(w), noClose, nil
+	case logFormatProto:
+		writer := ttylog.NewProtoLogWriter(w, true)
+		return writer.Write, writer.Close, nil
+	case logFormatUML:
+		return ttylog.NewUMLLogSink(w), noClose, nil
+	case logFormatCowrie:
+		gzipWriter := gzip.NewWriter(w)
+		return ttylog.NewUMLLogSink(gzipWriter), gzipWriter.Close, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown log format %q", format)
+	}
+}
+
+// parseFilterFlags validates the flags used by applyMiddleware.
+func parseFilterFlags(cmd *cobra.Command, args []string) error {
+	if stdinOnly && stdoutOnly {
+		return errors.New("can't supply both stdin-only and stdout-only")
+	}
+	if windowTo > 0 && windowTo < windowFrom {
+		return errors.New("to must be after from")
+	}
+
+	grepRegexp = nil
+	if grepPattern != "" {
+		var err error
+		if grepRegexp, err = regexp.Compile(grepPattern); err != nil {
+			return fmt.Errorf("couldn't parse grep pattern: %v", err)
+		}
+	}
+	return nil
+}
+
+// newClientSink writes what the terminal showed to w, or the keystrokes if
+// --stdin-only is set.
+func newClientSink(w io.Writer) ttylog.LogSink {
+	if stdinOnly {
+		return ttylog.NewClientInput(w)
+	}
+	return ttylog.NewClientOutput(w)
+}
+
 func applyMiddleware(sink ttylog.LogSink) ttylog.LogSink {
+	if stripANSI {
+		sink = ttylog.NewStripANSIAdapter(sink)
+	}
+	switch {
+	case stdinOnly:
+		sink = ttylog.NewFDFilterAdapter([]ttylog.FD{ttylog.FD_STDIN}, sink)
+	case stdoutOnly:
+		sink = ttylog.NewFDFilterAdapter([]ttylog.FD{ttylog.FD_STDOUT, ttylog.FD_STDERR}, sink)
+	}
+	if grepRegexp != nil {
+		sink = ttylog.NewSeekToMatchAdapter(grepRegexp, sink)
+	}
+	if windowFrom > 0 || windowTo > 0 {
+		sink = ttylog.NewTimeWindowAdapter(windowFrom, windowTo, sink)
+	}
 	if fixKippoQuirks {
 		sink = ttylog.NewKippoQuirksAdapter(sink)
 	}
@@ -102,15 +495,38 @@ func applyMiddleware(sink ttylog.LogSink) ttylog.LogSink {
 func init() {
 	rootCmd.AddCommand(logsCmd)
 	logsCmd.AddCommand(playCommand)
-	logsCmd.AddCommand(asciicastCmd)
+	logsCmd.AddCommand(convertCmd)
 	logsCmd.AddCommand(catCommand)
+	logsCmd.AddCommand(keystrokesCommand)
+	logsCmd.AddCommand(renderCommand)
 
-	for _, cmd := range []*cobra.Command{playCommand, asciicastCmd, catCommand} {
+	for _, cmd := range []*cobra.Command{playCommand, convertCmd, catCommand} {
 		cmd.Flags().BoolVar(&fixKippoQuirks, "fix-kippo", false, "Apply fixes to logs produced by Kippo.")
 	}
 
+	formats := strings.Join([]string{logFormatAsciicast, logFormatProto, logFormatUML, logFormatCowrie}, ", ")
+	convertCmd.Flags().StringVar(&convertFrom, "from", "", "Format of INPUT, one of: "+formats+". Detected from the name if empty.")
+	convertCmd.Flags().StringVar(&convertTo, "to", "", "Format of OUTPUT, one of: "+formats+". Detected from the name if empty.")
+	convertCmd.Flags().BoolVar(&convertRedact, "redact", false, "Mask the patterns and honeytokens configured in redaction.")
+
+	for _, cmd := range []*cobra.Command{playCommand, catCommand} {
+		cmd.Flags().DurationVar(&windowFrom, "from", 0, "Start this far into the log. (e.g. 90s, 2m)")
+		cmd.Flags().DurationVar(&windowTo, "to", 0, "Stop this far into the log, the end if zero.")
+		cmd.Flags().BoolVar(&stdinOnly, "stdin-only", false, "Only show keystrokes.")
+		cmd.Flags().BoolVar(&stdoutOnly, "stdout-only", false, "Only show output, stdout and stderr.")
+		cmd.Flags().StringVar(&grepPattern, "grep", "", "Start at the first output matching this regular expression.")
+	}
+	catCommand.Flags().BoolVar(&stripANSI, "strip-ansi", false, "Remove escape sequences and control characters for plain text export.")
+	catCommand.Flags().BoolVar(&jsonOutput, "json", false, "Print each log entry as a line of JSON.")
+
 	// cat doesn't allow idle time
-	for _, cmd := range []*cobra.Command{playCommand} {
+	for _, cmd := range []*cobra.Command{playCommand, renderCommand} {
 		cmd.Flags().DurationVarP(&idleTimeLimit, "idle-time-limit", "i", 3*time.Second, "Maximum time output can be idle. (e.g. 3s, 2m, 100ms)")
 	}
+	playCommand.Flags().BoolVar(&interactive, "interactive", false, "Control playback from the keyboard.")
+	renderCommand.Flags().StringVar(&renderFormat, "format", renderFormatText, "Output format, one of: "+renderFormatText+", "+renderFormatSVG+".")
+	renderCommand.Flags().DurationVar(&renderEvery, "every", 0, "Print a text snapshot at most this often rather than only the final screen.")
+	renderCommand.Flags().Float64Var(&renderFPS, "fps", 10, "Frames per second of SVG animations.")
+	renderCommand.Flags().StringVarP(&renderOutput, "output", "o", "-", "File to write to, - for stdout.")
+	playCommand.Flags().DurationVar(&jumpDuration, "jump", 10*time.Second, "How far to jump forward or back in interactive mode.")
 }
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/josephlewis42/honeyssh/core/persona"
+	"github.com/spf13/cobra"
+)
+
+var personasCmd = &cobra.Command{
+	Use:     "personas",
+	Aliases: []string{"persona"},
+	Short:   "Explore the machines the honeypot can imitate.",
+}
+
+var personasLsCommand = &cobra.Command{
+	Use:   "ls",
+	Short: "List the built-in personas.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tDESCRIPTION")
+		for _, name := range persona.BuiltinNames() {
+			p, err := persona.Builtin(name)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "%s\t%s\n", p.Name, p.Description)
+		}
+		return w.Flush()
+	},
+}
+
+var personasShowCommand = &cobra.Command{
+	Use:   "show NAME",
+	Short: "Print the YAML of a built-in persona.",
+	Long: `Print the YAML of a built-in persona. Save it as personas/<name>.yaml in
+the configuration directory and edit it to make a custom persona.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		data, err := persona.BuiltinYAML(args[0])
+		if err != nil {
+			return err
+		}
+		_, err = cmd.OutOrStdout().Write(data)
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(personasCmd)
+	personasCmd.AddCommand(personasLsCommand)
+	personasCmd.AddCommand(personasShowCommand)
+}
@@ -16,7 +16,12 @@ import (
 var serveCmd = &cobra.Command{
 	Use:   "serve",
 	Short: "Start the honeypot on a local port.",
-	Long:  ``,
+	Long: `Start the honeypot on a local port.
+
+Send SIGHUP to reload config.yaml without dropping sessions, new connections
+use the new configuration and running sessions keep theirs. If the new
+configuration isn't valid the running one is kept. Some settings, like
+network and honeytokens, are only read at startup and need a restart.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		os.Stdin.Close()
 		cmd.SilenceUsage = true
@@ -44,8 +49,14 @@ var serveCmd = &cobra.Command{
 		sigs := make(chan os.Signal, 1)
 
 		log.Println("- Starting interrupt handler")
-		signal.Notify(sigs, os.Interrupt, syscall.SIGINT, syscall.SIGTERM, syscall.SIGKILL)
+		signal.Notify(sigs, os.Interrupt, syscall.SIGINT, syscall.SIGTERM, syscall.SIGKILL, syscall.SIGHUP)
 		sig := <-sigs
+		for sig == syscall.SIGHUP {
+			// Failures are logged and the running configuration is kept.
+			log.Println("Got SIGHUP, reloading configuration...")
+			honeypot.Reload()
+			sig = <-sigs
+		}
 		log.Printf("Got signal %q, terminating...", sig)
 
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
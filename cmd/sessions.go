@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/josephlewis42/honeyssh/core/monitor"
+	"github.com/josephlewis42/honeyssh/core/ttylog"
+	"github.com/spf13/cobra"
+)
+
+var sessionsCmd = &cobra.Command{
+	Use:     "sessions",
+	Aliases: []string{"session"},
+	Short:   "Watch and control active sessions.",
+	Long: `Watch and control the active sessions of a running honeypot.
+
+The honeypot must have monitor.enabled set in its configuration and these
+commands must be run as the same user as the honeypot.`,
+}
+
+var sessionsLsCommand = &cobra.Command{
+	Use:   "ls",
+	Short: "List active sessions.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		socketPath, err := monitorSocketPath()
+		if err != nil {
+			return err
+		}
+
+		sessions, err := monitor.List(socketPath)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tUSER\tREMOTE\tSTARTED\tWATCHERS")
+		for _, session := range sessions {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\n",
+				session.ID,
+				session.User,
+				session.RemoteAddr,
+				session.StartTime.UTC().Format(time.RFC3339),
+				session.Watchers,
+			)
+		}
+		return w.Flush()
+	},
+}
+
+var sessionsWatchCommand = &cobra.Command{
+	Use:   "watch ID",
+	Short: "Watch an active session in real time.",
+	Long: `Watch an active session in real time, starting with its recent output.
+Watching stops when the session ends.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		socketPath, err := monitorSocketPath()
+		if err != nil {
+			return err
+		}
+
+		sink := ttylog.NewClientOutput(cmd.OutOrStdout())
+		sink = ttylog.NewClientResize(cmd.OutOrStdout(), sink)
+		return monitor.Watch(socketPath, args[0], sink)
+	},
+}
+
+var sessionsWallCommand = &cobra.Command{
+	Use:   "wall ID MESSAGE...",
+	Short: "Send a broadcast message to an active session.",
+	Long: `Send a message to an active session's terminal formatted like wall(1).
+The honeypot must have monitor.allow_control set in its configuration.`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		socketPath, err := monitorSocketPath()
+		if err != nil {
+			return err
+		}
+
+		return monitor.Wall(socketPath, args[0], strings.Join(args[1:], " "))
+	},
+}
+
+var sessionsKillCommand = &cobra.Command{
+	Use:   "kill ID",
+	Short: "End an active session.",
+	Long: `End an active session. The honeypot must have monitor.allow_control set
+in its configuration.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		socketPath, err := monitorSocketPath()
+		if err != nil {
+			return err
+		}
+
+		return monitor.Kill(socketPath, args[0])
+	},
+}
+
+func monitorSocketPath() (string, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return "", err
+	}
+
+	return config.MonitorSocketPath()
+}
+
+func init() {
+	rootCmd.AddCommand(sessionsCmd)
+	sessionsCmd.AddCommand(sessionsLsCommand)
+	sessionsCmd.AddCommand(sessionsWatchCommand)
+	sessionsCmd.AddCommand(sessionsWallCommand)
+	sessionsCmd.AddCommand(sessionsKillCommand)
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+
+	"github.com/josephlewis42/honeyssh/core/config"
+	"github.com/josephlewis42/honeyssh/core/integrity"
+	"github.com/josephlewis42/honeyssh/core/payload"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+var (
+	verifyPublicKey      string
+	verifyPrintPublicKey bool
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check session logs, downloads and the app log for tampering.",
+	Long: `Checks the signatures of session logs and downloads and the hash chain of
+app.log. Evidence that was changed, removed or inserted after it was written
+is reported as tampered and the command fails. Evidence written while signing
+was disabled is reported as unsigned, except for lines at the end of app.log
+after its chain which are reported as tampered.
+
+Ed25519 signatures can be checked away from the sensor with only the public
+key, print it with --print-public-key and pass it with --public-key.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		configuration, err := loadConfig()
+		if err != nil {
+			return err
+		}
+
+		var key *integrity.Key
+		if verifyPublicKey != "" {
+			public, err := hex.DecodeString(verifyPublicKey)
+			if err != nil {
+				return fmt.Errorf("couldn't parse public key: %v", err)
+			}
+			key, err = integrity.NewPublicKey(public)
+			if err != nil {
+				return err
+			}
+		} else {
+			key, err = configuration.IntegrityKey()
+			if err != nil {
+				return err
+			}
+		}
+
+		out := cmd.OutOrStdout()
+		if verifyPrintPublicKey {
+			if key.PublicKey() == nil {
+				return fmt.Errorf("%s keys have no public key", key.Method())
+			}
+			fmt.Fprintln(out, hex.EncodeToString(key.PublicKey()))
+			return nil
+		}
+
+		results := &verifyResults{out: out}
+		verifyAppLog(configuration, key, results)
+		results.verifyFiles(configuration.SessionLogFs(), config.LogsDirName, key, func(name string) bool {
+			return !integrity.IsSignature(name)
+		})
+		results.verifyFiles(configuration.DownloadFs(), config.DownloadDirName, key, func(name string) bool {
+			return path.Base(name) == payload.PayloadName
+		})
+
+		fmt.Fprintf(out, "%d ok, %d unsigned, %d tampered\n", results.ok, results.unsigned, results.tampered)
+		if results.tampered > 0 {
+			return errors.New("evidence failed verification")
+		}
+		return nil
+	},
+}
+
+// verifyResults tallies and prints verification results.
+type verifyResults struct {
+	out                    io.Writer
+	ok, unsigned, tampered int
+}
+
+// add records the result of verifying the named evidence.
+func (r *verifyResults) add(name string, err error) {
+	switch {
+	case err == nil:
+		r.ok++
+		return
+	case errors.Is(err, integrity.ErrUnsigned):
+		r.unsigned++
+	default:
+		r.tampered++
+	}
+	fmt.Fprintf(r.out, "%s: %v\n", name, err)
+}
+
+// verifyFiles checks the signature of each file in fsys that matches.
+func (r *verifyResults) verifyFiles(fsys afero.Fs, dir string, key *integrity.Key, match func(name string) bool) {
+	err := afero.Walk(fsys, ".", func(name string, info fs.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !match(name) {
+			return err
+		}
+		r.add(path.Join(dir, name), integrity.VerifyFile(fsys, name, key))
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		r.add(dir, err)
+	}
+}
+
+func verifyAppLog(configuration *config.Configuration, key *integrity.Key, results *verifyResults) {
+	logFd, err := configuration.ReadAppLog()
+	if errors.Is(err, fs.ErrNotExist) {
+		return
+	}
+	if err != nil {
+		results.add(config.AppLogName, err)
+		return
+	}
+	defer logFd.Close()
+
+	chainFd, err := configuration.ReadAppLogChain()
+	if errors.Is(err, fs.ErrNotExist) {
+		results.add(config.AppLogName, integrity.ErrUnsigned)
+		return
+	}
+	if err != nil {
+		results.add(config.AppLogName, err)
+		return
+	}
+	defer chainFd.Close()
+
+	report, err := integrity.VerifyChain(logFd, chainFd, key)
+	results.add(config.AppLogName, err)
+	if err == nil && report.Unsigned > 0 {
+		fmt.Fprintf(results.out, "%s: %v: %d of %d lines\n", config.AppLogName, integrity.ErrUnsigned, report.Unsigned, report.Lines)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+
+	verifyCmd.Flags().StringVar(&verifyPublicKey, "public-key", "", "Hex encoded Ed25519 public key to verify with instead of integrity_key.")
+	verifyCmd.Flags().BoolVar(&verifyPrintPublicKey, "print-public-key", false, "Print the Ed25519 public key of integrity_key and exit.")
+}
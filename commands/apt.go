@@ -5,6 +5,7 @@ import (
 	"math/rand"
 	"time"
 
+	"github.com/josephlewis42/honeyssh/core/persona"
 	"github.com/josephlewis42/honeyssh/core/vos"
 )
 
@@ -19,6 +20,10 @@ func Apt(virtOS vos.VOS) int {
 	}
 
 	return cmd.Run(virtOS, func() int {
+		if virtOS.Persona().Distro.PackageManager != persona.PackageManagerDpkg {
+			return commandNotFound(virtOS)
+		}
+
 		w := virtOS.Stdout()
 		switch {
 		case len(cmd.Flags().Args()) == 0:
@@ -84,6 +84,13 @@ func mustAddSbinCmd(name string, cmd vos.ProcessFunc) {
 	}
 }
 
+// commandNotFound mimics the shell's error for commands the persona doesn't
+// have.
+func commandNotFound(virtOS vos.VOS) int {
+	fmt.Fprintf(virtOS.Stderr(), "sh: %s: command not found\n", virtOS.Args()[0])
+	return 127
+}
+
 func BytesToHuman(bytes int64) string {
 	for _, e := range []struct {
 		unit  string
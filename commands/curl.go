@@ -136,7 +136,7 @@ func Curl(virtOS vos.VOS) int {
 			}
 		}
 
-		response, err := wgetHTTPClient.Do(request)
+		response, err := httpClient(virtOS).Do(request)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/josephlewis42/honeyssh/core/vos"
+)
+
+// Df implements the df command.
+func Df(virtOS vos.VOS) int {
+	cmd := &SimpleCommand{
+		Use:   "df [OPTION]... [FILE]...",
+		Short: "Show information about the file system on which each FILE resides, or all file systems by default.",
+
+		// Never bail, even if args are bad.
+		NeverBail: true,
+	}
+
+	humanSize := cmd.Flags().BoolLong("human-readable", 'h', "print sizes in powers of 1024 (e.g., 1023M)")
+	cmd.ShowHelp = cmd.Flags().BoolLong("help", '?', "show help and exit")
+
+	return cmd.Run(virtOS, func() int {
+		w := virtOS.Stdout()
+		p := virtOS.Persona()
+
+		size := func(kb int64) string {
+			return strconv.FormatInt(kb, 10)
+		}
+		sizeHeader := "1K-blocks"
+		if *humanSize {
+			size = freeHumanSize
+			sizeHeader = "Size"
+		}
+
+		// GNU df widens the first column to fit, BusyBox's is fixed.
+		format := "%-20s %9s %9s %9s %4s %s\n"
+		if !p.IsBusyBox() {
+			width := len("Filesystem")
+			for _, disk := range p.Disks {
+				width = max(width, len(disk.Device))
+			}
+			format = fmt.Sprintf("%%-%ds %%9s %%9s %%9s %%4s %%s\n", width)
+		}
+
+		fmt.Fprintf(w, format, "Filesystem", sizeHeader, "Used", "Available", "Use%", "Mounted on")
+		for _, disk := range p.Disks {
+			usePercent := "-"
+			if total := disk.UsedKB + disk.AvailableKB(); total > 0 {
+				// df rounds up.
+				usePercent = fmt.Sprintf("%d%%", (disk.UsedKB*100+total-1)/total)
+			}
+			fmt.Fprintf(w, format,
+				disk.Device,
+				size(disk.SizeKB),
+				size(disk.UsedKB),
+				size(disk.AvailableKB()),
+				usePercent,
+				disk.MountPoint)
+		}
+		return 0
+	})
+}
+
+var _ vos.ProcessFunc = Df
+
+func init() {
+	mustAddBinCmd("df", Df)
+}
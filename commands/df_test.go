@@ -0,0 +1,15 @@
+package commands
+
+import (
+	"testing"
+)
+
+func TestDf(t *testing.T) {
+	cases := goldenTestSuite{
+		"no-arg": {[]string{"df"}},
+		"help":   {[]string{"df", "--help"}},
+		"human":  {[]string{"df", "-h"}},
+	}
+
+	cases.Run(t, Df)
+}
@@ -2,6 +2,7 @@ package commands
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/josephlewis42/honeyssh/core/vos"
 )
@@ -21,23 +22,52 @@ func Free(virtOS vos.VOS) int {
 
 	return cmd.Run(virtOS, func() int {
 		w := virtOS.Stdout()
+		mem := virtOS.Persona().Memory
 
+		size := func(kb int64) string {
+			return strconv.FormatInt(kb, 10)
+		}
 		if *humanSize {
-			fmt.Fprintln(w,
-				`              total        used        free      shared  buff/cache   available
-Mem:           7.2G        4.1G        1.8G        623M        1.3G        2.2G
-Swap:           23G        4.1G         19G`)
-
-		} else {
-			fmt.Fprintln(w,
-				`              total        used        free      shared  buff/cache   available
-Mem:        7596572     4387812     1215572      730612     1993188     2171992
-Swap:      24587768     4301240    20286528`)
+			size = freeHumanSize
 		}
+
+		fmt.Fprintln(w, "              total        used        free      shared  buff/cache   available")
+		fmt.Fprintf(w, "%-5s%14s%12s%12s%12s%12s%12s\n",
+			"Mem:",
+			size(mem.TotalKB),
+			size(mem.UsedKB()),
+			size(mem.FreeKB),
+			size(mem.SharedKB),
+			size(mem.BuffersKB+mem.CachedKB),
+			size(mem.AvailableKB))
+		fmt.Fprintf(w, "%-5s%14s%12s%12s\n",
+			"Swap:",
+			size(mem.SwapTotalKB),
+			size(mem.SwapUsedKB()),
+			size(mem.SwapFreeKB))
 		return 0
 	})
 }
 
+// freeHumanSize formats a size in KiB with three significant digits e.g.
+// "7.2G" or "623M".
+func freeHumanSize(kb int64) string {
+	if kb == 0 {
+		return "0B"
+	}
+	value := float64(kb)
+	for _, unit := range []string{"K", "M", "G", "T"} {
+		switch {
+		case value < 10:
+			return fmt.Sprintf("%0.1f%s", value, unit)
+		case value < 1024:
+			return fmt.Sprintf("%0.f%s", value, unit)
+		}
+		value /= 1024
+	}
+	return fmt.Sprintf("%0.fP", value)
+}
+
 var _ vos.ProcessFunc = Free
 
 func init() {
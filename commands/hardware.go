@@ -0,0 +1,148 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/josephlewis42/honeyssh/core/persona"
+	"github.com/josephlewis42/honeyssh/core/vos"
+)
+
+// Lscpu implements the lscpu command.
+func Lscpu(virtOS vos.VOS) int {
+	cmd := &SimpleCommand{
+		Use:   "lscpu [OPTION...]",
+		Short: "Display information about the CPU architecture.",
+
+		// Never bail, even if args are bad.
+		NeverBail: true,
+	}
+
+	return cmd.Run(virtOS, func() int {
+		p := virtOS.Persona()
+		if p.IsBusyBox() {
+			return commandNotFound(virtOS)
+		}
+
+		w := virtOS.Stdout()
+		cpu := p.CPU
+		field := func(name string, value interface{}) {
+			fmt.Fprintf(w, "%-25s%v\n", name+":", value)
+		}
+
+		opModes := "32-bit"
+		if p.Is64Bit() {
+			opModes = "32-bit, 64-bit"
+		}
+		online := "0"
+		if cpu.Cores > 1 {
+			online = fmt.Sprintf("0-%d", cpu.Cores-1)
+		}
+
+		field("Architecture", virtOS.Uname().Machine)
+		field("  CPU op-mode(s)", opModes)
+		field("  Byte Order", p.ByteOrder())
+		field("CPU(s)", cpu.Cores)
+		field("  On-line CPU(s) list", online)
+		if cpu.Vendor != "" {
+			field("Vendor ID", cpu.Vendor)
+		}
+		field("  Model name", cpu.ModelName)
+
+		if p.Arch() == persona.ArchX86 {
+			field("    CPU family", cpu.Family)
+			field("    Model", cpu.Model)
+			field("    Thread(s) per core", 1)
+			field("    Core(s) per socket", cpu.Cores)
+			field("    Socket(s)", 1)
+			field("    Stepping", cpu.Stepping)
+		} else {
+			field("    Model", cpu.Stepping)
+			field("    Thread(s) per core", 1)
+			field("    Core(s) per cluster", cpu.Cores)
+			field("    Socket(s)", "-")
+			field("    Cluster(s)", 1)
+			field("    Stepping", fmt.Sprintf("r0p%d", cpu.Stepping))
+		}
+		if cpu.MHz > 0 {
+			field("    CPU max MHz", fmt.Sprintf("%0.4f", cpu.MHz))
+		}
+		field("    BogoMIPS", fmt.Sprintf("%0.2f", cpu.BogoMIPS))
+		field("    Flags", strings.Join(cpu.Flags, " "))
+
+		if cpu.Hypervisor != "" {
+			field("Virtualization features", "")
+			field("  Hypervisor vendor", cpu.Hypervisor)
+			field("  Virtualization type", "full")
+		}
+		if cpu.CacheSizeKB > 0 {
+			cacheSize := fmt.Sprintf("%d KiB", cpu.CacheSizeKB)
+			if cpu.CacheSizeKB%1024 == 0 {
+				cacheSize = fmt.Sprintf("%d MiB", cpu.CacheSizeKB/1024)
+			}
+			field("Caches (sum of all)", "")
+			field("  L3", cacheSize+" (1 instance)")
+		}
+		field("NUMA", "")
+		field("  NUMA node(s)", 1)
+		field("  NUMA node0 CPU(s)", online)
+		return 0
+	})
+}
+
+// Lspci implements the lspci command.
+func Lspci(virtOS vos.VOS) int {
+	cmd := &SimpleCommand{
+		Use:   "lspci [OPTION...]",
+		Short: "List PCI devices.",
+
+		// Never bail, even if args are bad.
+		NeverBail: true,
+	}
+
+	return cmd.Run(virtOS, func() int {
+		p := virtOS.Persona()
+		if p.IsBusyBox() {
+			return commandNotFound(virtOS)
+		}
+		printLines(virtOS.Stdout(), p.PCIDevices)
+		return 0
+	})
+}
+
+// Lsusb implements the lsusb command.
+func Lsusb(virtOS vos.VOS) int {
+	cmd := &SimpleCommand{
+		Use:   "lsusb [OPTION...]",
+		Short: "List USB devices.",
+
+		// Never bail, even if args are bad.
+		NeverBail: true,
+	}
+
+	return cmd.Run(virtOS, func() int {
+		p := virtOS.Persona()
+		if p.IsBusyBox() {
+			return commandNotFound(virtOS)
+		}
+		printLines(virtOS.Stdout(), p.USBDevices)
+		return 0
+	})
+}
+
+func printLines(w io.Writer, lines []string) {
+	for _, line := range lines {
+		fmt.Fprintln(w, line)
+	}
+}
+
+var _ vos.ProcessFunc = Lscpu
+var _ vos.ProcessFunc = Lspci
+var _ vos.ProcessFunc = Lsusb
+
+func init() {
+	mustAddBinCmd("lscpu", Lscpu)
+	mustAddBinCmd("lspci", Lspci)
+	mustAddBinCmd("lsusb", Lsusb)
+}
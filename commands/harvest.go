@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/josephlewis42/honeyssh/core/config"
+	"github.com/josephlewis42/honeyssh/core/vos"
+)
+
+// NewHarvestFetcher creates a fetcher for URLs found in captured scripts. It
+// uses the same SSRF protections as wget unless the configuration allows
+// private addresses.
+func NewHarvestFetcher(cfg config.Harvest) vos.Fetcher {
+	client := wgetHTTPClient
+	if cfg.AllowPrivateAddresses {
+		client = newWgetHTTPClient(nil)
+	}
+
+	return func(ctx context.Context, rawURL string) (io.ReadCloser, error) {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		request.Header.Set("User-Agent", "Wget/1.20.2")
+
+		response, err := client.Do(request)
+		if err != nil {
+			return nil, err
+		}
+		if response.StatusCode != http.StatusOK {
+			response.Body.Close()
+			return nil, fmt.Errorf("unexpected status: %s", response.Status)
+		}
+		return response.Body, nil
+	}
+}
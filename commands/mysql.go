@@ -0,0 +1,108 @@
+package commands
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/josephlewis42/honeyssh/core/logger"
+	"github.com/josephlewis42/honeyssh/core/vos"
+)
+
+// mysqlOptions holds the connection options given to mysql.
+type mysqlOptions struct {
+	user     string
+	host     string
+	port     string
+	password *string
+	prompt   bool
+}
+
+// parseMysqlArgs parses mysql's arguments. They're parsed by hand because
+// -p takes an optional attached value and -h is the host rather than help.
+func parseMysqlArgs(args []string) (opts mysqlOptions) {
+	opts.host = "localhost"
+	opts.port = "3306"
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		next := func() string {
+			if i+1 < len(args) {
+				i++
+				return args[i]
+			}
+			return ""
+		}
+
+		switch {
+		case arg == "-p" || arg == "--password":
+			opts.prompt = true
+		case strings.HasPrefix(arg, "--password="):
+			password := strings.TrimPrefix(arg, "--password=")
+			opts.password = &password
+		case strings.HasPrefix(arg, "-p"):
+			password := strings.TrimPrefix(arg, "-p")
+			opts.password = &password
+		case arg == "-u" || arg == "--user":
+			opts.user = next()
+		case strings.HasPrefix(arg, "--user="):
+			opts.user = strings.TrimPrefix(arg, "--user=")
+		case strings.HasPrefix(arg, "-u"):
+			opts.user = strings.TrimPrefix(arg, "-u")
+		case arg == "-h" || arg == "--host":
+			opts.host = next()
+		case strings.HasPrefix(arg, "--host="):
+			opts.host = strings.TrimPrefix(arg, "--host=")
+		case strings.HasPrefix(arg, "-h"):
+			opts.host = strings.TrimPrefix(arg, "-h")
+		case arg == "-P" || arg == "--port":
+			opts.port = next()
+		case strings.HasPrefix(arg, "--port="):
+			opts.port = strings.TrimPrefix(arg, "--port=")
+		case strings.HasPrefix(arg, "-P"):
+			opts.port = strings.TrimPrefix(arg, "-P")
+		}
+	}
+	return opts
+}
+
+// Mysql implements a fake MySQL client that is always denied access.
+func Mysql(virtOS vos.VOS) int {
+	opts := parseMysqlArgs(virtOS.Args()[1:])
+	if opts.user == "" {
+		opts.user = currentUser(virtOS)
+	}
+
+	if opts.prompt {
+		password, err := readPassword(virtOS, "Enter password: ")
+		if err != nil {
+			return 1
+		}
+		opts.password = &password
+	}
+
+	usingPassword := "NO"
+	if opts.password != nil {
+		usingPassword = "YES"
+		virtOS.LogCreds(&logger.Credentials{
+			Username: opts.user,
+			Password: *opts.password,
+			Target:   (&url.URL{Scheme: "mysql", Host: net.JoinHostPort(opts.host, opts.port)}).String(),
+		})
+	}
+
+	switch opts.host {
+	case "localhost", "127.0.0.1", "::1":
+		fmt.Fprintf(virtOS.Stderr(), "ERROR 1045 (28000): Access denied for user '%s'@'localhost' (using password: %s)\n", opts.user, usingPassword)
+	default:
+		fmt.Fprintf(virtOS.Stderr(), "ERROR 2003 (HY000): Can't connect to MySQL server on '%s:%s' (111)\n", opts.host, opts.port)
+	}
+	return 1
+}
+
+var _ vos.ProcessFunc = Mysql
+
+func init() {
+	mustAddBinCmd("mysql", Mysql)
+}
@@ -2,61 +2,16 @@ package commands
 
 import (
 	"fmt"
+	"io"
+	"net"
+	"sort"
 	"strings"
 
+	"github.com/josephlewis42/honeyssh/core/persona"
 	"github.com/josephlewis42/honeyssh/core/vos"
 )
 
 var (
-	// The commands here have been modified to be roughly consistent with respect
-	// to IP and MAC addresses.
-	ifconfigText = strings.TrimSpace(`
-lo: flags=73<UP,LOOPBACK,RUNNING>  mtu 65536
-        inet 127.0.0.1  netmask 255.0.0.0
-        inet6 ::1  prefixlen 128  scopeid 0x10<host>
-        loop  txqueuelen 1000  (Local Loopback)
-        RX packets 687175  bytes 67648738 (67.6 MB)
-        RX errors 0  dropped 0  overruns 0  frame 0
-        TX packets 687175  bytes 67648738 (67.6 MB)
-        TX errors 0  dropped 0 overruns 0  carrier 0  collisions 0
-
-ens4: flags=4163<BROADCAST,MULTICAST,UP,LOWER_UP>  mtu 1500
-        inet 10.128.0.2   netmask 255.255.255.0  broadcast 10.128.0.2
-        inet6 fe80::4001:aff:fe80:2  prefixlen 64  scopeid 0x20<link>
-        ether 42:01:0a:80:00:02  txqueuelen 1000  (Ethernet)
-        RX packets 44923709  bytes 57490779806 (57.4 GB)
-        RX errors 0  dropped 0  overruns 0  frame 0
-        TX packets 12923339  bytes 2665088356 (2.6 GB)
-        TX errors 0  dropped 0 overruns 0  carrier 0  collisions 0
-`)
-
-	ipAddress = strings.TrimSpace(`
-1: lo: <LOOPBACK,UP,LOWER_UP> mtu 65536 qdisc noqueue state UNKNOWN group default qlen 1000
-    link/loopback 00:00:00:00:00:00 brd 00:00:00:00:00:00
-    inet 127.0.0.1/8 scope host lo
-       valid_lft forever preferred_lft forever
-    inet6 ::1/128 scope host
-       valid_lft forever preferred_lft forever
-2: ens4: <BROADCAST,MULTICAST,UP,LOWER_UP> mtu 1460 qdisc mq state UP group default qlen 1000
-    link/ether 42:01:0a:80:00:02 brd ff:ff:ff:ff:ff:ff
-    inet 10.128.0.2/32 brd 10.128.0.2 scope global dynamic ens4
-       valid_lft 86099sec preferred_lft 86099sec
-    inet6 fe80::4001:aff:fe80:2/64 scope link
-       valid_lft forever preferred_lft forever
-`)
-
-	ipLink = strings.TrimSpace(`
-1: lo: <LOOPBACK,UP,LOWER_UP> mtu 65536 qdisc noqueue state UNKNOWN mode DEFAULT group default qlen 1000
-    link/loopback 00:00:00:00:00:00 brd 00:00:00:00:00:00
-2: ens4: <BROADCAST,MULTICAST,UP,LOWER_UP> mtu 1460 qdisc mq state UP mode DEFAULT group default qlen 1000
-    link/ether 42:01:0a:80:00:02 brd ff:ff:ff:ff:ff:ff
-`)
-
-	ipRoute = strings.TrimSpace(`
-default via 10.128.0.1 dev ens4
-10.128.0.1 dev ens4 scope link
-`)
-
 	ipRule = strings.TrimSpace(`
 0:      from all lookup local
 32766:  from all lookup main
@@ -76,68 +31,69 @@ prefix fc00::/7 label 5
 prefix ::/0 label 1
 `)
 
-	ipNtable = strings.TrimSpace(`
-inet arp_cache
-    thresh1 128 thresh2 512 thresh3 1024 gc_int 30000
-    refcnt 1 reachable 31176 base_reachable 30000 retrans 1000
-    gc_stale 60000 delay_probe 5000 queue 101
-    app_probes 0 ucast_probes 3 mcast_probes 3
-    anycast_delay 1000 proxy_delay 800 proxy_queue 64 locktime 1000
-
-inet arp_cache
-    dev ens4
-    refcnt 2 reachable 41140 base_reachable 30000 retrans 1000
-    gc_stale 60000 delay_probe 5000 queue 101
-    app_probes 0 ucast_probes 3 mcast_probes 3
-    anycast_delay 1000 proxy_delay 800 proxy_queue 64 locktime 1000
-
-inet arp_cache
-    dev lo
-    refcnt 2 reachable 35048 base_reachable 30000 retrans 1000
-    gc_stale 60000 delay_probe 5000 queue 101
-    app_probes 0 ucast_probes 3 mcast_probes 3
-    anycast_delay 1000 proxy_delay 800 proxy_queue 64 locktime 1000
-
-inet6 ndisc_cache
-    thresh1 128 thresh2 512 thresh3 1024 gc_int 30000
-    refcnt 1 reachable 35552 base_reachable 30000 retrans 1000
-    gc_stale 60000 delay_probe 5000 queue 101
-    app_probes 0 ucast_probes 3 mcast_probes 3
-    anycast_delay 1000 proxy_delay 800 proxy_queue 64 locktime 0
-
-inet6 ndisc_cache
-    dev ens4
-    refcnt 4 reachable 39848 base_reachable 30000 retrans 1000
-    gc_stale 60000 delay_probe 5000 queue 101
-    app_probes 0 ucast_probes 3 mcast_probes 3
-    anycast_delay 1000 proxy_delay 800 proxy_queue 64 locktime 0
-
-inet6 ndisc_cache
-    dev lo
-    refcnt 2 reachable 23036 base_reachable 30000 retrans 1000
-    gc_stale 60000 delay_probe 5000 queue 101
-    app_probes 0 ucast_probes 3 mcast_probes 3
-    anycast_delay 1000 proxy_delay 800 proxy_queue 64 locktime 0
-`)
-
-	ipNetconf = strings.TrimSpace(`
-    inet lo forwarding off rp_filter off mc_forwarding off proxy_neigh off ignore_routes_with_linkdown off
-    inet ens4 forwarding off rp_filter strict mc_forwarding off proxy_neigh off ignore_routes_with_linkdown off
-    inet all forwarding off rp_filter strict mc_forwarding off proxy_neigh off ignore_routes_with_linkdown off
-    inet default forwarding off rp_filter strict mc_forwarding off proxy_neigh off ignore_routes_with_linkdown off
-    inet6 lo forwarding off mc_forwarding off proxy_neigh off ignore_routes_with_linkdown off
-    inet6 ens4 forwarding off mc_forwarding off proxy_neigh off ignore_routes_with_linkdown off
-    inet6 all forwarding off mc_forwarding off proxy_neigh off ignore_routes_with_linkdown off
-    inet6 default forwarding off mc_forwarding off proxy_neigh off ignore_routes_with_linkdown off
-`)
-
 	ipTunnel = ""
+
+	// loopback is the interface every machine has.
+	loopback = persona.Interface{
+		Name:      "lo",
+		MAC:       "00:00:00:00:00:00",
+		Addresses: []string{"127.0.0.1/8", "::1/128"},
+		MTU:       65536,
+		RXPackets: 687175,
+		RXBytes:   67648738,
+		TXPackets: 687175,
+		TXBytes:   67648738,
+	}
 )
 
+// netInterfaces returns the loopback interface followed by the persona's.
+func netInterfaces(p *persona.Persona) []persona.Interface {
+	return append([]persona.Interface{loopback}, p.Interfaces...)
+}
+
+// isLoopback returns true if the interface is the loopback interface.
+func isLoopback(iface *persona.Interface) bool {
+	return iface.Name == loopback.Name
+}
+
+// ipScope returns the scope of an address in the format of ip.
+func ipScope(ip net.IP) string {
+	switch {
+	case ip.IsLoopback():
+		return "host"
+	case ip.IsLinkLocalUnicast():
+		return "link"
+	default:
+		return "global"
+	}
+}
+
+// ipBroadcast returns the broadcast address of an IPv4 network.
+func ipBroadcast(ipNet *net.IPNet) net.IP {
+	ip := ipNet.IP.To4()
+	out := make(net.IP, len(ip))
+	for i := range ip {
+		out[i] = ip[i] | ^ipNet.Mask[i]
+	}
+	return out
+}
+
+// scaledBytes formats a byte count with one decimal place in the largest
+// unit that fits e.g. "57.4 GB".
+func scaledBytes(bytes int64, base float64, units ...string) string {
+	value := float64(bytes)
+	unit := 0
+	for value >= base && unit < len(units)-1 {
+		value /= base
+		unit++
+	}
+	return fmt.Sprintf("%0.1f %s", value, units[unit])
+}
+
 // Ifconfig implements the ifconfig command.
 func Ifconfig(virtOS vos.VOS) int {
 	cmd := &SimpleCommand{
-		Use:   "ifconfig [OPTION...]",
+		Use:   "ifconfig [OPTION...] [INTERFACE]",
 		Short: "configure a network interface",
 
 		// Never bail, even if args are bad.
@@ -145,15 +101,115 @@ func Ifconfig(virtOS vos.VOS) int {
 	}
 
 	return cmd.Run(virtOS, func() int {
-		fmt.Fprintln(virtOS.Stdout(), ifconfigText)
+		p := virtOS.Persona()
+		ifaces := netInterfaces(p)
+		sort.Slice(ifaces, func(i, j int) bool {
+			return ifaces[i].Name < ifaces[j].Name
+		})
+
+		if args := cmd.Flags().Args(); len(args) > 0 {
+			var found []persona.Interface
+			for _, iface := range ifaces {
+				if iface.Name == args[0] {
+					found = append(found, iface)
+				}
+			}
+			if len(found) == 0 {
+				fmt.Fprintf(virtOS.Stderr(), "%s: error fetching interface information: Device not found\n", args[0])
+				return 1
+			}
+			ifaces = found
+		}
+
+		w := virtOS.Stdout()
+		for i := range ifaces {
+			if p.IsBusyBox() {
+				printBusyBoxIfconfig(w, &ifaces[i])
+			} else {
+				printIfconfig(w, &ifaces[i])
+			}
+		}
 		return 0
 	})
 }
 
+// printIfconfig prints an interface in the format of net-tools ifconfig.
+func printIfconfig(w io.Writer, iface *persona.Interface) {
+	siBytes := func(bytes int64) string {
+		return scaledBytes(bytes, 1000, "B", "KB", "MB", "GB", "TB")
+	}
+
+	if isLoopback(iface) {
+		fmt.Fprintf(w, "%s: flags=73<UP,LOOPBACK,RUNNING>  mtu %d\n", iface.Name, iface.GetMTU())
+	} else {
+		fmt.Fprintf(w, "%s: flags=4163<UP,BROADCAST,RUNNING,MULTICAST>  mtu %d\n", iface.Name, iface.GetMTU())
+	}
+
+	for _, ipNet := range iface.IPNets() {
+		switch {
+		case ipNet.IP.To4() == nil:
+			ones, _ := ipNet.Mask.Size()
+			scope := map[string]string{"host": "0x10<host>", "link": "0x20<link>", "global": "0x0<global>"}[ipScope(ipNet.IP)]
+			fmt.Fprintf(w, "        inet6 %s  prefixlen %d  scopeid %s\n", ipNet.IP, ones, scope)
+		case isLoopback(iface):
+			fmt.Fprintf(w, "        inet %s  netmask %s\n", ipNet.IP, net.IP(ipNet.Mask))
+		default:
+			fmt.Fprintf(w, "        inet %s  netmask %s  broadcast %s\n", ipNet.IP, net.IP(ipNet.Mask), ipBroadcast(ipNet))
+		}
+	}
+
+	if isLoopback(iface) {
+		fmt.Fprintf(w, "        loop  txqueuelen 1000  (Local Loopback)\n")
+	} else {
+		fmt.Fprintf(w, "        ether %s  txqueuelen 1000  (Ethernet)\n", iface.MAC)
+	}
+	fmt.Fprintf(w, "        RX packets %d  bytes %d (%s)\n", iface.RXPackets, iface.RXBytes, siBytes(iface.RXBytes))
+	fmt.Fprintf(w, "        RX errors 0  dropped 0  overruns 0  frame 0\n")
+	fmt.Fprintf(w, "        TX packets %d  bytes %d (%s)\n", iface.TXPackets, iface.TXBytes, siBytes(iface.TXBytes))
+	fmt.Fprintf(w, "        TX errors 0  dropped 0 overruns 0  carrier 0  collisions 0\n")
+	fmt.Fprintln(w)
+}
+
+// printBusyBoxIfconfig prints an interface in the format of BusyBox ifconfig.
+func printBusyBoxIfconfig(w io.Writer, iface *persona.Interface) {
+	binaryBytes := func(bytes int64) string {
+		return scaledBytes(bytes, 1024, "B", "KiB", "MiB", "GiB", "TiB")
+	}
+
+	if isLoopback(iface) {
+		fmt.Fprintf(w, "%-10sLink encap:Local Loopback  \n", iface.Name)
+	} else {
+		fmt.Fprintf(w, "%-10sLink encap:Ethernet  HWaddr %s  \n", iface.Name, strings.ToUpper(iface.MAC))
+	}
+
+	for _, ipNet := range iface.IPNets() {
+		switch {
+		case ipNet.IP.To4() == nil:
+			scope := map[string]string{"host": "Host", "link": "Link", "global": "Global"}[ipScope(ipNet.IP)]
+			fmt.Fprintf(w, "          inet6 addr: %s Scope:%s\n", ipNet, scope)
+		case isLoopback(iface):
+			fmt.Fprintf(w, "          inet addr:%s  Mask:%s\n", ipNet.IP, net.IP(ipNet.Mask))
+		default:
+			fmt.Fprintf(w, "          inet addr:%s  Bcast:%s  Mask:%s\n", ipNet.IP, ipBroadcast(ipNet), net.IP(ipNet.Mask))
+		}
+	}
+
+	if isLoopback(iface) {
+		fmt.Fprintf(w, "          UP LOOPBACK RUNNING  MTU:%d  Metric:1\n", iface.GetMTU())
+	} else {
+		fmt.Fprintf(w, "          UP BROADCAST RUNNING MULTICAST  MTU:%d  Metric:1\n", iface.GetMTU())
+	}
+	fmt.Fprintf(w, "          RX packets:%d errors:0 dropped:0 overruns:0 frame:0\n", iface.RXPackets)
+	fmt.Fprintf(w, "          TX packets:%d errors:0 dropped:0 overruns:0 carrier:0\n", iface.TXPackets)
+	fmt.Fprintf(w, "          collisions:0 txqueuelen:1000 \n")
+	fmt.Fprintf(w, "          RX bytes:%d (%s)  TX bytes:%d (%s)\n", iface.RXBytes, binaryBytes(iface.RXBytes), iface.TXBytes, binaryBytes(iface.TXBytes))
+	fmt.Fprintln(w)
+}
+
 // Ip implements the ip command (newer replacemnet for ifconfig)
 func Ip(virtOS vos.VOS) int {
 	cmd := &SimpleCommand{
-		Use:   "ip [ OPTIONS ] (link | address | addrlabel | route | rule | ntable | tunnel)",
+		Use:   "ip [ OPTIONS ] (link | address | addrlabel | route | rule | ntable | netconf | tunnel)",
 		Short: "configure routing, devices, interfaces, and tunnels",
 
 		// Never bail, even if args are bad.
@@ -166,31 +222,160 @@ func Ip(virtOS vos.VOS) int {
 			opt = args[0]
 		}
 
-		toDisplay := ""
+		w := virtOS.Stdout()
+		ifaces := netInterfaces(virtOS.Persona())
 		switch opt {
-		case "link":
-			toDisplay = ipLink
-		case "route":
-			toDisplay = ipRoute
+		case "link", "l":
+			printIPLinks(w, ifaces, false)
+		case "route", "r":
+			printIPRoutes(w, virtOS.Persona())
 		case "rule":
-			toDisplay = ipRule
+			fmt.Fprintln(w, ipRule)
 		case "tunnel":
-			toDisplay = ""
+			fmt.Fprintln(w, ipTunnel)
 		case "addrlabel":
-			toDisplay = ipAddrlabel
+			fmt.Fprintln(w, ipAddrlabel)
 		case "ntable":
-			toDisplay = ipNtable
-		case "address", "":
+			printIPNtable(w, ifaces)
+		case "netconf":
+			printIPNetconf(w, ifaces)
+		case "address", "addr", "a", "":
 			fallthrough
 		default:
-			toDisplay = ipAddress
+			printIPLinks(w, ifaces, true)
 		}
-
-		fmt.Fprintln(virtOS.Stdout(), toDisplay)
 		return 0
 	})
 }
 
+// printIPLinks prints the interfaces in the format of ip link, including
+// their addresses in the format of ip address if showAddresses is set.
+func printIPLinks(w io.Writer, ifaces []persona.Interface, showAddresses bool) {
+	mode := "mode DEFAULT "
+	if showAddresses {
+		mode = ""
+	}
+
+	for i := range ifaces {
+		iface := &ifaces[i]
+		if isLoopback(iface) {
+			fmt.Fprintf(w, "%d: %s: <LOOPBACK,UP,LOWER_UP> mtu %d qdisc noqueue state UNKNOWN %sgroup default qlen 1000\n", i+1, iface.Name, iface.GetMTU(), mode)
+			fmt.Fprintf(w, "    link/loopback %s brd 00:00:00:00:00:00\n", iface.MAC)
+		} else {
+			fmt.Fprintf(w, "%d: %s: <BROADCAST,MULTICAST,UP,LOWER_UP> mtu %d qdisc mq state UP %sgroup default qlen 1000\n", i+1, iface.Name, iface.GetMTU(), mode)
+			fmt.Fprintf(w, "    link/ether %s brd ff:ff:ff:ff:ff:ff\n", iface.MAC)
+		}
+		if !showAddresses {
+			continue
+		}
+
+		for _, ipNet := range iface.IPNets() {
+			ones, _ := ipNet.Mask.Size()
+			switch {
+			case ipNet.IP.To4() == nil:
+				fmt.Fprintf(w, "    inet6 %s/%d scope %s\n", ipNet.IP, ones, ipScope(ipNet.IP))
+			case isLoopback(iface):
+				fmt.Fprintf(w, "    inet %s/%d scope host %s\n", ipNet.IP, ones, iface.Name)
+			default:
+				fmt.Fprintf(w, "    inet %s/%d brd %s scope global %s\n", ipNet.IP, ones, ipBroadcast(ipNet), iface.Name)
+			}
+			fmt.Fprintf(w, "       valid_lft forever preferred_lft forever\n")
+		}
+	}
+}
+
+// printIPRoutes prints the IPv4 routing table in the format of ip route.
+func printIPRoutes(w io.Writer, p *persona.Persona) {
+	gateway := net.ParseIP(p.Gateway)
+
+	// The default route goes out of the interface on the gateway's network, or
+	// the first interface with an IPv4 address if none are e.g. for /32
+	// addresses in clouds.
+	var gatewayIface, gatewaySrc string
+	for _, iface := range p.Interfaces {
+		for _, ipNet := range iface.IPNets() {
+			if ipNet.IP.To4() == nil {
+				continue
+			}
+			if gatewayIface == "" || (gateway != nil && ipNet.Contains(gateway)) {
+				gatewayIface, gatewaySrc = iface.Name, ipNet.IP.String()
+			}
+		}
+	}
+
+	if gateway != nil && gatewayIface != "" {
+		fmt.Fprintf(w, "default via %s dev %s proto static src %s\n", gateway, gatewayIface, gatewaySrc)
+	}
+
+	for _, iface := range p.Interfaces {
+		for _, ipNet := range iface.IPNets() {
+			if ipNet.IP.To4() == nil {
+				continue
+			}
+			ones, bits := ipNet.Mask.Size()
+			if ones == bits {
+				if iface.Name == gatewayIface && gateway != nil {
+					fmt.Fprintf(w, "%s dev %s scope link\n", gateway, iface.Name)
+				}
+				continue
+			}
+			network := &net.IPNet{IP: ipNet.IP.Mask(ipNet.Mask), Mask: ipNet.Mask}
+			fmt.Fprintf(w, "%s dev %s proto kernel scope link src %s\n", network, iface.Name, ipNet.IP)
+		}
+	}
+}
+
+// printIPNtable prints the neighbor tables in the format of ip ntable.
+func printIPNtable(w io.Writer, ifaces []persona.Interface) {
+	for _, family := range []struct {
+		table    string
+		locktime int
+	}{
+		{"inet arp_cache", 1000},
+		{"inet6 ndisc_cache", 0},
+	} {
+		fmt.Fprintf(w, "%s\n", family.table)
+		fmt.Fprintf(w, "    thresh1 128 thresh2 512 thresh3 1024 gc_int 30000\n")
+		fmt.Fprintf(w, "    refcnt 1 reachable 31176 base_reachable 30000 retrans 1000\n")
+		fmt.Fprintf(w, "    gc_stale 60000 delay_probe 5000 queue 101\n")
+		fmt.Fprintf(w, "    app_probes 0 ucast_probes 3 mcast_probes 3\n")
+		fmt.Fprintf(w, "    anycast_delay 1000 proxy_delay 800 proxy_queue 64 locktime %d\n", family.locktime)
+		fmt.Fprintln(w)
+
+		for i := len(ifaces) - 1; i >= 0; i-- {
+			fmt.Fprintf(w, "%s\n", family.table)
+			fmt.Fprintf(w, "    dev %s\n", ifaces[i].Name)
+			fmt.Fprintf(w, "    refcnt 2 reachable %d base_reachable 30000 retrans 1000\n", 23036+i*6052)
+			fmt.Fprintf(w, "    gc_stale 60000 delay_probe 5000 queue 101\n")
+			fmt.Fprintf(w, "    app_probes 0 ucast_probes 3 mcast_probes 3\n")
+			fmt.Fprintf(w, "    anycast_delay 1000 proxy_delay 800 proxy_queue 64 locktime %d\n", family.locktime)
+			fmt.Fprintln(w)
+		}
+	}
+}
+
+// printIPNetconf prints the network configuration in the format of
+// ip netconf.
+func printIPNetconf(w io.Writer, ifaces []persona.Interface) {
+	for _, family := range []string{"inet", "inet6"} {
+		names := []string{}
+		for _, iface := range ifaces {
+			names = append(names, iface.Name)
+		}
+		names = append(names, "all", "default")
+
+		for _, name := range names {
+			rpFilter := " rp_filter strict"
+			if family == "inet6" {
+				rpFilter = ""
+			} else if name == loopback.Name {
+				rpFilter = " rp_filter off"
+			}
+			fmt.Fprintf(w, "%s %s forwarding off%s mc_forwarding off proxy_neigh off ignore_routes_with_linkdown off\n", family, name, rpFilter)
+		}
+	}
+}
+
 var _ vos.ProcessFunc = Ifconfig
 
 func init() {
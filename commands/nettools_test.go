@@ -0,0 +1,27 @@
+package commands
+
+import (
+	"testing"
+)
+
+func TestIfconfig(t *testing.T) {
+	cases := goldenTestSuite{
+		"no-arg":    {[]string{"ifconfig"}},
+		"interface": {[]string{"ifconfig", "lo"}},
+		"missing":   {[]string{"ifconfig", "eth9"}},
+	}
+
+	cases.Run(t, Ifconfig)
+}
+
+func TestIp(t *testing.T) {
+	cases := goldenTestSuite{
+		"no-arg":  {[]string{"ip"}},
+		"address": {[]string{"ip", "addr"}},
+		"link":    {[]string{"ip", "link"}},
+		"route":   {[]string{"ip", "route"}},
+		"netconf": {[]string{"ip", "netconf"}},
+	}
+
+	cases.Run(t, Ip)
+}
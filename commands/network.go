@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"net/http"
+
+	"github.com/josephlewis42/honeyssh/core/config"
+	"github.com/josephlewis42/honeyssh/core/fakenet"
+	"github.com/josephlewis42/honeyssh/core/payload"
+	"github.com/josephlewis42/honeyssh/core/vos"
+)
+
+// NewHTTPClient creates the client processes use to make HTTP requests in the
+// configured network mode.
+func NewHTTPClient(configuration *config.Configuration, payloads *payload.Store) *http.Client {
+	network := configuration.Network
+	sinkhole := fakenet.NewSinkhole(network.Responses, configuration.SinkholeFs())
+
+	switch network.Mode {
+	case config.NetworkModeSinkhole:
+		return &http.Client{Transport: sinkhole}
+	case config.NetworkModeReplay:
+		return &http.Client{Transport: fakenet.NewReplay(payloads, sinkhole)}
+	default:
+		return wgetHTTPClient
+	}
+}
+
+// httpClient returns the client a process should use for HTTP requests.
+func httpClient(virtOS vos.VOS) *http.Client {
+	if client := virtOS.HTTPClient(); client != nil {
+		return client
+	}
+	return wgetHTTPClient
+}
@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/josephlewis42/honeyssh/core/config"
+	"github.com/josephlewis42/honeyssh/core/fakenet"
+	"github.com/josephlewis42/honeyssh/core/payload"
+	"github.com/josephlewis42/honeyssh/core/vos"
+	"github.com/josephlewis42/honeyssh/core/vos/vostest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWget_networkModes(t *testing.T) {
+	const body = "#!/bin/sh\necho hi\n"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/x-sh")
+		fmt.Fprint(w, body)
+	}))
+	defer server.Close()
+
+	sinkhole := fakenet.NewSinkhole([]config.FakeResponse{
+		{Path: "/install.sh", Body: body, ContentType: "text/x-sh"},
+	}, nil)
+
+	run := func(client *http.Client) (string, *payload.Store) {
+		cmd := vostest.Command(Wget, "wget", server.URL+"/install.sh")
+		store := payload.NewStore(afero.NewMemMapFs())
+		procOS := cmd.VOS.(*vos.TenantProcOS)
+		procOS.SetPayloads(store)
+		procOS.SetHTTPClient(client)
+
+		out := &bytes.Buffer{}
+		cmd.Stdout = out
+		cmd.Stderr = out
+		assert.NoError(t, cmd.Run())
+		assert.Equal(t, 0, cmd.ExitStatus)
+		return out.String(), store
+	}
+
+	live, liveStore := run(newWgetHTTPClient(nil))
+	faked, fakedStore := run(&http.Client{Transport: sinkhole})
+	assert.Equal(t, live, faked)
+
+	liveEntries, err := liveStore.List()
+	assert.NoError(t, err)
+	assert.Len(t, liveEntries, 1)
+
+	// The test configuration is in live mode so both downloads are stored.
+	fakedEntries, err := fakedStore.List()
+	assert.NoError(t, err)
+	assert.Equal(t, liveEntries[0].SHA256, fakedEntries[0].SHA256)
+}
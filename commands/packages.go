@@ -0,0 +1,250 @@
+package commands
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/josephlewis42/honeyssh/core/persona"
+	"github.com/josephlewis42/honeyssh/core/vos"
+)
+
+// findPackages returns the installed packages matching the glob patterns, all
+// packages if there are none.
+func findPackages(p *persona.Persona, patterns []string) []persona.Package {
+	if len(patterns) == 0 {
+		return p.Packages
+	}
+
+	var out []persona.Package
+	for _, pkg := range p.Packages {
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, pkg.Name); ok {
+				out = append(out, pkg)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// findPackage returns the installed package with the given name.
+func findPackage(p *persona.Persona, name string) (persona.Package, bool) {
+	for _, pkg := range p.Packages {
+		if pkg.Name == name {
+			return pkg, true
+		}
+	}
+	return persona.Package{}, false
+}
+
+// Dpkg implements the dpkg and dpkg-query commands of Debian based personas.
+func Dpkg(virtOS vos.VOS) int {
+	cmd := &SimpleCommand{
+		Use:   "dpkg [<option>...] <command>",
+		Short: "Debian package manager.",
+
+		// Never bail, even if args are bad.
+		NeverBail: true,
+	}
+
+	list := cmd.Flags().BoolLong("list", 'l', "list packages concisely")
+	status := cmd.Flags().BoolLong("status", 's', "report status of specified package")
+
+	return cmd.Run(virtOS, func() int {
+		p := virtOS.Persona()
+		if p.Distro.PackageManager != persona.PackageManagerDpkg {
+			return commandNotFound(virtOS)
+		}
+
+		w := virtOS.Stdout()
+		args := cmd.Flags().Args()
+		switch {
+		case *list:
+			packages := findPackages(p, args)
+			if len(packages) == 0 {
+				fmt.Fprintf(virtOS.Stderr(), "dpkg-query: no packages found matching %s\n", strings.Join(args, " "))
+				return 1
+			}
+
+			nameWidth, versionWidth, archWidth := len("Name"), len("Version"), len("Architecture")
+			for _, pkg := range packages {
+				nameWidth = max(nameWidth, len(pkg.Name))
+				versionWidth = max(versionWidth, len(pkg.Version))
+				archWidth = max(archWidth, len(pkg.Arch))
+			}
+			format := fmt.Sprintf("%%-3s %%-%ds %%-%ds %%-%ds %%s\n", nameWidth, versionWidth, archWidth)
+
+			fmt.Fprintln(w, "Desired=Unknown/Install/Remove/Purge/Hold")
+			fmt.Fprintln(w, "| Status=Not/Inst/Conf-files/Unpacked/halF-conf/Half-inst/trig-aWait/Trig-pend")
+			fmt.Fprintln(w, "|/ Err?=(none)/Reinst-required (Status,Err: uppercase=bad)")
+			fmt.Fprintf(w, format, "||/", "Name", "Version", "Architecture", "Description")
+			fmt.Fprintf(w, "+++-%s-%s-%s-%s\n",
+				strings.Repeat("=", nameWidth),
+				strings.Repeat("=", versionWidth),
+				strings.Repeat("=", archWidth),
+				strings.Repeat("=", 40))
+			for _, pkg := range packages {
+				fmt.Fprintf(w, format, "ii", pkg.Name, pkg.Version, pkg.Arch, pkg.Description)
+			}
+			return 0
+
+		case *status:
+			if len(args) == 0 {
+				fmt.Fprintln(virtOS.Stderr(), "dpkg-query: error: --status needs at least one package name argument")
+				return 2
+			}
+			status := 0
+			for i, name := range args {
+				pkg, ok := findPackage(p, name)
+				if !ok {
+					fmt.Fprintf(virtOS.Stderr(), "dpkg-query: package '%s' is not installed and no information is available\n", name)
+					status = 1
+					continue
+				}
+				if i > 0 {
+					fmt.Fprintln(w)
+				}
+				fmt.Fprintf(w, "Package: %s\n", pkg.Name)
+				fmt.Fprintf(w, "Status: install ok installed\n")
+				fmt.Fprintf(w, "Architecture: %s\n", pkg.Arch)
+				fmt.Fprintf(w, "Version: %s\n", pkg.Version)
+				fmt.Fprintf(w, "Description: %s\n", pkg.Description)
+			}
+			return status
+
+		default:
+			fmt.Fprintln(virtOS.Stderr(), "dpkg: error: need an action option")
+			fmt.Fprintln(virtOS.Stderr())
+			fmt.Fprintln(virtOS.Stderr(), "Use --help for help about querying packages.")
+			return 2
+		}
+	})
+}
+
+// Rpm implements the query commands of rpm for Red Hat based personas.
+func Rpm(virtOS vos.VOS) int {
+	cmd := &SimpleCommand{
+		Use:   "rpm [OPTION...]",
+		Short: "RPM Package Manager.",
+
+		// Never bail, even if args are bad.
+		NeverBail: true,
+	}
+
+	query := cmd.Flags().BoolLong("query", 'q', "query installed packages")
+	all := cmd.Flags().BoolLong("all", 'a', "query all packages")
+	info := cmd.Flags().BoolLong("info", 'i', "list descriptive information from package(s)")
+
+	return cmd.Run(virtOS, func() int {
+		p := virtOS.Persona()
+		if p.Distro.PackageManager != persona.PackageManagerRPM {
+			return commandNotFound(virtOS)
+		}
+
+		w := virtOS.Stdout()
+		args := cmd.Flags().Args()
+		if !*query {
+			fmt.Fprintln(virtOS.Stderr(), "rpm: no operation specified")
+			return 1
+		}
+
+		var packages []persona.Package
+		status := 0
+		switch {
+		case *all:
+			packages = findPackages(p, args)
+		case len(args) == 0:
+			fmt.Fprintln(virtOS.Stderr(), "rpm: no arguments given for query")
+			return 1
+		default:
+			for _, name := range args {
+				pkg, ok := findPackage(p, name)
+				if !ok {
+					fmt.Fprintf(w, "package %s is not installed\n", name)
+					status = 1
+					continue
+				}
+				packages = append(packages, pkg)
+			}
+		}
+
+		for _, pkg := range packages {
+			if !*info {
+				fmt.Fprintf(w, "%s-%s.%s\n", pkg.Name, pkg.Version, pkg.Arch)
+				continue
+			}
+			version, release, _ := strings.Cut(pkg.Version, "-")
+			fmt.Fprintf(w, "Name        : %s\n", pkg.Name)
+			fmt.Fprintf(w, "Version     : %s\n", version)
+			fmt.Fprintf(w, "Release     : %s\n", release)
+			fmt.Fprintf(w, "Architecture: %s\n", pkg.Arch)
+			fmt.Fprintf(w, "Summary     : %s\n", pkg.Description)
+		}
+		return status
+	})
+}
+
+// Opkg implements the query commands of opkg for OpenWrt based personas.
+func Opkg(virtOS vos.VOS) int {
+	cmd := &SimpleCommand{
+		Use:   "opkg [options...] sub-command [arguments...]",
+		Short: "OpenWrt package manager.",
+
+		// Never bail, even if args are bad.
+		NeverBail: true,
+	}
+
+	return cmd.Run(virtOS, func() int {
+		p := virtOS.Persona()
+		if p.Distro.PackageManager != persona.PackageManagerOpkg {
+			return commandNotFound(virtOS)
+		}
+
+		w := virtOS.Stdout()
+		args := cmd.Flags().Args()
+		if len(args) == 0 {
+			fmt.Fprintln(virtOS.Stderr(), "opkg must have one sub-command argument")
+			return 1
+		}
+
+		switch args[0] {
+		case "list-installed", "list":
+			for _, pkg := range findPackages(p, args[1:]) {
+				fmt.Fprintf(w, "%s - %s\n", pkg.Name, pkg.Version)
+			}
+			return 0
+
+		case "info", "status":
+			for _, pkg := range findPackages(p, args[1:]) {
+				fmt.Fprintf(w, "Package: %s\n", pkg.Name)
+				fmt.Fprintf(w, "Version: %s\n", pkg.Version)
+				fmt.Fprintf(w, "Status: install user installed\n")
+				fmt.Fprintf(w, "Architecture: %s\n", pkg.Arch)
+				fmt.Fprintln(w)
+			}
+			return 0
+
+		case "install":
+			fmt.Fprintln(w, "Unknown package '"+strings.Join(args[1:], " ")+"'.")
+			fmt.Fprintln(w, "Collected errors:")
+			fmt.Fprintln(w, " * opkg_install_cmd: Cannot install package "+strings.Join(args[1:], " ")+".")
+			return 255
+
+		default:
+			fmt.Fprintf(virtOS.Stderr(), "opkg: unknown sub-command %s\n", args[0])
+			return 1
+		}
+	})
+}
+
+var _ vos.ProcessFunc = Dpkg
+var _ vos.ProcessFunc = Rpm
+var _ vos.ProcessFunc = Opkg
+
+func init() {
+	mustAddBinCmd("dpkg", Dpkg)
+	mustAddBinCmd("dpkg-query", Dpkg)
+	mustAddBinCmd("rpm", Rpm)
+	mustAddBinCmd("opkg", Opkg)
+}
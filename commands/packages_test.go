@@ -0,0 +1,26 @@
+package commands
+
+import (
+	"testing"
+)
+
+func TestDpkg(t *testing.T) {
+	cases := goldenTestSuite{
+		"no-arg":         {[]string{"dpkg"}},
+		"list":           {[]string{"dpkg", "-l"}},
+		"list-pattern":   {[]string{"dpkg", "-l", "openssh*"}},
+		"list-missing":   {[]string{"dpkg", "-l", "nginx"}},
+		"status":         {[]string{"dpkg", "-s", "bash"}},
+		"status-missing": {[]string{"dpkg", "-s", "nginx"}},
+	}
+
+	cases.Run(t, Dpkg)
+}
+
+func TestRpm(t *testing.T) {
+	cases := goldenTestSuite{
+		"wrong-distro": {[]string{"rpm", "-qa"}},
+	}
+
+	cases.Run(t, Rpm)
+}
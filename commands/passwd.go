@@ -3,7 +3,6 @@ package commands
 import (
 	"fmt"
 
-	"github.com/abiosoft/readline"
 	"github.com/josephlewis42/honeyssh/core/logger"
 	"github.com/josephlewis42/honeyssh/core/vos"
 )
@@ -19,21 +18,7 @@ func Passwd(virtualOS vos.VOS) int {
 	}
 
 	return cmd.Run(virtualOS, func() int {
-		cfg := &readline.Config{
-			Stdin:  readline.NewCancelableStdin(virtualOS.Stdin()),
-			Stdout: virtualOS.Stdout(),
-			Stderr: virtualOS.Stderr(),
-			FuncGetWidth: func() int {
-				return virtualOS.GetPTY().Width
-			},
-			FuncIsTerminal: func() bool {
-				return virtualOS.GetPTY().IsPTY
-			},
-		}
-		if err := cfg.Init(); err != nil {
-			return 1
-		}
-		readline, err := readline.NewEx(cfg)
+		readline, err := newPasswordReader(virtualOS)
 		if err != nil {
 			return 1
 		}
@@ -52,6 +37,7 @@ func Passwd(virtualOS vos.VOS) int {
 		virtualOS.LogCreds(&logger.Credentials{
 			Username: login,
 			Password: string(newPass1),
+			Target:   "passwd",
 		})
 		newPass2, err2 := readline.ReadPassword("Retype new UNIX password: ")
 		if err2 != nil {
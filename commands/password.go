@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"io"
+	"strings"
+
+	"github.com/abiosoft/readline"
+	"github.com/josephlewis42/honeyssh/core/vos"
+)
+
+// newPasswordReader creates a readline instance attached to the process that
+// can prompt for passwords without echoing them.
+func newPasswordReader(virtualOS vos.VOS) (*readline.Instance, error) {
+	cfg := &readline.Config{
+		Stdin:  readline.NewCancelableStdin(virtualOS.Stdin()),
+		Stdout: virtualOS.Stdout(),
+		Stderr: virtualOS.Stderr(),
+		FuncGetWidth: func() int {
+			return virtualOS.GetPTY().Width
+		},
+		FuncIsTerminal: func() bool {
+			return virtualOS.GetPTY().IsPTY
+		},
+	}
+	if err := cfg.Init(); err != nil {
+		return nil, err
+	}
+	return readline.NewEx(cfg)
+}
+
+// readPassword prompts for a password on the terminal.
+func readPassword(virtualOS vos.VOS, prompt string) (string, error) {
+	rl, err := newPasswordReader(virtualOS)
+	if err != nil {
+		return "", err
+	}
+	defer rl.Close()
+
+	password, err := rl.ReadPassword(prompt)
+	return string(password), err
+}
+
+// readLine reads a single line from r without buffering past the newline so
+// the rest of the input is left for the next reader.
+func readLine(r io.Reader) (string, error) {
+	var (
+		line strings.Builder
+		buf  [1]byte
+	)
+	for {
+		n, err := r.Read(buf[:])
+		if n == 1 {
+			if buf[0] == '\n' {
+				return strings.TrimSuffix(line.String(), "\r"), nil
+			}
+			line.WriteByte(buf[0])
+		}
+		if err == io.EOF && line.Len() > 0 {
+			return line.String(), nil
+		}
+		if err != nil {
+			return line.String(), err
+		}
+	}
+}
+
+// currentUser returns the name of the user the process is running as.
+func currentUser(virtualOS vos.VOS) string {
+	if user := virtualOS.Getenv(EnvUser); user != "" {
+		return user
+	}
+	return virtualOS.SSHUser()
+}
@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/josephlewis42/honeyssh/core/config"
+	"github.com/josephlewis42/honeyssh/core/persona"
+	"github.com/josephlewis42/honeyssh/core/vos"
+	"github.com/josephlewis42/honeyssh/core/vos/vostest"
+	"github.com/josephlewis42/honeyssh/third_party/memmapfs"
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPersonas renders each built-in persona with the commands that reveal
+// the machine so they can be reviewed side by side.
+func TestPersonas(t *testing.T) {
+	commands := []string{
+		"uname -a",
+		"cat /etc/os-release",
+		"cat /proc/version",
+		"cat /proc/cpuinfo",
+		"cat /proc/mounts",
+		"lscpu",
+		"lspci",
+		"lsusb",
+		"free -h",
+		"df -h",
+		"ifconfig",
+		"ip addr",
+		"ip route",
+		"ps aux",
+		"dpkg -l",
+		"rpm -qa",
+		"opkg list-installed",
+	}
+
+	g := goldie.New(
+		t,
+		goldie.WithFixtureDir(filepath.Join("testdata", "golden")),
+		goldie.WithTestNameForDir(true),
+	)
+
+	for _, name := range persona.BuiltinNames() {
+		t.Run(name, func(t *testing.T) {
+			cfg := &config.Configuration{
+				Persona: name,
+				OS:      config.OS{DefaultShell: "/bin/sh", DefaultPath: "/bin:/sbin"},
+				Uname:   config.Uname{Nodename: "honeypot"},
+			}
+			timeSource := func() time.Time {
+				return time.Date(2006, 1, 2, 3, 4, 5, 0, time.UTC)
+			}
+			sharedOS := vos.NewSharedOS(memmapfs.NewMemMapFs(timeSource), BuiltinProcessResolver, cfg, timeSource)
+			procOS := vos.NewTenantOS(sharedOS, &vostest.NopEventRecorder{}, &vostest.FakeSSHSession{}).LoginProc()
+
+			out := &bytes.Buffer{}
+			for _, command := range commands {
+				argv := strings.Fields(command)
+				fmt.Fprintf(out, "$ %s\n", command)
+				proc, err := procOS.StartProcess(argv[0], argv, &vos.ProcAttr{
+					Files: vos.NewVIOAdapter(nil, out, out),
+				})
+				if !assert.NoError(t, err) {
+					continue
+				}
+				if status := proc.Run(); status != 0 {
+					fmt.Fprintf(out, "[exit status %d]\n", status)
+				}
+				fmt.Fprintln(out)
+			}
+
+			g.Assert(t, name, out.Bytes())
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"path"
 	"strconv"
 
 	goscp "github.com/bramvdbogaerde/go-scp"
@@ -81,10 +82,66 @@ func scpUpload(vos vos.VOS, path string) (err error) {
 	}
 }
 
-// Scp implements an SCP command that only uploads.
+// scpDownload sends files to the client.
+func scpDownload(virtOS vos.VOS, paths []string) error {
+	if err := scpReadAck(virtOS); err != nil {
+		return err
+	}
+
+	for _, name := range paths {
+		if err := scpSendFile(virtOS, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scpSendFile sends a single file to the client, files that can't be sent are
+// reported as warnings.
+func scpSendFile(virtOS vos.VOS, name string) error {
+	fd, err := virtOS.Open(name)
+	if err != nil {
+		fmt.Fprintf(virtOS.Stdout(), "\x01scp: %s: No such file or directory\n", name)
+		return nil
+	}
+	defer fd.Close()
+
+	info, err := fd.Stat()
+	switch {
+	case err != nil:
+		return err
+	case !info.Mode().IsRegular():
+		fmt.Fprintf(virtOS.Stdout(), "\x01scp: %s: not a regular file\n", name)
+		return nil
+	}
+
+	fmt.Fprintf(virtOS.Stdout(), "C%04o %d %s\n", info.Mode().Perm(), info.Size(), path.Base(name))
+	if err := scpReadAck(virtOS); err != nil {
+		return err
+	}
+	if _, err := io.Copy(virtOS.Stdout(), fd); err != nil {
+		return err
+	}
+	goscp.Ack(virtOS.Stdout())
+	return scpReadAck(virtOS)
+}
+
+// scpReadAck waits for the client to acknowledge the last message.
+func scpReadAck(virtOS vos.VOS) error {
+	resp, err := goscp.ParseResponse(virtOS.Stdin())
+	switch {
+	case err != nil:
+		return err
+	case resp.IsFailure():
+		return errors.New(resp.GetMessage())
+	}
+	return nil
+}
+
+// Scp implements an SCP command that sends and receives files.
 func Scp(virtOS vos.VOS) int {
 	cmd := &SimpleCommand{
-		Use:   "scp -t TOFILE",
+		Use:   "scp [-t TOFILE] [-f FILE...]",
 		Short: "Secure copy.",
 
 		// Never bail, even if args are bad.
@@ -92,6 +149,7 @@ func Scp(virtOS vos.VOS) int {
 	}
 
 	to := cmd.Flags().String('t', "", "Start scp in upload mode")
+	from := cmd.Flags().Bool('f', "Start scp in download mode")
 	_ = cmd.Flags().Bool('v', "Start scp in verbose mode")
 	_ = cmd.Flags().Bool('r', "Start scp in recursive mode")
 
@@ -105,6 +163,8 @@ func Scp(virtOS vos.VOS) int {
 				return err
 			}
 			return nil
+		case *from:
+			return scpDownload(virtOS, cmd.Flags().Args())
 		default:
 			return errors.New("couldn't connect")
 		}
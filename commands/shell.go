@@ -2,10 +2,13 @@ package commands
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/abiosoft/readline"
@@ -21,6 +24,7 @@ const (
 	EnvHostname        = "HOSTNAME"
 	EnvUser            = "USER"
 	EnvUID             = "UID"
+	EnvShellLevel      = "SHLVL"
 	DefaultColorPrompt = `\033[01;32m\u@\h\033[00m:\033[01;34m\w\033[00m\$ `
 	DefaultPrompt      = `\u@\h:\w\$ `
 )
@@ -29,6 +33,14 @@ var (
 	envRegex = regexp.MustCompile(`(\$\$|\$\w+)`)
 )
 
+const (
+	// maxShellLevel is the deepest shells may be nested, it prevents scripts
+	// that run themselves from recursing forever.
+	maxShellLevel = 32
+	// maxScriptSize is the largest script the shell will run.
+	maxScriptSize = 1 << 20
+)
+
 type Shell struct {
 	VirtualOS vos.VOS
 	Readline  *readline.Instance
@@ -36,11 +48,17 @@ type Shell struct {
 	lastRet int
 	history []string
 
+	// args holds $0 followed by the positional parameters.
+	args []string
+	// sourceDepth is the number of nested scripts being sourced.
+	sourceDepth int
+
 	// Set to true to quit the shell
 	Quit bool
 }
 
 func RunShell(virtualOS vos.VOS) int {
+	callerDir := virtualOS.Getwd()
 
 	s, err := NewShell(virtualOS)
 	if err != nil {
@@ -56,9 +74,29 @@ func RunShell(virtualOS vos.VOS) int {
 	commandFlag := cmd.Flags().String('c', "", "Command")
 
 	return cmd.Run(virtualOS, func() int {
-		if *commandFlag != "" {
+		level, _ := strconv.Atoi(virtualOS.Getenv(EnvShellLevel))
+		if level >= maxShellLevel {
+			fmt.Fprintf(virtualOS.Stderr(), "sh: maximum nesting level exceeded (%d)\n", level)
+			return 2
+		}
+		virtualOS.Setenv(EnvShellLevel, strconv.Itoa(level+1))
+
+		args := cmd.Flags().Args()
+		switch {
+		case *commandFlag != "":
+			if len(args) > 0 {
+				s.args = args
+			}
 			s.runCommand(*commandFlag)
 			return s.lastRet
+
+		case len(args) > 0:
+			// Scripts run in the caller's directory rather than home.
+			if err := virtualOS.Chdir(callerDir); err != nil {
+				fmt.Fprintf(virtualOS.Stderr(), "sh: %v\n", err)
+				return 2
+			}
+			return s.runScript(args[0], args)
 		}
 
 		return s.runInteractive()
@@ -93,7 +131,7 @@ func NewShell(virtualOS vos.VOS) (*Shell, error) {
 		Readline:  readline,
 	}
 
-	shell.Init(virtualOS.SSHUser())
+	shell.Init(currentUser(virtualOS))
 
 	return shell, nil
 }
@@ -245,13 +283,15 @@ func (s *Shell) executeStatement(ec execContext, stmt *syntax.Stmt) error {
 		}
 
 		for _, word := range cmd.Args {
-			argStr, err := s.evalWord(ec, word)
+			fields, err := s.evalFields(ec, word)
 			if err != nil {
 				return err
 			}
-			ec.args = append(ec.args, argStr)
+			ec.args = append(ec.args, fields...)
 		}
 		s.executeProgramOrBuiltin(ec)
+	case *syntax.ForClause:
+		return s.executeFor(ec, stmt, cmd)
 	case *syntax.BinaryCmd:
 		switch cmd.Op {
 		case syntax.AndStmt:
@@ -293,6 +333,42 @@ func (s *Shell) executeStatement(ec execContext, stmt *syntax.Stmt) error {
 	return nil
 }
 
+// executeFor runs a for loop over a list of words, or the positional
+// parameters if there's no list.
+func (s *Shell) executeFor(ec execContext, stmt *syntax.Stmt, cmd *syntax.ForClause) error {
+	iter, ok := cmd.Loop.(*syntax.WordIter)
+	if !ok || cmd.Select {
+		return s.logSyntaxError(ec, stmt)
+	}
+
+	items := s.positional()[1:]
+	if iter.InPos.IsValid() {
+		items = nil
+		for _, word := range iter.Items {
+			fields, err := s.evalFields(ec, word)
+			if err != nil {
+				return err
+			}
+			items = append(items, fields...)
+		}
+	}
+
+	for _, item := range items {
+		s.VirtualOS.Setenv(iter.Name.Value, item)
+		for _, loopStmt := range cmd.Do {
+			if s.Quit {
+				return nil
+			}
+			// Statements in the body see variables set by the ones before.
+			ec.env = s.cmdEnv().Environ()
+			if err := s.executeStatement(ec, loopStmt); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func (s *Shell) evalAssign(ec execContext, assignments []*syntax.Assign) ([]string, error) {
 	out := vos.NewMapEnv()
 	tmpEnv := vos.NewMapEnvFromEnvList(ec.env)
@@ -331,16 +407,68 @@ func (s *Shell) evalWord(ec execContext, word *syntax.Word) (string, error) {
 	if word == nil {
 		return "", nil
 	}
-	var out []string
+	fields, err := s.evalFields(ec, word)
+	return strings.Join(fields, " "), err
+}
 
-	for _, part := range word.Parts {
-		subEval, err := s.evalWordPart(ec, part)
-		if err != nil {
-			return "", err
+// evalFields evaluates a word to the fields it expands to. Words are one
+// field except for $@ which expands to a field per positional parameter, even
+// when quoted.
+func (s *Shell) evalFields(ec execContext, word *syntax.Word) ([]string, error) {
+	return s.joinFields(ec, word.Parts)
+}
+
+// joinFields evaluates adjacent word parts, the first field of each part is
+// joined to the last field of the one before.
+func (s *Shell) joinFields(ec execContext, parts []syntax.WordPart) ([]string, error) {
+	var fields []string
+	for _, part := range parts {
+		var partFields []string
+		switch part := part.(type) {
+		case *syntax.DblQuoted:
+			var err error
+			if partFields, err = s.joinFields(ec, part.Parts); err != nil {
+				return nil, err
+			}
+			if partFields == nil && !containsParam(part.Parts, "@") {
+				// "" is an empty field.
+				partFields = []string{""}
+			}
+		default:
+			if isParam(part, "@") {
+				partFields = s.positional()[1:]
+				break
+			}
+			value, err := s.evalWordPart(ec, part)
+			if err != nil {
+				return nil, err
+			}
+			partFields = []string{value}
+		}
+
+		switch {
+		case len(partFields) == 0:
+		case fields == nil:
+			fields = append(fields, partFields...)
+		default:
+			fields[len(fields)-1] += partFields[0]
+			fields = append(fields, partFields[1:]...)
 		}
-		out = append(out, subEval)
 	}
-	return strings.Join(out, ""), nil
+	return fields, nil
+}
+
+// isParam returns true if the part expands the parameter.
+func isParam(part syntax.WordPart, name string) bool {
+	param, ok := part.(*syntax.ParamExp)
+	return ok && param.Param != nil && param.Param.Value == name
+}
+
+// containsParam returns true if any of the parts expand the parameter.
+func containsParam(parts []syntax.WordPart, name string) bool {
+	return slices.ContainsFunc(parts, func(part syntax.WordPart) bool {
+		return isParam(part, name)
+	})
 }
 
 func (s *Shell) evalWordPart(ec execContext, part syntax.WordPart) (string, error) {
@@ -427,6 +555,15 @@ func (s *Shell) cmdEnv() vos.VEnv {
 	mapEnv.Setenv("WIDTH", fmt.Sprintf("%d", s.VirtualOS.GetPTY().Width))
 	mapEnv.Setenv("HEIGHT", fmt.Sprintf("%d", s.VirtualOS.GetPTY().Height))
 
+	// Positional parameters
+	args := s.positional()
+	for i, arg := range args {
+		mapEnv.Setenv(strconv.Itoa(i), arg)
+	}
+	mapEnv.Setenv("#", strconv.Itoa(len(args)-1))
+	mapEnv.Setenv("@", strings.Join(args[1:], " "))
+	mapEnv.Setenv("*", strings.Join(args[1:], " "))
+
 	return mapEnv
 }
 
@@ -441,6 +578,7 @@ func (s *Shell) executeProgramOrBuiltin(ec execContext) {
 
 	// Execute builtins
 	if builtin, ok := AllBuiltins[ec.args[0]]; ok {
+		s.VirtualOS.LogBuiltin(ec.args)
 		s.lastRet = builtin.Main(s, ec.args)
 
 		log.Printf("builtin")
@@ -461,6 +599,90 @@ func (s *Shell) executeProgramOrBuiltin(ec execContext) {
 	s.lastRet = proc.Run()
 }
 
+// positional returns $0 followed by the positional parameters.
+func (s *Shell) positional() []string {
+	if len(s.args) == 0 {
+		return s.VirtualOS.Args()[:1]
+	}
+	return s.args
+}
+
+// runScript runs a shell script line by line, args holds $0 followed by the
+// positional parameters.
+func (s *Shell) runScript(name string, args []string) int {
+	script, err := s.readScript(name)
+	if err != nil {
+		fmt.Fprintf(s.VirtualOS.Stderr(), "sh: 0: cannot open %s: %v\n", name, err)
+		return 2
+	}
+
+	oldArgs := s.args
+	s.args = args
+	defer func() { s.args = oldArgs }()
+
+	s.lastRet = 0
+	lines := strings.Split(script, "\n")
+
+	// The script is parsed once as it runs rather than re-parsing statements
+	// that span lines until they're complete, that would let a large script
+	// with an unterminated statement tie up the CPU.
+	err = syntax.NewParser().Interactive(strings.NewReader(script), func(stmts []*syntax.Stmt) bool {
+		if len(stmts) == 0 {
+			return !s.Quit
+		}
+
+		// Log the lines the statements came from, including here-documents.
+		start, end := stmts[0].Pos().Line(), uint(0)
+		for _, stmt := range stmts {
+			syntax.Walk(stmt, func(node syntax.Node) bool {
+				if node != nil {
+					end = max(end, node.End().Line())
+				}
+				return true
+			})
+		}
+		stmt := strings.Join(lines[start-1:min(end, uint(len(lines)))], "\n")
+		s.VirtualOS.LogScriptLine(name, int(start), stmt)
+
+		if err := s.executeFile(&syntax.File{Stmts: stmts}, stmt); err != nil {
+			fmt.Fprintf(s.Readline, "sh: %v\n", err)
+		}
+		return !s.Quit
+	})
+	if err != nil {
+		// Like other shells, scripts stop at the first syntax error.
+		fmt.Fprintf(s.Readline, "sh: syntax error: %v\n", err)
+		return 2
+	}
+
+	return s.lastRet
+}
+
+// readScript reads a script from the virtual filesystem.
+func (s *Shell) readScript(name string) (string, error) {
+	fd, err := s.VirtualOS.Open(name)
+	if err != nil {
+		return "", errors.New("No such file")
+	}
+	defer fd.Close()
+
+	info, err := fd.Stat()
+	switch {
+	case err != nil:
+		return "", err
+	case info.IsDir():
+		return "", errors.New("Is a directory")
+	}
+
+	script, err := io.ReadAll(io.LimitReader(fd, maxScriptSize))
+	if err != nil {
+		return "", err
+	}
+	return string(script), nil
+}
+
 func init() {
 	mustAddBinCmd("sh", RunShell)
+	mustAddBinCmd("bash", RunShell)
+	mustAddBinCmd("dash", RunShell)
 }
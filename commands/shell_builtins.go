@@ -125,14 +125,29 @@ func Help(s *Shell, args []string) int {
 	return 0
 }
 
-func NopBuiltin(s *Shell, args []string) int {
-	return 0
+// Source runs a script in the current shell.
+func Source(s *Shell, args []string) int {
+	if len(args) < 2 {
+		fmt.Fprintf(s.VirtualOS.Stderr(), "sh: %s: filename argument required\n", args[0])
+		return 2
+	}
+	if s.sourceDepth >= maxShellLevel {
+		fmt.Fprintf(s.VirtualOS.Stderr(), "sh: %s: maximum nesting level exceeded\n", args[0])
+		return 2
+	}
+	s.sourceDepth++
+	defer func() { s.sourceDepth-- }()
+
+	// Positional parameters are only replaced if new ones are given.
+	scriptArgs := s.positional()
+	if len(args) > 2 {
+		scriptArgs = append([]string{scriptArgs[0]}, args[2:]...)
+	}
+	return s.runScript(args[1], scriptArgs)
 }
 
-func Sudo(s *Shell, args []string) int {
-	remainder := args[1:]
-	s.runCommand(strings.Join(remainder, " "))
-	return s.lastRet
+func NopBuiltin(s *Shell, args []string) int {
+	return 0
 }
 
 func init() {
@@ -142,11 +157,11 @@ func init() {
 	AllBuiltins["help"] = ShellBuiltinFunc(Help)
 	AllBuiltins["exit"] = ShellBuiltinFunc(Exit)
 	AllBuiltins["logout"] = ShellBuiltinFunc(Exit) // matches exit
-	AllBuiltins["sudo"] = ShellBuiltinFunc(Sudo)
+	AllBuiltins["."] = ShellBuiltinFunc(Source)
+	AllBuiltins["source"] = ShellBuiltinFunc(Source)
 
 	// Nops
 	AllBuiltins["set"] = ShellBuiltinFunc(NopBuiltin)
-	AllBuiltins["su"] = ShellBuiltinFunc(NopBuiltin)
 	AllBuiltins["export"] = ShellBuiltinFunc(NopBuiltin)
 	AllBuiltins["disown"] = ShellBuiltinFunc(NopBuiltin)
 }
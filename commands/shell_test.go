@@ -1,7 +1,12 @@
 package commands
 
 import (
+	"strings"
 	"testing"
+
+	"github.com/josephlewis42/honeyssh/core/vos"
+	"github.com/josephlewis42/honeyssh/core/vos/vostest"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestRunShell(t *testing.T) {
@@ -33,3 +38,124 @@ func TestRunShell(t *testing.T) {
 
 	cases.Run(t, RunShell)
 }
+
+func TestRunShell_scripts(t *testing.T) {
+	cases := map[string]struct {
+		files      map[string]string
+		args       []string
+		wantOut    string
+		wantStatus int
+	}{
+		"sh script": {
+			files:   map[string]string{"/x.sh": "#!/bin/sh\n/bin/echo $0 $1 $# $@\n"},
+			args:    []string{"sh", "/x.sh", "a", "b"},
+			wantOut: "/x.sh a 2 a b\n",
+		},
+		"quoted args": {
+			files: map[string]string{
+				"/x.sh": "#!/bin/sh\n/bin/sh /y.sh \"$@\"\n/bin/sh /y.sh \"$*\"\n/bin/sh /y.sh \"-$@-\"\n",
+				"/y.sh": "/bin/echo $# \"[$1]\" \"[$2]\"\n",
+			},
+			args:    []string{"sh", "/x.sh", "a b", "c"},
+			wantOut: "2 [a b] [c]\n1 [a b c] []\n2 [-a b] [c-]\n",
+		},
+		"quoted args empty": {
+			files: map[string]string{
+				"/x.sh": "/bin/sh /y.sh \"$@\"\n/bin/sh /y.sh \"$*\"\n",
+				"/y.sh": "/bin/echo $#\n",
+			},
+			args:    []string{"sh", "/x.sh"},
+			wantOut: "0\n1\n",
+		},
+		"for loop": {
+			files:   map[string]string{"/x.sh": "for f in \"$@\"; do /bin/echo \"<$f>\"; done\nfor f; do /bin/echo $f; done\nfor f in x y; do A=$f; /bin/echo $A; done\n"},
+			args:    []string{"sh", "/x.sh", "a b", "c"},
+			wantOut: "<a b>\n<c>\na b\nc\nx\ny\n",
+		},
+		"bash script": {
+			files:   map[string]string{"/x.sh": "/bin/echo $0 $#\n"},
+			args:    []string{"bash", "/x.sh"},
+			wantOut: "/x.sh 0\n",
+		},
+		"exec shebang": {
+			files:   map[string]string{"/x.sh": "#!/usr/bin/env bash\n/bin/echo $0 $1\n"},
+			args:    []string{"sh", "-c", "/x.sh a"},
+			wantOut: "/x.sh a\n",
+		},
+		"source": {
+			files:   map[string]string{"/x.sh": "/bin/echo $0 $1\nA=B\n"},
+			args:    []string{"sh", "-c", ". /x.sh c; /bin/echo $A"},
+			wantOut: "sh c\nB\n",
+		},
+		"multiline statements": {
+			files:   map[string]string{"/x.sh": "/bin/echo a \\\n  b\n\n# comment\n/bin/echo c\n"},
+			args:    []string{"sh", "/x.sh"},
+			wantOut: "a b\nc\n",
+		},
+		"multiline quote": {
+			files:   map[string]string{"/x.sh": "/bin/echo 'a\nb'; /bin/echo c\n/bin/echo d\n"},
+			args:    []string{"sh", "/x.sh"},
+			wantOut: "a\nb\nc\nd\n",
+		},
+		"syntax error": {
+			files:      map[string]string{"/x.sh": "/bin/echo a\n/bin/echo ) b\n/bin/echo c\n"},
+			args:       []string{"sh", "/x.sh"},
+			wantOut:    "a\nsh: syntax error: 2:11: a command can only contain words and redirects; encountered )\n",
+			wantStatus: 2,
+		},
+		"unterminated statement": {
+			files:      map[string]string{"/x.sh": "/bin/echo a\nif true; then\n" + strings.Repeat("/bin/echo b\n", 10000)},
+			args:       []string{"sh", "/x.sh"},
+			wantOut:    "a\nsh: syntax error: 2:1: if statement must end with \"fi\"\n",
+			wantStatus: 2,
+		},
+		"exit": {
+			files:   map[string]string{"/x.sh": "/bin/echo a\nexit\n/bin/echo b\n"},
+			args:    []string{"sh", "/x.sh"},
+			wantOut: "a\n",
+		},
+		"recursion": {
+			files:      map[string]string{"/x.sh": "#!/bin/sh\n/x.sh\n"},
+			args:       []string{"sh", "-c", "/x.sh"},
+			wantOut:    "sh: maximum nesting level exceeded (32)\n",
+			wantStatus: 2,
+		},
+		"binary": {
+			files:      map[string]string{"/x": "\x7fELF"},
+			args:       []string{"sh", "-c", "/x"},
+			wantOut:    "/x: Segmentation fault\n",
+			wantStatus: 1,
+		},
+		"missing": {
+			args:       []string{"sh", "/x.sh"},
+			wantOut:    "sh: 0: cannot open /x.sh: No such file\n",
+			wantStatus: 2,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			cmd := vostest.Command(RunShell, tc.args[0], tc.args[1:]...)
+			cmd.ProcessResolver = BuiltinProcessResolver
+			cmd.Setup = func(virtOS vos.VOS) error {
+				for name, contents := range tc.files {
+					fd, err := virtOS.Create(name)
+					if err != nil {
+						return err
+					}
+					fd.WriteString(contents)
+					fd.Close()
+					if err := virtOS.Chmod(name, 0755); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+
+			out, err := cmd.CombinedOutput()
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantOut, string(out))
+			assert.Equal(t, tc.wantStatus, cmd.ExitStatus)
+		})
+	}
+}
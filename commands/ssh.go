@@ -0,0 +1,198 @@
+package commands
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/abiosoft/readline"
+	"github.com/josephlewis42/honeyssh/core/logger"
+	"github.com/josephlewis42/honeyssh/core/vos"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// sshAttempts is the number of passwords ssh asks for before giving up.
+const sshAttempts = 3
+
+// Ssh implements a fake OpenSSH client. Connections to configured internal
+// hosts log in to a nested session on the host, others are always denied.
+func Ssh(virtOS vos.VOS) int {
+	cmd := &SimpleCommand{
+		Use:   "ssh [-46AaCfGgKkMNnqsTtVvXxYy] [-i identity_file] [-l login_name] [-o option] [-p port] destination [command [argument ...]]",
+		Short: "OpenSSH remote login client.",
+	}
+
+	for _, flag := range "46AaCfGgKkMNnqsTtVvXxYy" {
+		_ = cmd.Flags().Bool(flag, "")
+	}
+	for _, flag := range "BbcDEeFIJLmORSWw" {
+		_ = cmd.Flags().String(flag, "", "")
+	}
+	_ = cmd.Flags().String('i', "", "Identity file.")
+	_ = cmd.Flags().String('o', "", "Option in the format used in the configuration file.")
+	login := cmd.Flags().String('l', "", "User to log in as on the remote machine.")
+	port := cmd.Flags().String('p', "22", "Port to connect to on the remote host.")
+
+	return cmd.Run(virtOS, func() int {
+		args := cmd.Flags().Args()
+		if len(args) == 0 {
+			cmd.PrintHelp(virtOS.Stderr())
+			return 255
+		}
+
+		user, host, destPort := parseSSHDestination(args[0])
+		if user == "" {
+			user = *login
+		}
+		if user == "" {
+			user = currentUser(virtOS)
+		}
+		if destPort == "" {
+			destPort = *port
+		}
+		if _, err := strconv.ParseUint(destPort, 10, 16); err != nil {
+			fmt.Fprintf(virtOS.Stderr(), "Bad port '%s'\n", destPort)
+			return 255
+		}
+
+		rl, err := newPasswordReader(virtOS)
+		if err != nil {
+			return 255
+		}
+		defer rl.Close()
+
+		if !verifyHostKey(virtOS, rl, host) {
+			fmt.Fprintln(virtOS.Stderr(), "Host key verification failed.")
+			return 255
+		}
+
+		internalHost, reachable := virtOS.InternalHost(host)
+		target := (&url.URL{Scheme: "ssh", Host: net.JoinHostPort(host, destPort)}).String()
+		for i := 0; i < sshAttempts; i++ {
+			password, err := rl.ReadPassword(fmt.Sprintf("%s@%s's password: ", user, host))
+			if err != nil {
+				return 255
+			}
+			virtOS.LogCreds(&logger.Credentials{
+				Username: user,
+				Password: string(password),
+				Target:   target,
+			})
+
+			if reachable && (len(internalHost.Passwords) == 0 || slices.Contains(internalHost.Passwords, string(password))) {
+				argv := []string{"/bin/sh"}
+				if len(args) > 1 {
+					argv = append(argv, "-c", strings.Join(args[1:], " "))
+				}
+				return virtOS.Pivot(internalHost, host, user, argv, virtOS)
+			}
+
+			if i < sshAttempts-1 {
+				fmt.Fprintln(virtOS.Stderr(), "Permission denied, please try again.")
+			}
+		}
+
+		fmt.Fprintf(virtOS.Stderr(), "%s@%s: Permission denied (publickey,password).\n", user, host)
+		return 255
+	})
+}
+
+// parseSSHDestination splits a destination of the form [user@]host or
+// ssh://[user@]host[:port].
+func parseSSHDestination(dest string) (user, host, port string) {
+	if strings.HasPrefix(dest, "ssh://") {
+		if u, err := url.Parse(dest); err == nil {
+			return u.User.Username(), u.Hostname(), u.Port()
+		}
+	}
+
+	if idx := strings.LastIndex(dest, "@"); idx >= 0 {
+		user, dest = dest[:idx], dest[idx+1:]
+	}
+	return user, dest, ""
+}
+
+// fakeHostKey returns a stable host key for the host.
+func fakeHostKey(host string) gossh.PublicKey {
+	seed := sha256.Sum256([]byte(host))
+	key, err := gossh.NewPublicKey(ed25519.NewKeyFromSeed(seed[:]).Public())
+	if err != nil {
+		// Ed25519 keys are always supported.
+		panic(err)
+	}
+	return key
+}
+
+// verifyHostKey asks the user to accept the host's key unless it's in their
+// known_hosts file. It returns false if the key was rejected.
+func verifyHostKey(virtOS vos.VOS, rl *readline.Instance, host string) bool {
+	knownHosts := path.Join(virtOS.Getenv(EnvHome), ".ssh", "known_hosts")
+	if isKnownHost(virtOS, knownHosts, host) {
+		return true
+	}
+
+	key := fakeHostKey(host)
+	fingerprint := gossh.FingerprintSHA256(key)
+	fmt.Fprintf(virtOS.Stdout(), "The authenticity of host '%[1]s (%[1]s)' can't be established.\n", host)
+	fmt.Fprintf(virtOS.Stdout(), "ED25519 key fingerprint is %s.\n", fingerprint)
+	fmt.Fprintln(virtOS.Stdout(), "This key is not known by any other names")
+
+	rl.SetPrompt("Are you sure you want to continue connecting (yes/no/[fingerprint])? ")
+	for {
+		answer, err := rl.Readline()
+		if err != nil {
+			return false
+		}
+		switch strings.TrimSpace(answer) {
+		case "yes", fingerprint:
+			fmt.Fprintf(virtOS.Stderr(), "Warning: Permanently added '%s' (ED25519) to the list of known hosts.\n", host)
+			addKnownHost(virtOS, knownHosts, host, key)
+			return true
+		case "no":
+			return false
+		}
+		rl.SetPrompt("Please type 'yes', 'no' or the fingerprint: ")
+	}
+}
+
+func isKnownHost(virtOS vos.VOS, knownHosts, host string) bool {
+	fd, err := virtOS.Open(knownHosts)
+	if err != nil {
+		return false
+	}
+	defer fd.Close()
+
+	scanner := bufio.NewScanner(fd)
+	for scanner.Scan() {
+		if hosts, _, ok := strings.Cut(scanner.Text(), " "); ok && slices.Contains(strings.Split(hosts, ","), host) {
+			return true
+		}
+	}
+	return false
+}
+
+func addKnownHost(virtOS vos.VOS, knownHosts, host string, key gossh.PublicKey) {
+	if err := virtOS.MkdirAll(path.Dir(knownHosts), 0700); err != nil {
+		return
+	}
+	fd, err := virtOS.OpenFile(knownHosts, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return
+	}
+	defer fd.Close()
+	fmt.Fprintf(fd, "%s %s", host, gossh.MarshalAuthorizedKey(key))
+}
+
+var _ vos.ProcessFunc = Ssh
+
+func init() {
+	mustAddBinCmd("ssh", Ssh)
+}
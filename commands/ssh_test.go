@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/josephlewis42/honeyssh/core/config"
+	"github.com/josephlewis42/honeyssh/core/logger"
+	"github.com/josephlewis42/honeyssh/core/vos"
+	"github.com/josephlewis42/honeyssh/core/vos/vostest"
+	"github.com/josephlewis42/honeyssh/third_party/memmapfs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSsh_pivot(t *testing.T) {
+	cfg := &config.Configuration{
+		InternalHosts: []config.InternalHost{
+			{
+				Addresses: []string{"10.0.0.*"},
+				Uname:     config.Uname{Nodename: "db01", KernelName: "Linux", HardwarePlatform: "aarch64"},
+				Passwords: []string{"s3cret"},
+			},
+		},
+		OS:    config.OS{DefaultShell: "/bin/sh", DefaultPath: "/bin"},
+		Uname: config.Uname{Nodename: "web01", KernelName: "Linux", HardwarePlatform: "x86_64"},
+	}
+
+	timeSource := func() time.Time {
+		return time.Date(2006, 1, 2, 3, 4, 5, 0, time.UTC)
+	}
+	sharedOS := vos.NewSharedOS(memmapfs.NewMemMapFs(timeSource), BuiltinProcessResolver, cfg, timeSource)
+	var entries []*logger.LogEntry
+	sessionLogger := (&logger.Logger{Record: func(le *logger.LogEntry) error {
+		entries = append(entries, le)
+		return nil
+	}}).NewSession("parent")
+	procOS := vos.NewTenantOS(sharedOS, sessionLogger, &vostest.FakeSSHSession{}).LoginProc()
+	procOS.Setenv(EnvPath, "/bin")
+	procOS.Setenv(EnvHome, "/root")
+
+	run := func(stdin string, argv ...string) string {
+		out := &bytes.Buffer{}
+		proc, err := procOS.StartProcess(argv[0], argv, &vos.ProcAttr{
+			Files: vos.NewVIOAdapter(strings.NewReader(stdin), out, out),
+		})
+		assert.NoError(t, err)
+		proc.Run()
+		return out.String()
+	}
+
+	// The host key is accepted the first time, then remembered.
+	out := run("yes\nwrong\ns3cret\n", "ssh", "admin@10.0.0.5", "uname", "-nm")
+	assert.Contains(t, out, "Warning: Permanently added '10.0.0.5' (ED25519) to the list of known hosts.\n")
+	assert.True(t, strings.HasSuffix(out, "Permission denied, please try again.\ndb01 aarch64\n"), out)
+
+	out = run("s3cret\n", "ssh", "admin@10.0.0.5", "uname", "-n")
+	assert.Equal(t, "db01\n", out)
+
+	// The honeypot itself is unchanged.
+	assert.Equal(t, "web01\n", run("", "uname", "-n"))
+
+	var pivots []*logger.Pivot
+	children := make(map[string]string)
+	for _, entry := range entries {
+		if pivot := entry.GetPivot(); pivot != nil {
+			assert.Equal(t, "parent", entry.GetSessionId())
+			pivots = append(pivots, pivot)
+		}
+		if entry.GetParentSessionId() != "" {
+			children[entry.GetSessionId()] = entry.GetParentSessionId()
+		}
+	}
+	if assert.Len(t, pivots, 2) {
+		assert.Equal(t, "db01", pivots[0].GetHostname())
+		assert.Equal(t, "admin", pivots[0].GetUsername())
+		assert.Equal(t, []string{"-c", "uname -nm"}, pivots[0].GetCommand())
+	}
+	assert.Equal(t, map[string]string{"parent.1": "parent", "parent.2": "parent"}, children)
+}
@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/josephlewis42/honeyssh/core/config"
+	"github.com/josephlewis42/honeyssh/core/logger"
+	"github.com/josephlewis42/honeyssh/core/vos"
+)
+
+// Su implements a fake su command.
+func Su(virtOS vos.VOS) int {
+	cmd := &SimpleCommand{
+		Use:   "su [options] [-] [<user> [<argument>...]]",
+		Short: "Change the effective user ID and group ID to that of <user>.",
+
+		// Never bail, even if args are bad.
+		NeverBail: true,
+	}
+
+	_ = cmd.Flags().BoolLong("login", 'l', "Start the shell as a login shell.")
+	command := cmd.Flags().StringLong("command", 'c', "", "Pass a command to the shell.")
+	shell := cmd.Flags().StringLong("shell", 's', "", "Run the specified shell.")
+
+	return cmd.Run(virtOS, func() int {
+		args := cmd.Flags().Args()
+		if len(args) > 0 && args[0] == "-" {
+			args = args[1:]
+		}
+
+		username := "root"
+		if len(args) > 0 {
+			username = args[0]
+		}
+
+		usr, ok := lookupUser(virtOS, username)
+		if !ok {
+			fmt.Fprintf(virtOS.Stderr(), "su: user %s does not exist or the user entry does not contain all the required fields\n", username)
+			return 1
+		}
+
+		// Root can switch to any user without a password.
+		if virtOS.Getuid() != 0 {
+			password, err := readPassword(virtOS, "Password: ")
+			if err != nil {
+				return 1
+			}
+			virtOS.LogCreds(&logger.Credentials{
+				Username: username,
+				Password: password,
+				Target:   "su",
+			})
+			if !virtOS.CheckPassword(username, password) {
+				fmt.Fprintln(virtOS.Stderr(), "su: Authentication failure")
+				return 1
+			}
+		}
+
+		if *shell != "" {
+			usr.Shell = *shell
+		}
+		argv := []string{usr.Shell}
+		if *command != "" {
+			argv = append(argv, "-c", *command)
+		}
+
+		return runAsUser(virtOS, usr, argv)
+	})
+}
+
+// lookupUser finds the configured user, root always exists.
+func lookupUser(virtOS vos.VOS, username string) (usr config.User, ok bool) {
+	usr, ok = virtOS.GetUser(username)
+	if !ok && username == "root" {
+		usr, ok = config.User{Username: "root", UID: 0, GID: 0, Home: "/root"}, true
+	}
+	switch {
+	case usr.Home != "":
+	case usr.UID == 0:
+		usr.Home = "/root"
+	default:
+		usr.Home = "/home/" + usr.Username
+	}
+	if usr.Shell == "" {
+		usr.Shell = "/bin/sh"
+	}
+	return usr, ok
+}
+
+// runAsUser runs argv as the user and returns its exit status.
+func runAsUser(virtOS vos.VOS, usr config.User, argv []string) int {
+	env := vos.NewMapEnvFromEnvList(virtOS.Environ())
+	env.Setenv(EnvUser, usr.Username)
+	env.Setenv("LOGNAME", usr.Username)
+	env.Setenv(EnvHome, usr.Home)
+	env.Setenv("SHELL", usr.Shell)
+
+	proc, err := virtOS.StartProcess(argv[0], argv, &vos.ProcAttr{
+		Env:   env.Environ(),
+		Files: virtOS,
+	})
+	if err != nil {
+		fmt.Fprintf(virtOS.Stderr(), "%s: %s: command not found\n", virtOS.Args()[0], argv[0])
+		return 1
+	}
+	proc.Setuid(usr.UID)
+	return proc.Run()
+}
+
+var _ vos.ProcessFunc = Su
+
+func init() {
+	mustAddBinCmd("su", Su)
+}
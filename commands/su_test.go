@@ -0,0 +1,204 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/josephlewis42/honeyssh/core/config"
+	"github.com/josephlewis42/honeyssh/core/logger"
+	"github.com/josephlewis42/honeyssh/core/vos"
+	"github.com/josephlewis42/honeyssh/core/vos/vostest"
+	"github.com/josephlewis42/honeyssh/third_party/memmapfs"
+	"github.com/stretchr/testify/assert"
+)
+
+type credentialRecorder struct {
+	vostest.NopEventRecorder
+
+	credentials []*logger.Credentials
+}
+
+func (r *credentialRecorder) Record(event logger.LogType) error {
+	if creds, ok := event.(*logger.LogEntry_UsedCredentials); ok {
+		r.credentials = append(r.credentials, creds.UsedCredentials)
+	}
+	return nil
+}
+
+// userSession is an SSH session logged in as user.
+type userSession struct {
+	vostest.FakeSSHSession
+
+	user string
+}
+
+func (s *userSession) User() string {
+	return s.user
+}
+
+func TestCredentialCapture(t *testing.T) {
+	baseCfg := config.Configuration{
+		Users: []config.User{
+			{Username: "root", UID: 0, Passwords: []string{"toor"}},
+			{Username: "admin", UID: 1000, Passwords: []string{"letmein"}},
+		},
+	}
+
+	cases := map[string]struct {
+		uid       int
+		argv      []string
+		stdin     string
+		wantOut   string
+		wantCreds []*logger.Credentials
+
+		allowAnyPassword bool
+		loginPassword    string
+	}{
+		"su correct password": {
+			uid:     1000,
+			argv:    []string{"su", "-c", "id", "root"},
+			stdin:   "toor\n",
+			wantOut: "uid=0(root) gid=0(root) groups=0(root)\n",
+			wantCreds: []*logger.Credentials{
+				{Username: "root", Password: "toor", Target: "su"},
+			},
+		},
+		"su wrong password": {
+			uid:     1000,
+			argv:    []string{"su", "-"},
+			stdin:   "hunter2\n",
+			wantOut: "su: Authentication failure\n",
+			wantCreds: []*logger.Credentials{
+				{Username: "root", Password: "hunter2", Target: "su"},
+			},
+		},
+		"su as root": {
+			argv:    []string{"su", "-c", "id", "admin"},
+			wantOut: "uid=1000(admin) gid=1000(admin) groups=1000(admin)\n",
+		},
+		"su unknown user": {
+			argv:    []string{"su", "nobody"},
+			wantOut: "su: user nobody does not exist or the user entry does not contain all the required fields\n",
+		},
+		"sudo stdin": {
+			uid:     1000,
+			argv:    []string{"sudo", "-S", "id"},
+			stdin:   "wrong\nletmein\n",
+			wantOut: "[sudo] password for admin: Sorry, try again.\n[sudo] password for admin: uid=0(root) gid=0(root) groups=0(root)\n",
+			wantCreds: []*logger.Credentials{
+				{Username: "admin", Password: "wrong", Target: "sudo"},
+				{Username: "admin", Password: "letmein", Target: "sudo"},
+			},
+		},
+		"sudo allow any password": {
+			uid:              1000,
+			argv:             []string{"sudo", "-S", "id"},
+			stdin:            "hunter2\n",
+			allowAnyPassword: true,
+			wantOut:          "[sudo] password for admin: uid=0(root) gid=0(root) groups=0(root)\n",
+			wantCreds: []*logger.Credentials{
+				{Username: "admin", Password: "hunter2", Target: "sudo"},
+			},
+		},
+		"sudo login password": {
+			uid:           1000,
+			argv:          []string{"sudo", "-S", "id"},
+			stdin:         "ssh-pass\n",
+			loginPassword: "ssh-pass",
+			wantOut:       "[sudo] password for admin: uid=0(root) gid=0(root) groups=0(root)\n",
+			wantCreds: []*logger.Credentials{
+				{Username: "admin", Password: "ssh-pass", Target: "sudo"},
+			},
+		},
+		"su login password is only for the login user": {
+			uid:           1000,
+			argv:          []string{"su", "-"},
+			stdin:         "ssh-pass\n",
+			loginPassword: "ssh-pass",
+			wantOut:       "su: Authentication failure\n",
+			wantCreds: []*logger.Credentials{
+				{Username: "root", Password: "ssh-pass", Target: "su"},
+			},
+		},
+		"sudo no password": {
+			uid:     1000,
+			argv:    []string{"sudo", "-S", "id"},
+			wantOut: "[sudo] password for admin: sudo: no password was provided\n",
+		},
+		"sudo non-interactive": {
+			uid:     1000,
+			argv:    []string{"sudo", "-n", "id"},
+			wantOut: "sudo: a password is required\n",
+		},
+		"sudo as root": {
+			argv:    []string{"sudo", "-u", "admin", "id"},
+			wantOut: "uid=1000(admin) gid=1000(admin) groups=1000(admin)\n",
+		},
+		"ssh": {
+			argv:    []string{"ssh", "-p", "2222", "deploy@10.0.0.5"},
+			stdin:   "yes\na\nb\nc\n",
+			wantOut: "The authenticity of host '10.0.0.5 (10.0.0.5)' can't be established.\nED25519 key fingerprint is SHA256:2mb7nk7zIWXzzs8U2r0bZa2CLm+clWrrH/x5A6g/8h0.\nThis key is not known by any other names\nWarning: Permanently added '10.0.0.5' (ED25519) to the list of known hosts.\nPermission denied, please try again.\nPermission denied, please try again.\ndeploy@10.0.0.5: Permission denied (publickey,password).\n",
+			wantCreds: []*logger.Credentials{
+				{Username: "deploy", Password: "a", Target: "ssh://10.0.0.5:2222"},
+				{Username: "deploy", Password: "b", Target: "ssh://10.0.0.5:2222"},
+				{Username: "deploy", Password: "c", Target: "ssh://10.0.0.5:2222"},
+			},
+		},
+		"mysql prompt": {
+			argv:    []string{"mysql", "-uroot", "-p"},
+			stdin:   "secret\n",
+			wantOut: "ERROR 1045 (28000): Access denied for user 'root'@'localhost' (using password: YES)\n",
+			wantCreds: []*logger.Credentials{
+				{Username: "root", Password: "secret", Target: "mysql://localhost:3306"},
+			},
+		},
+		"mysql inline password": {
+			argv:    []string{"mysql", "-h", "db01", "--user=app", "-papp123"},
+			wantOut: "ERROR 2003 (HY000): Can't connect to MySQL server on 'db01:3306' (111)\n",
+			wantCreds: []*logger.Credentials{
+				{Username: "app", Password: "app123", Target: "mysql://db01:3306"},
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			timeSource := func() time.Time {
+				return time.Date(2006, 1, 2, 3, 4, 5, 0, time.UTC)
+			}
+			cfg := baseCfg
+			cfg.AllowAnyPassword = tc.allowAnyPassword
+			sharedOS := vos.NewSharedOS(memmapfs.NewMemMapFs(timeSource), BuiltinProcessResolver, &cfg, timeSource)
+			recorder := &credentialRecorder{}
+			tenantOS := vos.NewTenantOS(sharedOS, recorder, &userSession{user: "admin"})
+			tenantOS.SetLoginPassword(tc.loginPassword)
+			procOS := tenantOS.LoginProc()
+
+			procOS.Setenv(EnvPath, "/bin")
+			procOS.Setenv(EnvUser, "root")
+			if tc.uid != 0 {
+				procOS.Setenv(EnvUser, "admin")
+			}
+
+			out := &bytes.Buffer{}
+			proc, err := procOS.StartProcess(tc.argv[0], tc.argv, &vos.ProcAttr{
+				Files: vos.NewVIOAdapter(strings.NewReader(tc.stdin), out, out),
+			})
+			if !assert.NoError(t, err) {
+				return
+			}
+			proc.Setuid(tc.uid)
+			proc.Run()
+
+			assert.Equal(t, tc.wantOut, out.String())
+			assert.Len(t, recorder.credentials, len(tc.wantCreds))
+			for i, want := range tc.wantCreds {
+				if i < len(recorder.credentials) {
+					assert.Equal(t, want.String(), recorder.credentials[i].String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/josephlewis42/honeyssh/core/logger"
+	"github.com/josephlewis42/honeyssh/core/vos"
+)
+
+// sudoAttempts is the number of passwords sudo accepts before giving up.
+const sudoAttempts = 3
+
+// Sudo implements a fake sudo command.
+func Sudo(virtOS vos.VOS) int {
+	cmd := &SimpleCommand{
+		Use:   "sudo [-Sins] [-u user] [command [arg ...]]",
+		Short: "Execute a command as another user.",
+
+		// Never bail, even if args are bad.
+		NeverBail: true,
+	}
+
+	stdin := cmd.Flags().BoolLong("stdin", 'S', "Read password from standard input.")
+	nonInteractive := cmd.Flags().BoolLong("non-interactive", 'n', "Non-interactive mode, no prompts are used.")
+	login := cmd.Flags().BoolLong("login", 'i', "Run login shell as the target user.")
+	shell := cmd.Flags().BoolLong("shell", 's', "Run shell as the target user.")
+	_ = cmd.Flags().BoolLong("reset-timestamp", 'k', "Invalidate timestamp file.")
+	username := cmd.Flags().StringLong("user", 'u', "root", "Run command as specified user name or ID.")
+
+	return cmd.Run(virtOS, func() int {
+		args := cmd.Flags().Args()
+		if len(args) == 0 && !*login && !*shell {
+			cmd.PrintHelp(virtOS.Stderr())
+			return 1
+		}
+
+		usr, ok := lookupUser(virtOS, *username)
+		if !ok {
+			fmt.Fprintf(virtOS.Stderr(), "sudo: unknown user: %s\n", *username)
+			return 1
+		}
+
+		// Root never needs to authenticate.
+		if virtOS.Getuid() != 0 {
+			if *nonInteractive {
+				fmt.Fprintln(virtOS.Stderr(), "sudo: a password is required")
+				return 1
+			}
+			if !sudoAuthenticate(virtOS, *stdin) {
+				return 1
+			}
+		}
+
+		argv := args
+		if *login || *shell {
+			argv = []string{usr.Shell}
+			if len(args) > 0 {
+				argv = append(argv, "-c", strings.Join(args, " "))
+			}
+		}
+		return runAsUser(virtOS, usr, argv)
+	})
+}
+
+// sudoAuthenticate prompts for the caller's password and returns true if a
+// correct one is given.
+func sudoAuthenticate(virtOS vos.VOS, stdin bool) bool {
+	caller := currentUser(virtOS)
+	prompt := fmt.Sprintf("[sudo] password for %s: ", caller)
+
+	nextPassword := func() (string, error) {
+		fmt.Fprint(virtOS.Stderr(), prompt)
+		return readLine(virtOS.Stdin())
+	}
+	if !stdin {
+		rl, err := newPasswordReader(virtOS)
+		if err != nil {
+			return false
+		}
+		defer rl.Close()
+		nextPassword = func() (string, error) {
+			password, err := rl.ReadPassword(prompt)
+			return string(password), err
+		}
+	}
+
+	for i := 0; i < sudoAttempts; i++ {
+		password, err := nextPassword()
+		if err != nil {
+			fmt.Fprintln(virtOS.Stderr(), "sudo: no password was provided")
+			return false
+		}
+
+		virtOS.LogCreds(&logger.Credentials{
+			Username: caller,
+			Password: password,
+			Target:   "sudo",
+		})
+		if virtOS.CheckPassword(caller, password) {
+			return true
+		}
+		fmt.Fprintln(virtOS.Stderr(), "Sorry, try again.")
+	}
+
+	fmt.Fprintf(virtOS.Stderr(), "sudo: %d incorrect password attempts\n", sudoAttempts)
+	return false
+}
+
+var _ vos.ProcessFunc = Sudo
+
+func init() {
+	mustAddBinCmd("sudo", Sudo)
+}
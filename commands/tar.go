@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/josephlewis42/honeyssh/core/vos"
+	"github.com/spf13/afero"
 )
 
 // Tar implements a basic tar command.
@@ -21,6 +22,7 @@ func Tar(virtOS vos.VOS) int {
 		NeverBail: true,
 	}
 
+	create := cmd.Flags().Bool('c', "Create a new archive")
 	extract := cmd.Flags().Bool('x', "Extract files")
 	verbose := cmd.Flags().Bool('v', "Verbose mode")
 	unzip := cmd.Flags().Bool('z', "Unzip archive")
@@ -32,6 +34,10 @@ func Tar(virtOS vos.VOS) int {
 			return 1
 		}
 
+		if *create {
+			return tarCreate(virtOS, *archive, *unzip, *verbose, cmd.Flags().Args())
+		}
+
 		archiveFd, err := virtOS.Open(*archive)
 		if err != nil {
 			fmt.Fprintf(virtOS.Stderr(), "tar: couldn't open archive %q: %v\n", *archive, err)
@@ -99,6 +105,81 @@ func Tar(virtOS vos.VOS) int {
 	})
 }
 
+// tarCreate writes the paths to a new archive, "-" writes to stdout.
+func tarCreate(virtOS vos.VOS, archive string, gz, verbose bool, paths []string) int {
+	if len(paths) == 0 {
+		fmt.Fprintln(virtOS.Stderr(), "tar: Cowardly refusing to create an empty archive")
+		return 2
+	}
+
+	var out io.Writer = virtOS.Stdout()
+	if archive != "-" {
+		fd, err := virtOS.Create(archive)
+		if err != nil {
+			fmt.Fprintf(virtOS.Stderr(), "tar: %s: Cannot open: %v\n", archive, err)
+			return 2
+		}
+		defer fd.Close()
+		out = fd
+	}
+	if gz {
+		gzWriter := gzip.NewWriter(out)
+		defer gzWriter.Close()
+		out = gzWriter
+	}
+	tarWriter := tar.NewWriter(out)
+	defer tarWriter.Close()
+
+	status := 0
+	warnedLeadingSlash := false
+	for _, root := range paths {
+		err := afero.Walk(virtOS, root, func(name string, info fs.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if strings.HasPrefix(name, "/") && !warnedLeadingSlash {
+				fmt.Fprintln(virtOS.Stderr(), "tar: Removing leading `/' from member names")
+				warnedLeadingSlash = true
+			}
+			if verbose {
+				fmt.Fprintln(virtOS.Stdout(), name)
+			}
+			return tarAddFile(virtOS, tarWriter, name, info)
+		})
+		if err != nil {
+			fmt.Fprintf(virtOS.Stderr(), "tar: %s: %v\n", root, err)
+			status = 2
+		}
+	}
+	return status
+}
+
+// tarAddFile writes the file to the archive.
+func tarAddFile(virtOS vos.VOS, tarWriter *tar.Writer, name string, info fs.FileInfo) error {
+	hdr, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	hdr.Name = strings.TrimPrefix(name, "/")
+	if info.IsDir() {
+		hdr.Name += "/"
+	}
+	if err := tarWriter.WriteHeader(hdr); err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+
+	fd, err := virtOS.Open(name)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+	_, err = io.Copy(tarWriter, fd)
+	return err
+}
+
 var _ vos.ProcessFunc = Tar
 
 func init() {
@@ -49,24 +49,27 @@ func wgetSocketControl(network string, address string, conn syscall.RawConn) err
 	return nil
 }
 
-var wgetDialer = &net.Dialer{
-	Timeout: 5 * time.Second,
-	Control: wgetSocketControl,
-}
+// newWgetHTTPClient creates a client that checks connections with control.
+func newWgetHTTPClient(control func(network string, address string, conn syscall.RawConn) error) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: control,
+	}
 
-var wgetTransport = &http.Transport{
-	Proxy:                 http.ProxyFromEnvironment,
-	ForceAttemptHTTP2:     true,
-	MaxIdleConns:          10,
-	IdleConnTimeout:       90 * time.Second,
-	TLSHandshakeTimeout:   5 * time.Second,
-	ExpectContinueTimeout: 1 * time.Second,
-	DialContext:           wgetDialer.DialContext,
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:                 http.ProxyFromEnvironment,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          10,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   5 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+			DialContext:           dialer.DialContext,
+		},
+	}
 }
 
-var wgetHTTPClient = &http.Client{
-	Transport: wgetTransport,
-}
+var wgetHTTPClient = newWgetHTTPClient(wgetSocketControl)
 
 func newReadline(virtOS vos.VOS) (*readline.Instance, error) {
 	cfg := &readline.Config{
@@ -151,7 +154,7 @@ func Wget(virtOS vos.VOS) int {
 		}
 		request.Header.Set("User-Agent", "Wget/1.20.2")
 
-		response, err := wgetHTTPClient.Do(request)
+		response, err := httpClient(virtOS).Do(request)
 		if err != nil {
 			return err
 		}
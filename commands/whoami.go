@@ -18,7 +18,7 @@ func Whoami(virtOS vos.VOS) int {
 
 	return cmd.Run(virtOS, func() int {
 		w := virtOS.Stdout()
-		fmt.Fprintln(w, virtOS.SSHUser())
+		fmt.Fprintln(w, currentUser(virtOS))
 		return 0
 	})
 }
@@ -0,0 +1,171 @@
+// Package classify tags attacker commands with categories and MITRE ATT&CK
+// technique IDs so analysts don't have to recognize them by eye.
+package classify
+
+import (
+	_ "embed"
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"sigs.k8s.io/yaml"
+)
+
+//go:embed rules.yaml
+var builtinRulesData []byte
+
+// Rule tags commands that match it. A rule matches if the command name is in
+// Commands (or Commands is empty) and Pattern matches the command line (or
+// Pattern is empty).
+type Rule struct {
+	// Name of the rule, used for debugging.
+	Name string `json:"name" validate:"required"`
+	// Commands holds the base names of the programs the rule applies to e.g.
+	// "crontab".
+	Commands []string `json:"commands,omitempty" validate:"required_without=Pattern"`
+	// Pattern is a regular expression matched against the space separated
+	// command line.
+	Pattern string `json:"pattern,omitempty" validate:"required_without=Commands"`
+	// Categories to tag the command with e.g. "persistence".
+	Categories []string `json:"categories,omitempty"`
+	// Techniques holds MITRE ATT&CK technique IDs e.g. "T1053.003".
+	Techniques []string `json:"techniques,omitempty"`
+}
+
+// Classification holds the tags that apply to a command.
+type Classification struct {
+	// Categories are sorted unique categories.
+	Categories []string
+	// Techniques are sorted unique MITRE ATT&CK technique IDs.
+	Techniques []string
+}
+
+// IsEmpty returns true if no tags were applied.
+func (c *Classification) IsEmpty() bool {
+	return len(c.Categories) == 0 && len(c.Techniques) == 0
+}
+
+type compiledRule struct {
+	Rule
+	commands map[string]bool
+	pattern  *regexp.Regexp
+}
+
+func (r *compiledRule) matches(name, cmdline string) bool {
+	if len(r.commands) > 0 && !r.commands[name] {
+		return false
+	}
+	if r.pattern != nil && !r.pattern.MatchString(cmdline) {
+		return false
+	}
+	return true
+}
+
+// Classifier applies rules to commands.
+type Classifier struct {
+	rules []compiledRule
+}
+
+// ParseRules parses a YAML list of rules.
+func ParseRules(data []byte) ([]Rule, error) {
+	var out []Rule
+	if err := yaml.UnmarshalStrict(data, &out); err != nil {
+		return nil, err
+	}
+
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		return strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+	})
+	for i := range out {
+		if err := validate.Struct(&out[i]); err != nil {
+			return nil, fmt.Errorf("rule %d: %v", i, err)
+		}
+	}
+
+	return out, nil
+}
+
+// BuiltinRules returns the rules that ship with the honeypot.
+func BuiltinRules() []Rule {
+	rules, err := ParseRules(builtinRulesData)
+	if err != nil {
+		panic(err)
+	}
+	return rules
+}
+
+// New creates a classifier from the given rules.
+func New(rules []Rule) (*Classifier, error) {
+	out := &Classifier{}
+	for _, rule := range rules {
+		cr := compiledRule{Rule: rule}
+		if rule.Pattern != "" {
+			pattern, err := regexp.Compile(rule.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("rule %q: %v", rule.Name, err)
+			}
+			cr.pattern = pattern
+		}
+		if len(rule.Commands) > 0 {
+			cr.commands = make(map[string]bool)
+			for _, cmd := range rule.Commands {
+				cr.commands[cmd] = true
+			}
+		}
+		out.rules = append(out.rules, cr)
+	}
+	return out, nil
+}
+
+// Default returns a classifier with only the builtin rules.
+func Default() *Classifier {
+	classifier, err := New(BuiltinRules())
+	if err != nil {
+		panic(err)
+	}
+	return classifier
+}
+
+// Classify tags the command with the matching rules.
+func (c *Classifier) Classify(argv []string) Classification {
+	var out Classification
+	if c == nil || len(argv) == 0 {
+		return out
+	}
+
+	name := path.Base(argv[0])
+	cmdline := strings.Join(append([]string{name}, argv[1:]...), " ")
+
+	categories := make(map[string]bool)
+	techniques := make(map[string]bool)
+	for i := range c.rules {
+		rule := &c.rules[i]
+		if !rule.matches(name, cmdline) {
+			continue
+		}
+		for _, category := range rule.Categories {
+			categories[category] = true
+		}
+		for _, technique := range rule.Techniques {
+			techniques[technique] = true
+		}
+	}
+
+	out.Categories = sortedKeys(categories)
+	out.Techniques = sortedKeys(techniques)
+	return out
+}
+
+func sortedKeys(m map[string]bool) []string {
+	var out []string
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
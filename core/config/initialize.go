@@ -3,6 +3,7 @@ package config
 import (
 	"crypto/rsa"
 	"crypto/x509"
+	"encoding/hex"
 	"encoding/pem"
 	"errors"
 	"fmt"
@@ -13,6 +14,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/josephlewis42/honeyssh/core/integrity"
 	"github.com/spf13/afero"
 )
 
@@ -43,6 +45,12 @@ func Initialize(path string, logger *log.Logger) (*Configuration, error) {
 		return nil, err
 	}
 
+	logger.Println("Generating integrity key...")
+	integritySeed, err := integrity.GenerateSeed()
+	if err != nil {
+		return nil, err
+	}
+
 	logger.Println("Creating configuration files...")
 	exists := func(path string) bool {
 		// This is hacky, but good enough.
@@ -57,6 +65,7 @@ func Initialize(path string, logger *log.Logger) (*Configuration, error) {
 		{ConfigurationName, defaultConfigData},
 		{PrivateKeyName, privateKey},
 		{RootFSName, rootFsData},
+		{IntegrityKeyName, []byte(hex.EncodeToString(integritySeed) + "\n")},
 	}
 	for _, configFile := range configFiles {
 		if !exists(configFile.path) {
@@ -81,6 +90,10 @@ func Initialize(path string, logger *log.Logger) (*Configuration, error) {
 		}
 	}
 
+	if err := cfg.loadPersonas(); err != nil {
+		return nil, err
+	}
+
 	return cfg, nil
 }
 
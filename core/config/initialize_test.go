@@ -44,6 +44,12 @@ func TestInitialize(t *testing.T) {
 		fd.Close()
 	})
 
+	t.Run("IntegrityKey", func(t *testing.T) {
+		key, err := cfg.IntegrityKey()
+		assert.Nil(t, err)
+		assert.Equal(t, "ed25519", key.Method())
+	})
+
 	t.Run("PrivateKeyPem", func(t *testing.T) {
 		keyPem, err := cfg.PrivateKeyPem()
 		assert.Nil(t, err)
@@ -1,7 +1,6 @@
 package config
 
 import (
-	"io/ioutil"
 	"path/filepath"
 
 	"github.com/spf13/afero"
@@ -19,7 +18,17 @@ func Load(path string) (*Configuration, error) {
 		return nil, err
 	}
 
-	configContents, err := ioutil.ReadFile(filepath.Join(absPath, ConfigurationName))
+	return load(afero.NewBasePathFs(afero.NewOsFs(), absPath))
+}
+
+// Reload loads the configuration again from the same directory, e.g. after
+// config.yaml was edited. The receiver isn't changed.
+func (c *Configuration) Reload() (*Configuration, error) {
+	return load(c.configFs)
+}
+
+func load(configFs afero.Fs) (*Configuration, error) {
+	configContents, err := afero.ReadFile(configFs, ConfigurationName)
 	if err != nil {
 		return nil, err
 	}
@@ -27,10 +36,13 @@ func Load(path string) (*Configuration, error) {
 	if err := yaml.UnmarshalStrict(configContents, &out); err != nil {
 		return nil, err
 	}
-	out.configFs = afero.NewBasePathFs(afero.NewOsFs(), absPath)
+	out.configFs = configFs
 
 	if err := out.Validate(); err != nil {
 		return nil, err
 	}
+	if err := out.loadPersonas(); err != nil {
+		return nil, err
+	}
 	return &out, nil
 }
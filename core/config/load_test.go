@@ -0,0 +1,57 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfiguration_Reload(t *testing.T) {
+	cases := map[string]struct {
+		contents []byte
+		wantErr  bool
+	}{
+		"edited": {
+			contents: bytes.Replace(defaultConfigData, []byte("nodename: "), []byte("nodename: edited-"), 1),
+		},
+		"invalid yaml": {
+			contents: []byte("motd: [\n"),
+			wantErr:  true,
+		},
+		"unknown field": {
+			contents: append([]byte("not_a_field: true\n"), defaultConfigData...),
+			wantErr:  true,
+		},
+		"fails validation": {
+			contents: bytes.Replace(defaultConfigData, []byte(`default_shell: "/bin/sh"`), []byte(`default_shell: ""`), 1),
+			wantErr:  true,
+		},
+		"unknown persona": {
+			contents: bytes.Replace(defaultConfigData, []byte("persona: centos"), []byte("persona: vax"), 1),
+			wantErr:  true,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			configFs := afero.NewMemMapFs()
+			assert.NoError(t, afero.WriteFile(configFs, ConfigurationName, defaultConfigData, 0600))
+			current, err := load(configFs)
+			assert.NoError(t, err)
+
+			assert.NoError(t, afero.WriteFile(configFs, ConfigurationName, tc.contents, 0600))
+			reloaded, err := current.Reload()
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Contains(t, reloaded.Uname.Nodename, "edited-")
+			assert.NotContains(t, current.Uname.Nodename, "edited-")
+			// Files are still found in the same directory.
+			assert.Equal(t, current.fs(), reloaded.fs())
+		})
+	}
+}
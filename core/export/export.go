@@ -0,0 +1,279 @@
+// Package export converts honeypot events into threat intelligence formats
+// (STIX 2.1 and MISP) that can be shared with partners.
+package export
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/josephlewis42/honeyssh/core/logger"
+)
+
+// Kind is the type of an observable.
+type Kind string
+
+const (
+	// KindIP is the IP address of an attacker.
+	KindIP Kind = "ip"
+	// KindURL is a URL an attacker downloaded from.
+	KindURL Kind = "url"
+	// KindFile is a payload identified by its hashes.
+	KindFile Kind = "file"
+	// KindSSHClient is the version string of an attacker's SSH client.
+	KindSSHClient Kind = "ssh-client"
+	// KindSSHKey is the SHA-256 fingerprint of a public key an attacker
+	// offered.
+	KindSSHKey Kind = "ssh-key"
+	// KindCredential is a username and password pair an attacker tried.
+	KindCredential Kind = "credential"
+)
+
+// Observable is a de-duplicated indicator seen by the honeypot.
+type Observable struct {
+	Kind Kind
+	// Value is the canonical value, for files it's the SHA-256 hash and for
+	// credentials it's logger.CredentialKey.
+	Value string
+
+	// Username and Password are set for credentials.
+	Username string
+	Password string
+
+	// MD5 is set for files.
+	MD5 string
+
+	FirstSeen time.Time
+	LastSeen  time.Time
+	Count     int
+}
+
+// Download is a file captured by the honeypot.
+type Download struct {
+	// Name of the download in the download directory.
+	Name string
+	// Source the download came from e.g. a URL.
+	Source string
+	// Time the download happened.
+	Time time.Time
+	// SHA256 and MD5 are the hashes of the download, if empty they're
+	// computed with Collector.HashDownload.
+	SHA256 string
+	MD5    string
+}
+
+// Options holds information about the export.
+type Options struct {
+	// Name of the sensor or organization producing the export.
+	Name string
+	// Created is the time the export was generated.
+	Created time.Time
+}
+
+// Collector de-duplicates observables from events and downloads.
+type Collector struct {
+	// HashDownload returns the hex encoded SHA-256 and MD5 of a download by
+	// name, if nil or it returns an error the payload isn't exported.
+	HashDownload func(name string) (sha256Sum, md5Sum string, err error)
+
+	observables map[string]*Observable
+	downloads   map[string]bool
+	// loginIPs holds the addresses login attempts came from, they're only
+	// counted for addresses with no connection events e.g. in older logs.
+	loginIPs map[string]*Observable
+}
+
+func (c *Collector) init() {
+	if c.observables == nil {
+		c.observables = make(map[string]*Observable)
+		c.downloads = make(map[string]bool)
+		c.loginIPs = make(map[string]*Observable)
+	}
+}
+
+// Update adds observables from the event.
+func (c *Collector) Update(le *logger.LogEntry) {
+	timestamp := time.UnixMicro(le.GetTimestampMicros()).UTC()
+
+	switch event := le.GetLogType().(type) {
+	case *logger.LogEntry_ConnectionOpened:
+		c.addIP(event.ConnectionOpened.GetRemoteAddr(), timestamp)
+	case *logger.LogEntry_LoginAttempt:
+		la := event.LoginAttempt
+		c.addLoginIP(la.GetRemoteAddr(), timestamp)
+		if la.GetClientVersion() != "" {
+			c.add(&Observable{Kind: KindSSHClient, Value: la.GetClientVersion()}, timestamp)
+		}
+		if len(la.GetPublicKey()) > 0 {
+			c.add(&Observable{Kind: KindSSHKey, Value: fingerprintSHA256(la.GetPublicKey())}, timestamp)
+		}
+		if la.GetUsername() != "" || la.GetPassword() != "" {
+			c.add(&Observable{
+				Kind:     KindCredential,
+				Value:    logger.CredentialKey(la.GetUsername(), la.GetPassword()),
+				Username: la.GetUsername(),
+				Password: la.GetPassword(),
+			}, timestamp)
+		}
+	case *logger.LogEntry_Download:
+		c.AddDownload(Download{
+			Name:   event.Download.GetName(),
+			Source: event.Download.GetSource(),
+			Time:   timestamp,
+			SHA256: event.Download.GetSha256(),
+			MD5:    event.Download.GetMd5(),
+		})
+	}
+}
+
+// AddDownload adds observables for a captured file. Downloads are
+// de-duplicated by name so they can be added from both the event log and the
+// payload store.
+func (c *Collector) AddDownload(d Download) {
+	c.init()
+	if d.Name == "" || c.downloads[d.Name] {
+		return
+	}
+	c.downloads[d.Name] = true
+
+	if u, err := url.Parse(d.Source); err == nil {
+		switch u.Scheme {
+		case "http", "https", "ftp":
+			c.add(&Observable{Kind: KindURL, Value: d.Source}, d.Time)
+		}
+	}
+
+	sha256Sum, md5Sum := d.SHA256, d.MD5
+	if sha256Sum == "" && c.HashDownload != nil {
+		var err error
+		if sha256Sum, md5Sum, err = c.HashDownload(d.Name); err != nil {
+			return
+		}
+	}
+	if sha256Sum == "" {
+		return
+	}
+	c.add(&Observable{
+		Kind:  KindFile,
+		Value: sha256Sum,
+		MD5:   md5Sum,
+	}, d.Time)
+}
+
+// ipObservable returns the observable for the host of remoteAddr, it's nil
+// if remoteAddr isn't an IP address.
+func ipObservable(remoteAddr string) *Observable {
+	host := remoteAddr
+	if h, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		host = h
+	}
+	if net.ParseIP(host) == nil {
+		return nil
+	}
+	return &Observable{Kind: KindIP, Value: host}
+}
+
+// addIP counts a connection from remoteAddr.
+func (c *Collector) addIP(remoteAddr string, timestamp time.Time) {
+	if o := ipObservable(remoteAddr); o != nil {
+		c.add(o, timestamp)
+	}
+}
+
+// addLoginIP records a login attempt from remoteAddr. Connections log several
+// attempts so they're merged in to the connections from the address when the
+// observables are returned.
+func (c *Collector) addLoginIP(remoteAddr string, timestamp time.Time) {
+	o := ipObservable(remoteAddr)
+	if o == nil {
+		return
+	}
+	c.init()
+	existing, ok := c.loginIPs[o.Value]
+	if !ok {
+		o.FirstSeen = timestamp
+		o.LastSeen = timestamp
+		c.loginIPs[o.Value] = o
+		existing = o
+	}
+	existing.Count++
+	existing.see(timestamp)
+}
+
+func (c *Collector) add(o *Observable, timestamp time.Time) {
+	c.init()
+	key := observableKey(o.Kind, o.Value)
+	existing, ok := c.observables[key]
+	if !ok {
+		o.FirstSeen = timestamp
+		o.LastSeen = timestamp
+		c.observables[key] = o
+		existing = o
+	}
+
+	existing.Count++
+	existing.see(timestamp)
+}
+
+func observableKey(kind Kind, value string) string {
+	return string(kind) + "\x00" + value
+}
+
+// see widens the time the observable was seen to include timestamp.
+func (o *Observable) see(timestamp time.Time) {
+	if timestamp.Before(o.FirstSeen) {
+		o.FirstSeen = timestamp
+	}
+	if timestamp.After(o.LastSeen) {
+		o.LastSeen = timestamp
+	}
+}
+
+// Observables returns the collected observables sorted by kind and value.
+func (c *Collector) Observables() []*Observable {
+	var out []*Observable
+	for _, o := range c.observables {
+		out = append(out, o)
+	}
+	for host, login := range c.loginIPs {
+		// Addresses are counted by connection, login attempts are only
+		// counted if there were no connection events.
+		if o, ok := c.observables[observableKey(KindIP, host)]; ok {
+			o.see(login.FirstSeen)
+			o.see(login.LastSeen)
+			continue
+		}
+		out = append(out, login)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Kind != out[j].Kind {
+			return out[i].Kind < out[j].Kind
+		}
+		return out[i].Value < out[j].Value
+	})
+	return out
+}
+
+// fingerprintSHA256 returns the OpenSSH style SHA-256 fingerprint of a
+// public key in wire format.
+func fingerprintSHA256(publicKey []byte) string {
+	sum := sha256.Sum256(publicKey)
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:])
+}
+
+// uuidV5 creates a name based UUID so exports are stable between runs.
+func uuidV5(namespace [16]byte, name string) string {
+	h := sha1.New()
+	h.Write(namespace[:])
+	h.Write([]byte(name))
+	sum := h.Sum(nil)
+
+	sum[6] = (sum[6] & 0x0f) | 0x50
+	sum[8] = (sum[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
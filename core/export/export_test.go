@@ -0,0 +1,171 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/josephlewis42/honeyssh/core/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+var testTime = time.Date(2006, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func testCollector() *Collector {
+	collector := &Collector{
+		HashDownload: func(name string) (string, string, error) {
+			return "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", "d41d8cd98f00b204e9800998ecf8427e", nil
+		},
+	}
+
+	for i := 0; i < 3; i++ {
+		collector.Update(&logger.LogEntry{
+			TimestampMicros: testTime.Add(time.Duration(i) * time.Hour).UnixMicro(),
+			LogType: &logger.LogEntry_LoginAttempt{LoginAttempt: &logger.LoginAttempt{
+				Username:      "root",
+				Password:      "hunter2",
+				RemoteAddr:    "192.0.2.1:1234",
+				ClientVersion: "SSH-2.0-libssh_0.9.6",
+			}},
+		})
+	}
+	collector.Update(&logger.LogEntry{
+		TimestampMicros: testTime.UnixMicro(),
+		LogType: &logger.LogEntry_Download{Download: &logger.Download{
+			Name:   "payload",
+			Source: "http://example.com/x.sh",
+		}},
+	})
+	// Duplicate downloads from metadata files are ignored.
+	collector.AddDownload(Download{Name: "payload", Source: "http://example.com/x.sh", Time: testTime})
+	collector.AddDownload(Download{Name: "upload", Source: "scp_upload://tmp/x", Time: testTime})
+
+	return collector
+}
+
+func TestCollector(t *testing.T) {
+	observables := testCollector().Observables()
+
+	var kinds []Kind
+	for _, o := range observables {
+		kinds = append(kinds, o.Kind)
+	}
+	assert.Equal(t, []Kind{KindCredential, KindFile, KindIP, KindSSHClient, KindURL}, kinds)
+
+	credential := observables[0]
+	assert.Equal(t, 3, credential.Count)
+	assert.Equal(t, testTime, credential.FirstSeen)
+	assert.Equal(t, testTime.Add(2*time.Hour), credential.LastSeen)
+
+	// Both downloads have the same content.
+	assert.Equal(t, 2, observables[1].Count)
+	assert.Equal(t, "192.0.2.1", observables[2].Value)
+}
+
+func TestCollector_ipCount(t *testing.T) {
+	collector := &Collector{}
+	collector.Update(&logger.LogEntry{
+		TimestampMicros: testTime.UnixMicro(),
+		LogType: &logger.LogEntry_ConnectionOpened{ConnectionOpened: &logger.ConnectionOpened{
+			RemoteAddr: "192.0.2.1:1234",
+		}},
+	})
+	// Three failed passwords and a success on the same connection.
+	for i := 1; i <= 4; i++ {
+		collector.Update(&logger.LogEntry{
+			TimestampMicros: testTime.Add(time.Duration(i) * time.Minute).UnixMicro(),
+			LogType: &logger.LogEntry_LoginAttempt{LoginAttempt: &logger.LoginAttempt{
+				RemoteAddr: "192.0.2.1:1234",
+			}},
+		})
+	}
+	// Older logs only have login attempts.
+	collector.Update(&logger.LogEntry{
+		TimestampMicros: testTime.UnixMicro(),
+		LogType: &logger.LogEntry_LoginAttempt{LoginAttempt: &logger.LoginAttempt{
+			RemoteAddr: "198.51.100.1:22",
+		}},
+	})
+
+	observables := collector.Observables()
+	if !assert.Len(t, observables, 2) {
+		return
+	}
+	assert.Equal(t, "192.0.2.1", observables[0].Value)
+	assert.Equal(t, 1, observables[0].Count)
+	assert.Equal(t, testTime, observables[0].FirstSeen)
+	assert.Equal(t, testTime.Add(4*time.Minute), observables[0].LastSeen)
+	assert.Equal(t, "198.51.100.1", observables[1].Value)
+	assert.Equal(t, 1, observables[1].Count)
+}
+
+func TestUUIDV5(t *testing.T) {
+	dnsNamespace := [16]byte{
+		0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x11, 0xd1,
+		0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8,
+	}
+
+	assert.Equal(t, "2ed6657d-e927-568b-95e1-2665a8aea6a2", uuidV5(dnsNamespace, "www.example.com"))
+}
+
+func TestWriteSTIX(t *testing.T) {
+	out := &bytes.Buffer{}
+	assert.NoError(t, WriteSTIX(out, testCollector().Observables(), Options{Name: "sensor", Created: testTime}))
+
+	var bundle struct {
+		Type    string           `json:"type"`
+		Objects []map[string]any `json:"objects"`
+	}
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &bundle))
+	assert.Equal(t, "bundle", bundle.Type)
+
+	types := make(map[string]int)
+	patterns := make(map[string]bool)
+	for _, obj := range bundle.Objects {
+		types[obj["type"].(string)]++
+		if pattern, ok := obj["pattern"]; ok {
+			patterns[pattern.(string)] = true
+		}
+	}
+	assert.Equal(t, map[string]int{
+		"identity":      1,
+		"indicator":     5,
+		"observed-data": 5,
+		"sighting":      5,
+		"ipv4-addr":     1,
+		"url":           1,
+		"file":          1,
+		"software":      1,
+		"user-account":  1,
+	}, types)
+	assert.True(t, patterns["[ipv4-addr:value = '192.0.2.1']"])
+	assert.True(t, patterns["[user-account:account_login = 'root' AND user-account:credential = 'hunter2']"])
+
+	// Exports are stable.
+	again := &bytes.Buffer{}
+	assert.NoError(t, WriteSTIX(again, testCollector().Observables(), Options{Name: "sensor", Created: testTime}))
+	assert.Equal(t, out.String(), again.String())
+}
+
+func TestWriteMISP(t *testing.T) {
+	out := &bytes.Buffer{}
+	assert.NoError(t, WriteMISP(out, testCollector().Observables(), Options{Name: "sensor", Created: testTime}))
+
+	var event mispEvent
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &event))
+	assert.Equal(t, "2006-01-02", event.Event.Date)
+
+	attributeTypes := make(map[string]string)
+	for _, attr := range event.Event.Attribute {
+		attributeTypes[attr.Type] = attr.Value
+	}
+	assert.Equal(t, "192.0.2.1", attributeTypes["ip-src"])
+	assert.Equal(t, "http://example.com/x.sh", attributeTypes["url"])
+
+	var objectNames []string
+	for _, obj := range event.Event.Object {
+		objectNames = append(objectNames, obj.Name)
+	}
+	assert.Equal(t, []string{"credential", "file"}, objectNames)
+}
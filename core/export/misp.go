@@ -0,0 +1,159 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// mispNamespace is used to derive MISP UUIDs so repeated exports don't
+// create duplicates.
+var mispNamespace = [16]byte{
+	0x2d, 0x7e, 0x90, 0x13, 0x5b, 0x4c, 0x4f, 0x08,
+	0x8e, 0x61, 0x0a, 0x37, 0xd2, 0x95, 0x1f, 0x64,
+}
+
+type mispEvent struct {
+	Event mispEventBody `json:"Event"`
+}
+
+type mispEventBody struct {
+	UUID          string          `json:"uuid"`
+	Info          string          `json:"info"`
+	Date          string          `json:"date"`
+	Timestamp     string          `json:"timestamp"`
+	ThreatLevelID string          `json:"threat_level_id"`
+	Analysis      string          `json:"analysis"`
+	Distribution  string          `json:"distribution"`
+	Published     bool            `json:"published"`
+	Attribute     []mispAttribute `json:"Attribute"`
+	Object        []mispObject    `json:"Object"`
+}
+
+type mispAttribute struct {
+	UUID         string         `json:"uuid"`
+	Type         string         `json:"type"`
+	Category     string         `json:"category"`
+	Value        string         `json:"value"`
+	ToIDS        bool           `json:"to_ids"`
+	Comment      string         `json:"comment,omitempty"`
+	ObjectRel    string         `json:"object_relation,omitempty"`
+	FirstSeen    string         `json:"first_seen,omitempty"`
+	LastSeen     string         `json:"last_seen,omitempty"`
+	Distribution string         `json:"distribution"`
+	Sighting     []mispSighting `json:"Sighting,omitempty"`
+}
+
+type mispSighting struct {
+	Type      string `json:"type"`
+	Source    string `json:"source"`
+	Timestamp string `json:"date_sighting"`
+}
+
+type mispObject struct {
+	UUID         string          `json:"uuid"`
+	Name         string          `json:"name"`
+	MetaCategory string          `json:"meta-category"`
+	Distribution string          `json:"distribution"`
+	FirstSeen    string          `json:"first_seen,omitempty"`
+	LastSeen     string          `json:"last_seen,omitempty"`
+	Comment      string          `json:"comment,omitempty"`
+	Attribute    []mispAttribute `json:"Attribute"`
+}
+
+// WriteMISP writes the observables as a MISP event in JSON format. Simple
+// observables become attributes while files and credentials become objects.
+// Each observable carries a sighting from the honeypot at the time it was
+// last seen.
+func WriteMISP(w io.Writer, observables []*Observable, opts Options) error {
+	name := opts.Name
+	if name == "" {
+		name = "honeyssh"
+	}
+
+	event := mispEventBody{
+		UUID:          uuidV5(mispNamespace, "event:"+name+":"+stixTime(opts.Created)),
+		Info:          fmt.Sprintf("SSH honeypot activity from %s", name),
+		Date:          opts.Created.UTC().Format("2006-01-02"),
+		Timestamp:     strconv.FormatInt(opts.Created.Unix(), 10),
+		ThreatLevelID: "3", // Low
+		Analysis:      "2", // Completed
+		Distribution:  "0", // Your organization only
+		Attribute:     []mispAttribute{},
+		Object:        []mispObject{},
+	}
+
+	for _, o := range observables {
+		key := string(o.Kind) + ":" + o.Value
+		attr := func(relation, attrType, category, value string, toIDS bool) mispAttribute {
+			return mispAttribute{
+				UUID:         uuidV5(mispNamespace, "attribute:"+key+":"+relation),
+				Type:         attrType,
+				Category:     category,
+				Value:        value,
+				ToIDS:        toIDS,
+				Comment:      fmt.Sprintf("Seen %d time(s) by %s", o.Count, name),
+				ObjectRel:    relation,
+				FirstSeen:    stixTime(o.FirstSeen),
+				LastSeen:     stixTime(o.LastSeen),
+				Distribution: "5", // Inherit from the event
+				Sighting: []mispSighting{{
+					Type:      "0",
+					Source:    name,
+					Timestamp: strconv.FormatInt(o.LastSeen.Unix(), 10),
+				}},
+			}
+		}
+
+		switch o.Kind {
+		case KindIP:
+			event.Attribute = append(event.Attribute, attr("", "ip-src", "Network activity", o.Value, true))
+		case KindURL:
+			event.Attribute = append(event.Attribute, attr("", "url", "Payload delivery", o.Value, true))
+		case KindSSHClient:
+			a := attr("", "text", "Network activity", o.Value, false)
+			a.Comment = "SSH client version. " + a.Comment
+			event.Attribute = append(event.Attribute, a)
+		case KindSSHKey:
+			a := attr("", "text", "Network activity", o.Value, false)
+			a.Comment = "SSH public key fingerprint. " + a.Comment
+			event.Attribute = append(event.Attribute, a)
+		case KindFile:
+			attrs := []mispAttribute{attr("sha256", "sha256", "Payload delivery", o.Value, true)}
+			if o.MD5 != "" {
+				attrs = append(attrs, attr("md5", "md5", "Payload delivery", o.MD5, true))
+			}
+			event.Object = append(event.Object, mispObject{
+				UUID:         uuidV5(mispNamespace, "object:"+key),
+				Name:         "file",
+				MetaCategory: "file",
+				Distribution: "5",
+				FirstSeen:    stixTime(o.FirstSeen),
+				LastSeen:     stixTime(o.LastSeen),
+				Attribute:    attrs,
+			})
+		case KindCredential:
+			event.Object = append(event.Object, mispObject{
+				UUID:         uuidV5(mispNamespace, "object:"+key),
+				Name:         "credential",
+				MetaCategory: "misc",
+				Distribution: "5",
+				FirstSeen:    stixTime(o.FirstSeen),
+				LastSeen:     stixTime(o.LastSeen),
+				Comment:      "Credential pair tried against the honeypot.",
+				Attribute: []mispAttribute{
+					attr("username", "text", "Other", o.Username, false),
+					attr("password", "text", "Other", o.Password, false),
+				},
+			})
+		default:
+			return fmt.Errorf("unknown observable kind %q", o.Kind)
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(mispEvent{Event: event})
+}
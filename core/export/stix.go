@@ -0,0 +1,215 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// stixSCONamespace is the namespace STIX 2.1 uses for deterministic cyber
+// observable IDs.
+var stixSCONamespace = [16]byte{
+	0x00, 0xab, 0xed, 0xb4, 0xaa, 0x42, 0x46, 0x6c,
+	0x9c, 0x01, 0xfe, 0xd2, 0x33, 0x15, 0xa9, 0xb7,
+}
+
+// stixNamespace is used to derive IDs for domain objects generated by the
+// honeypot so repeated exports don't create duplicates.
+var stixNamespace = [16]byte{
+	0x6b, 0x1c, 0x3e, 0x52, 0x0d, 0x8f, 0x4a, 0x1e,
+	0x9a, 0x57, 0x2f, 0x44, 0x81, 0x6e, 0xc3, 0x0b,
+}
+
+const stixTimeFormat = "2006-01-02T15:04:05.000Z"
+
+type stixObject map[string]any
+
+// WriteSTIX writes the observables as a STIX 2.1 bundle. Each observable
+// becomes a cyber observable, observed-data, an indicator and a sighting by
+// the honeypot's identity.
+func WriteSTIX(w io.Writer, observables []*Observable, opts Options) error {
+	created := stixTime(opts.Created)
+	name := opts.Name
+	if name == "" {
+		name = "honeyssh"
+	}
+
+	identityID := "identity--" + uuidV5(stixNamespace, "identity:"+name)
+	objects := []stixObject{{
+		"type":           "identity",
+		"spec_version":   "2.1",
+		"id":             identityID,
+		"created":        created,
+		"modified":       created,
+		"name":           name,
+		"identity_class": "system",
+	}}
+
+	for _, o := range observables {
+		sco, pattern, err := stixObservable(o)
+		if err != nil {
+			return err
+		}
+
+		key := string(o.Kind) + ":" + o.Value
+		indicatorID := "indicator--" + uuidV5(stixNamespace, "indicator:"+key)
+		observedDataID := "observed-data--" + uuidV5(stixNamespace, "observed-data:"+name+":"+key)
+
+		objects = append(objects,
+			sco,
+			stixObject{
+				"type":            "observed-data",
+				"spec_version":    "2.1",
+				"id":              observedDataID,
+				"created":         created,
+				"modified":        created,
+				"created_by_ref":  identityID,
+				"first_observed":  stixTime(o.FirstSeen),
+				"last_observed":   stixTime(o.LastSeen),
+				"number_observed": o.Count,
+				"object_refs":     []string{sco["id"].(string)},
+			},
+			stixObject{
+				"type":            "indicator",
+				"spec_version":    "2.1",
+				"id":              indicatorID,
+				"created":         created,
+				"modified":        created,
+				"created_by_ref":  identityID,
+				"name":            fmt.Sprintf("Honeypot %s: %s", o.Kind, o.Value),
+				"indicator_types": []string{"malicious-activity"},
+				"pattern":         pattern,
+				"pattern_type":    "stix",
+				"valid_from":      stixTime(o.FirstSeen),
+			},
+			stixObject{
+				"type":               "sighting",
+				"spec_version":       "2.1",
+				"id":                 "sighting--" + uuidV5(stixNamespace, "sighting:"+name+":"+key),
+				"created":            created,
+				"modified":           created,
+				"created_by_ref":     identityID,
+				"first_seen":         stixTime(o.FirstSeen),
+				"last_seen":          stixTime(o.LastSeen),
+				"count":              o.Count,
+				"sighting_of_ref":    indicatorID,
+				"observed_data_refs": []string{observedDataID},
+				"where_sighted_refs": []string{identityID},
+			},
+		)
+	}
+
+	bundle := stixObject{
+		"type":    "bundle",
+		"id":      "bundle--" + uuidV5(stixNamespace, "bundle:"+name+":"+created),
+		"objects": objects,
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(bundle)
+}
+
+// stixObservable returns the cyber observable object and indicator pattern
+// for an observable.
+func stixObservable(o *Observable) (stixObject, string, error) {
+	var sco stixObject
+	var pattern string
+
+	switch o.Kind {
+	case KindIP:
+		scoType := "ipv4-addr"
+		if ip := net.ParseIP(o.Value); ip != nil && ip.To4() == nil {
+			scoType = "ipv6-addr"
+		}
+		sco = stixObject{"type": scoType, "value": o.Value}
+		pattern = fmt.Sprintf("[%s:value = %s]", scoType, stixQuote(o.Value))
+	case KindURL:
+		sco = stixObject{"type": "url", "value": o.Value}
+		pattern = fmt.Sprintf("[url:value = %s]", stixQuote(o.Value))
+	case KindFile:
+		hashes := map[string]string{"SHA-256": o.Value}
+		if o.MD5 != "" {
+			hashes["MD5"] = o.MD5
+		}
+		sco = stixObject{"type": "file", "hashes": hashes}
+		pattern = fmt.Sprintf("[file:hashes.'SHA-256' = %s]", stixQuote(o.Value))
+	case KindSSHClient:
+		sco = stixObject{"type": "software", "name": o.Value}
+		pattern = fmt.Sprintf("[software:name = %s]", stixQuote(o.Value))
+	case KindSSHKey:
+		sco = stixObject{"type": "x-ssh-key", "fingerprint": o.Value}
+		pattern = fmt.Sprintf("[x-ssh-key:fingerprint = %s]", stixQuote(o.Value))
+	case KindCredential:
+		sco = stixObject{"type": "user-account", "account_login": o.Username, "credential": o.Password}
+		pattern = fmt.Sprintf("[user-account:account_login = %s AND user-account:credential = %s]",
+			stixQuote(o.Username), stixQuote(o.Password))
+	default:
+		return nil, "", fmt.Errorf("unknown observable kind %q", o.Kind)
+	}
+
+	id, err := stixSCOID(sco)
+	if err != nil {
+		return nil, "", err
+	}
+	sco["id"] = id
+	sco["spec_version"] = "2.1"
+	return sco, pattern, nil
+}
+
+// stixSCOID computes the deterministic ID of a cyber observable from its ID
+// contributing properties.
+func stixSCOID(sco stixObject) (string, error) {
+	scoType := sco["type"].(string)
+
+	var contributing map[string]any
+	switch scoType {
+	case "file":
+		// Only a single hash contributes, MD5 is preferred.
+		hashes := sco["hashes"].(map[string]string)
+		hash := map[string]string{}
+		if md5Sum, ok := hashes["MD5"]; ok {
+			hash["MD5"] = md5Sum
+		} else {
+			hash["SHA-256"] = hashes["SHA-256"]
+		}
+		contributing = map[string]any{"hashes": hash}
+	case "user-account":
+		// The credential is included so each username/password pair gets its
+		// own object rather than colliding on the login.
+		contributing = map[string]any{"account_login": sco["account_login"], "credential": sco["credential"]}
+	default:
+		contributing = map[string]any{}
+		for k, v := range sco {
+			if k != "type" {
+				contributing[k] = v
+			}
+		}
+	}
+
+	// Go sorts map keys so this is the canonical form for these objects.
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(contributing); err != nil {
+		return "", err
+	}
+
+	return scoType + "--" + uuidV5(stixSCONamespace, strings.TrimSuffix(buf.String(), "\n")), nil
+}
+
+// stixQuote quotes a string for use in a STIX pattern.
+func stixQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `'`, `\'`)
+	return "'" + s + "'"
+}
+
+func stixTime(t time.Time) string {
+	return t.UTC().Format(stixTimeFormat)
+}
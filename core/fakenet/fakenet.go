@@ -0,0 +1,226 @@
+// Package fakenet answers HTTP requests without contacting the network.
+package fakenet
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math/rand"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/josephlewis42/honeyssh/core/config"
+	"github.com/josephlewis42/honeyssh/core/payload"
+	"github.com/spf13/afero"
+)
+
+// defaultBinarySize is the size of generic binary responses.
+const defaultBinarySize = 48 * 1024
+
+const defaultHTML = `<!DOCTYPE html>
+<html>
+<head><title>Welcome to nginx!</title></head>
+<body>
+<h1>Welcome to nginx!</h1>
+<p>If you see this page, the nginx web server is successfully installed and
+working. Further configuration is required.</p>
+</body>
+</html>
+`
+
+const defaultScript = "#!/bin/sh\nexit 0\n"
+
+// Sinkhole is an http.RoundTripper that serves fake responses.
+type Sinkhole struct {
+	responses []config.FakeResponse
+	canned    afero.Fs
+}
+
+var _ http.RoundTripper = (*Sinkhole)(nil)
+
+// NewSinkhole creates a sinkhole that serves the first matching response,
+// then files from canned named <host>/<path>, then a generic response. canned
+// may be nil.
+func NewSinkhole(responses []config.FakeResponse, canned afero.Fs) *Sinkhole {
+	return &Sinkhole{responses: responses, canned: canned}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (s *Sinkhole) RoundTrip(req *http.Request) (*http.Response, error) {
+	host, urlPath := req.URL.Hostname(), req.URL.Path
+	if urlPath == "" {
+		urlPath = "/"
+	}
+
+	for _, response := range s.responses {
+		if !matches(response.Host, host) || !matches(response.Path, urlPath) {
+			continue
+		}
+
+		body := []byte(response.Body)
+		if response.File != "" {
+			var err error
+			if body, err = s.readCanned(response.File); err != nil {
+				return nil, err
+			}
+		}
+		status := response.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		return newResponse(req, status, response.ContentType, body), nil
+	}
+
+	switch body, err := s.readCanned(path.Join(host, urlPath)); {
+	case err == nil:
+		return newResponse(req, http.StatusOK, "", body), nil
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, err
+	}
+
+	contentType, body := genericBody(req.URL.String(), urlPath)
+	return newResponse(req, http.StatusOK, contentType, body), nil
+}
+
+func (s *Sinkhole) readCanned(name string) ([]byte, error) {
+	if s.canned == nil {
+		return nil, fs.ErrNotExist
+	}
+	info, err := s.canned.Stat(name)
+	switch {
+	case err != nil:
+		return nil, err
+	case info.IsDir():
+		return nil, fs.ErrNotExist
+	}
+	return afero.ReadFile(s.canned, name)
+}
+
+// matches returns true if the glob is empty or matches the value.
+func matches(glob, value string) bool {
+	if glob == "" {
+		return true
+	}
+	ok, _ := path.Match(glob, value)
+	return ok
+}
+
+// genericBody returns a plausible body for a URL based on its extension.
+func genericBody(rawURL, urlPath string) (contentType string, body []byte) {
+	switch ext := path.Ext(urlPath); {
+	case strings.HasSuffix(urlPath, "/"), ext == ".html", ext == ".htm", ext == ".php":
+		return "text/html", []byte(defaultHTML)
+	case ext == ".sh":
+		return "text/x-sh", []byte(defaultScript)
+	case ext == ".txt":
+		return "text/plain", []byte{}
+	}
+
+	// Binaries are random but stable so repeated fetches look the same.
+	sum := sha256.Sum256([]byte(rawURL))
+	seed := int64(0)
+	for _, b := range sum[:8] {
+		seed = seed<<8 | int64(b)
+	}
+	body = make([]byte, defaultBinarySize)
+	rand.New(rand.NewSource(seed)).Read(body)
+	return "application/octet-stream", body
+}
+
+// newResponse creates a response the way a real server would send it.
+func newResponse(req *http.Request, status int, contentType string, body []byte) *http.Response {
+	if contentType == "" {
+		contentType = http.DetectContentType(body)
+	}
+
+	header := http.Header{}
+	header.Set("Server", "nginx")
+	header.Set("Content-Type", contentType)
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+
+	if req.Method == http.MethodHead {
+		body = nil
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// Replay is an http.RoundTripper that serves payloads previously captured
+// from the requested URL.
+type Replay struct {
+	store    *payload.Store
+	fallback http.RoundTripper
+}
+
+var _ http.RoundTripper = (*Replay)(nil)
+
+// NewReplay creates a replay that serves payloads from the store and uses
+// fallback for URLs that were never captured.
+func NewReplay(store *payload.Store, fallback http.RoundTripper) *Replay {
+	return &Replay{store: store, fallback: fallback}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Replay) RoundTrip(req *http.Request) (*http.Response, error) {
+	sha256Sum, err := r.lookup(req.URL.String())
+	switch {
+	case errors.Is(err, payload.ErrNotFound):
+		return r.fallback.RoundTrip(req)
+	case err != nil:
+		return nil, err
+	}
+
+	fd, err := r.store.Open(sha256Sum)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+	body, err := io.ReadAll(fd)
+	if err != nil {
+		return nil, err
+	}
+
+	return newResponse(req, http.StatusOK, "", body), nil
+}
+
+// lookup finds the payload most recently captured from the URL.
+func (r *Replay) lookup(rawURL string) (string, error) {
+	entries, err := r.store.List()
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		found    string
+		lastSeen int64
+	)
+	for _, entry := range entries {
+		for _, sighting := range entry.Sightings {
+			if sighting.Source != rawURL {
+				continue
+			}
+			if seen := sighting.Time.UnixNano(); found == "" || seen > lastSeen {
+				found, lastSeen = entry.SHA256, seen
+			}
+		}
+	}
+	if found == "" {
+		return "", fmt.Errorf("%s: %w", rawURL, payload.ErrNotFound)
+	}
+	return found, nil
+}
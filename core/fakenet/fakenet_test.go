@@ -0,0 +1,125 @@
+package fakenet
+
+import (
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/josephlewis42/honeyssh/core/config"
+	"github.com/josephlewis42/honeyssh/core/payload"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func fetch(t *testing.T, transport http.RoundTripper, method, rawURL string) (*http.Response, string) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, rawURL, nil)
+	assert.NoError(t, err)
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	return resp, string(body)
+}
+
+func TestSinkhole(t *testing.T) {
+	canned := afero.NewMemMapFs()
+	assert.NoError(t, afero.WriteFile(canned, "example.com/bins/mips", []byte("canned mips"), 0644))
+	assert.NoError(t, afero.WriteFile(canned, "miner.bin", []byte("canned miner"), 0644))
+
+	sinkhole := NewSinkhole([]config.FakeResponse{
+		{Host: "*.example.com", Path: "/bins/*", Body: "fake bin", ContentType: "application/x-executable"},
+		{Path: "/missing", Status: http.StatusNotFound, Body: "not found"},
+		{Host: "pool.example.org", File: "miner.bin"},
+	}, canned)
+
+	cases := map[string]struct {
+		url             string
+		wantStatus      int
+		wantContentType string
+		wantBody        string
+	}{
+		"response": {
+			url:             "http://cdn.example.com/bins/arm7",
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/x-executable",
+			wantBody:        "fake bin",
+		},
+		"status": {
+			url:             "http://192.0.2.1/missing",
+			wantStatus:      http.StatusNotFound,
+			wantContentType: "text/plain; charset=utf-8",
+			wantBody:        "not found",
+		},
+		"response file": {
+			url:             "http://pool.example.org/xmrig",
+			wantStatus:      http.StatusOK,
+			wantContentType: "text/plain; charset=utf-8",
+			wantBody:        "canned miner",
+		},
+		"canned": {
+			url:             "http://example.com/bins/mips",
+			wantStatus:      http.StatusOK,
+			wantContentType: "text/plain; charset=utf-8",
+			wantBody:        "canned mips",
+		},
+		"script": {
+			url:             "http://example.com/install.sh",
+			wantStatus:      http.StatusOK,
+			wantContentType: "text/x-sh",
+			wantBody:        defaultScript,
+		},
+		"index": {
+			url:             "http://example.com",
+			wantStatus:      http.StatusOK,
+			wantContentType: "text/html",
+			wantBody:        defaultHTML,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			resp, body := fetch(t, sinkhole, http.MethodGet, tc.url)
+			assert.Equal(t, tc.wantStatus, resp.StatusCode)
+			assert.Equal(t, tc.wantContentType, resp.Header.Get("Content-Type"))
+			assert.Equal(t, tc.wantBody, body)
+		})
+	}
+
+	// Generic binaries are stable.
+	_, first := fetch(t, sinkhole, http.MethodGet, "http://example.com/x86")
+	_, second := fetch(t, sinkhole, http.MethodGet, "http://example.com/x86")
+	assert.Len(t, first, defaultBinarySize)
+	assert.Equal(t, first, second)
+
+	resp, body := fetch(t, sinkhole, http.MethodHead, "http://example.com/x86")
+	assert.Empty(t, body)
+	assert.Equal(t, "49152", resp.Header.Get("Content-Length"))
+}
+
+func TestReplay(t *testing.T) {
+	store := payload.NewStore(afero.NewMemMapFs())
+	start := time.Date(2006, 1, 2, 3, 4, 5, 0, time.UTC)
+	for i, body := range []string{"old", "new"} {
+		fd, err := store.Create(payload.Sighting{
+			ID:     body,
+			Time:   start.Add(time.Duration(i) * time.Hour),
+			Source: "http://example.com/bins/mips",
+		})
+		assert.NoError(t, err)
+		_, err = fd.Write([]byte(body))
+		assert.NoError(t, err)
+		assert.NoError(t, fd.Close())
+	}
+
+	replay := NewReplay(store, NewSinkhole(nil, nil))
+
+	_, body := fetch(t, replay, http.MethodGet, "http://example.com/bins/mips")
+	assert.Equal(t, "new", body)
+
+	_, body = fetch(t, replay, http.MethodGet, "http://example.com/install.sh")
+	assert.Equal(t, defaultScript, body)
+}
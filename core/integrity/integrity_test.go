@@ -0,0 +1,217 @@
+package integrity
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func testKey(t *testing.T, method string) *Key {
+	t.Helper()
+	key, err := NewKey(method, bytes.Repeat([]byte{1}, SeedSize))
+	assert.NoError(t, err)
+	return key
+}
+
+func TestKey(t *testing.T) {
+	for _, method := range []string{MethodHMAC, MethodEd25519} {
+		t.Run(method, func(t *testing.T) {
+			key := testKey(t, method)
+			signature, err := key.Sign([]byte("message"))
+			assert.NoError(t, err)
+			assert.True(t, key.Verify([]byte("message"), signature))
+			assert.False(t, key.Verify([]byte("massage"), signature))
+
+			other, err := NewKey(method, bytes.Repeat([]byte{2}, SeedSize))
+			assert.NoError(t, err)
+			assert.False(t, other.Verify([]byte("message"), signature))
+		})
+	}
+
+	_, err := NewKey("rot13", make([]byte, SeedSize))
+	assert.Error(t, err)
+	_, err = NewKey(MethodHMAC, []byte("short"))
+	assert.Error(t, err)
+}
+
+func TestNewPublicKey(t *testing.T) {
+	key := testKey(t, MethodEd25519)
+	signature, err := key.Sign([]byte("message"))
+	assert.NoError(t, err)
+
+	public, err := NewPublicKey(key.PublicKey())
+	assert.NoError(t, err)
+	assert.True(t, public.Verify([]byte("message"), signature))
+	_, err = public.Sign([]byte("message"))
+	assert.Error(t, err)
+}
+
+func TestVerifyFile(t *testing.T) {
+	cases := map[string]struct {
+		tamper  func(fs afero.Fs)
+		wantErr error
+	}{
+		"untouched": {
+			tamper: func(afero.Fs) {},
+		},
+		"contents changed": {
+			tamper: func(fs afero.Fs) {
+				afero.WriteFile(fs, "logs/a.cast", []byte("edited"), 0600)
+			},
+			wantErr: ErrTampered,
+		},
+		"signature swapped": {
+			tamper: func(fs afero.Fs) {
+				sig, _ := afero.ReadFile(fs, "logs/b.cast.sig")
+				afero.WriteFile(fs, "logs/a.cast.sig", sig, 0600)
+			},
+			wantErr: ErrTampered,
+		},
+		"signature removed": {
+			tamper: func(fs afero.Fs) {
+				fs.Remove("logs/a.cast.sig")
+			},
+			wantErr: ErrUnsigned,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			key := testKey(t, MethodEd25519)
+			fs := afero.NewMemMapFs()
+			afero.WriteFile(fs, "logs/a.cast", []byte("original"), 0600)
+			afero.WriteFile(fs, "logs/b.cast", []byte("original"), 0600)
+			assert.NoError(t, SignFile(fs, "logs/a.cast", key))
+			assert.NoError(t, SignFile(fs, "logs/b.cast", key))
+
+			tc.tamper(fs)
+
+			err := VerifyFile(fs, "logs/a.cast", key)
+			if tc.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errors.Is(err, tc.wantErr), "got error %v", err)
+			}
+		})
+	}
+}
+
+// writeChain writes each line through a ChainWriter, continuing the chain
+// held in chain.
+func writeChain(t *testing.T, log, chain *bytes.Buffer, key *Key, lines ...string) {
+	t.Helper()
+	last, err := LastLink(bytes.NewReader(chain.Bytes()))
+	assert.NoError(t, err)
+	writer := NewChainWriter(log, int64(log.Len()), chain, last, key)
+	for _, line := range lines {
+		_, err := writer.Write([]byte(line))
+		assert.NoError(t, err)
+	}
+}
+
+func TestVerifyChain(t *testing.T) {
+	cases := map[string]struct {
+		tamper       func(log, chain string) (string, string)
+		wantErr      string
+		wantSigned   int
+		wantUnsigned int
+	}{
+		"untouched": {
+			tamper:       func(log, chain string) (string, string) { return log, chain },
+			wantSigned:   4,
+			wantUnsigned: 2,
+		},
+		"line changed": {
+			tamper: func(log, chain string) (string, string) {
+				return strings.Replace(log, `"b"`, `"x"`, 1), chain
+			},
+			wantErr: "line 4",
+		},
+		"line removed": {
+			tamper: func(log, chain string) (string, string) {
+				return strings.Replace(log, "{\"b\"}\n", "", 1), chain
+			},
+			wantErr: "line 4",
+		},
+		"line inserted": {
+			tamper: func(log, chain string) (string, string) {
+				return strings.Replace(log, "{\"b\"}\n", "{\"b\"}\n{\"x\"}\n", 1), chain
+			},
+			wantErr: "line 5",
+		},
+		"lines reordered": {
+			tamper: func(log, chain string) (string, string) {
+				return strings.Replace(log, "{\"c\"}\n{\"d\"}\n", "{\"d\"}\n{\"c\"}\n", 1), chain
+			},
+			wantErr: "line 5",
+		},
+		"log truncated": {
+			tamper: func(log, chain string) (string, string) {
+				return strings.TrimSuffix(log, "{\"d\"}\n"), chain
+			},
+			wantErr: "missing lines",
+		},
+		"line appended": {
+			tamper: func(log, chain string) (string, string) {
+				return log + "{\"x\"}\n", chain
+			},
+			wantErr: "line 7 is after the end of the chain",
+		},
+		"partial line appended": {
+			tamper: func(log, chain string) (string, string) {
+				return log + "{\"x\"}", chain
+			},
+			wantErr: "line 7 is after the end of the chain",
+		},
+		"chain edited": {
+			tamper: func(log, chain string) (string, string) {
+				lines := strings.SplitAfter(chain, "\n")
+				return log, strings.Join(append(lines[:1], lines[2:]...), "")
+			},
+			wantErr: "line 5",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			key := testKey(t, MethodHMAC)
+			log, chain := &bytes.Buffer{}, &bytes.Buffer{}
+
+			// Two lines written before signing was enabled.
+			log.WriteString("{\"old1\"}\n{\"old2\"}\n")
+			writeChain(t, log, chain, key, "{\"a\"}\n", "{\"b\"}\n")
+			// Restarting continues the chain, lines can be split across writes.
+			writeChain(t, log, chain, key, "{\"c\"}\n{", "\"d\"}\n")
+
+			gotLog, gotChain := tc.tamper(log.String(), chain.String())
+			report, err := VerifyChain(strings.NewReader(gotLog), strings.NewReader(gotChain), key)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				assert.True(t, errors.Is(err, ErrTampered))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantSigned, report.Signed)
+			assert.Equal(t, tc.wantUnsigned, report.Unsigned)
+			assert.Equal(t, 6, report.Lines)
+		})
+	}
+}
+
+func TestVerifyChain_Gap(t *testing.T) {
+	key := testKey(t, MethodEd25519)
+	log, chain := &bytes.Buffer{}, &bytes.Buffer{}
+
+	writeChain(t, log, chain, key, "{\"a\"}\n")
+	// Written while signing was disabled.
+	log.WriteString("{\"b\"}\n")
+	writeChain(t, log, chain, key, "{\"c\"}\n")
+
+	report, err := VerifyChain(bytes.NewReader(log.Bytes()), bytes.NewReader(chain.Bytes()), key)
+	assert.NoError(t, err)
+	assert.Equal(t, &ChainReport{Lines: 3, Signed: 2, Unsigned: 1}, report)
+}
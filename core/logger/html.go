@@ -0,0 +1,293 @@
+package logger
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/josephlewis42/honeyssh/core/payload"
+)
+
+//go:embed report.html.tmpl
+var htmlReportTemplate string
+
+// htmlTopN is the number of items shown in "top" tables.
+const htmlTopN = 20
+
+// seriesColors holds the colors of chart series, they're picked in order.
+var seriesColors = []string{"#d9534f", "#5cb85c", "#5bc0de", "#f0ad4e", "#777777"}
+
+// HTMLReport renders the reports as a single self-contained HTML page.
+type HTMLReport struct {
+	Summary      *Report
+	Interactions *InteractionReport
+	Bugs         *BugReport
+
+	// GeneratedAt is the time the report was generated.
+	GeneratedAt time.Time
+
+	// OpenDownload opens a captured download by name, if nil or it returns an
+	// error the download won't be hashed.
+	OpenDownload func(name string) (io.ReadCloser, error)
+
+	// OpenTTYLog opens a session recording by name in asciicast format, if nil
+	// or it returns an error the recording won't be embedded.
+	OpenTTYLog func(name string) (io.ReadCloser, error)
+}
+
+// Render writes the HTML report to w.
+func (h *HTMLReport) Render(w io.Writer) error {
+	tmpl, err := template.New("report").Parse(htmlReportTemplate)
+	if err != nil {
+		return err
+	}
+
+	return tmpl.Execute(w, h.templateData())
+}
+
+type htmlReportData struct {
+	GeneratedAt string
+	Summary     *Report
+	Bugs        *BugReport
+
+	LoginChart      htmlChart
+	CredentialChart htmlChart
+
+	TopTables []htmlTopTable
+
+	Downloads []htmlDownload
+	Sessions  []htmlSession
+}
+
+type htmlTopTable struct {
+	Title string
+	Items []KeyCount
+}
+
+type htmlDownload struct {
+	Time      string
+	SessionID string
+	Name      string
+	Source    string
+	Command   string
+	SHA256    string
+	MD5       string
+}
+
+type htmlSession struct {
+	Anchor    string
+	ID        string
+	Start     string
+	Session   *InteractiveSession
+	Asciicast string
+
+	TypedLines []htmlTypedLine
+}
+
+type htmlTypedLine struct {
+	Time    string
+	Cadence string
+	Text    string
+}
+
+func (h *HTMLReport) templateData() *htmlReportData {
+	summary := h.Summary
+	if summary == nil {
+		summary = &Report{}
+	}
+	bugs := h.Bugs
+	if bugs == nil {
+		bugs = NewBugReport()
+	}
+	interactions := h.Interactions
+	if interactions == nil {
+		interactions = &InteractionReport{}
+	}
+
+	out := &htmlReportData{
+		GeneratedAt:     formatTime(h.GeneratedAt),
+		Summary:         summary,
+		Bugs:            bugs,
+		LoginChart:      newHTMLChart("Login attempts per day", &summary.LoginAttempt.Timeline),
+		CredentialChart: newHTMLChart("Credentials per day", &summary.LoginAttempt.CredentialTimeline),
+		TopTables: []htmlTopTable{
+			{"Usernames", summary.LoginAttempt.Usernames.Top(htmlTopN)},
+			{"Passwords", summary.LoginAttempt.Passwords.Top(htmlTopN)},
+			{"Credentials", summary.LoginAttempt.Credentials.Top(htmlTopN)},
+			{"Remote IPs", summary.LoginAttempt.RemoteIPs.Top(htmlTopN)},
+			{"Commands", summary.RunCommand.CommandNames.Top(htmlTopN)},
+			{"ATT&CK techniques", summary.RunCommand.AttackTechniques.Top(htmlTopN)},
+		},
+	}
+
+	for _, file := range summary.Download.Files {
+		download := htmlDownload{
+			Time:      formatTime(time.UnixMicro(file.TimestampMicros)),
+			SessionID: file.SessionID,
+			Name:      file.GetName(),
+			Source:    file.GetSource(),
+			Command:   strings.Join(file.GetCommand(), " "),
+		}
+		download.SHA256, download.MD5 = file.GetSha256(), file.GetMd5()
+		if download.SHA256 == "" {
+			// Downloads captured before hashes were logged.
+			download.SHA256, download.MD5 = h.hashDownload(file.GetName())
+		}
+		out.Downloads = append(out.Downloads, download)
+	}
+
+	for i, sessionID := range interactions.SessionIDs() {
+		session := interactions.Session(sessionID)
+		view := htmlSession{
+			Anchor:    fmt.Sprintf("session-%d", i),
+			ID:        sessionID,
+			Start:     formatTime(time.UnixMicro(session.StartTimeMicros)),
+			Session:   session,
+			Asciicast: h.readTTYLog(session.TTYLog),
+		}
+		for _, line := range session.TypedLines {
+			view.TypedLines = append(view.TypedLines, htmlTypedLine{
+				Time:    formatTime(time.UnixMicro(line.TimestampMicros)),
+				Cadence: line.Cadence,
+				Text:    line.Text,
+			})
+		}
+		out.Sessions = append(out.Sessions, view)
+	}
+
+	return out
+}
+
+func (h *HTMLReport) hashDownload(name string) (sha256Sum, md5Sum string) {
+	if h.OpenDownload == nil || name == "" {
+		return
+	}
+
+	fd, err := h.OpenDownload(name)
+	if err != nil {
+		return
+	}
+	defer fd.Close()
+
+	info, err := payload.HashReader(fd)
+	if err != nil {
+		return
+	}
+	return info.SHA256, info.MD5
+}
+
+func (h *HTMLReport) readTTYLog(name string) string {
+	if h.OpenTTYLog == nil || name == "" {
+		return ""
+	}
+
+	fd, err := h.OpenTTYLog(name)
+	if err != nil {
+		return ""
+	}
+	defer fd.Close()
+
+	data, err := io.ReadAll(fd)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// Chart dimensions in SVG units.
+const (
+	chartWidth   = 800
+	chartHeight  = 200
+	chartPadding = 20
+)
+
+type htmlChart struct {
+	Title  string
+	Width  int
+	Height int
+	Max    int
+	Legend []htmlChartLegend
+	Bars   []htmlChartBar
+	// Labels holds the first and last day of the chart.
+	FirstDay string
+	LastDay  string
+}
+
+type htmlChartLegend struct {
+	Name  string
+	Color string
+}
+
+type htmlChartBar struct {
+	X, Y, Width, Height float64
+	Color               string
+	Title               string
+}
+
+// newHTMLChart creates a stacked bar chart from a time series.
+func newHTMLChart(title string, ts *TimeSeries) htmlChart {
+	chart := htmlChart{
+		Title:  title,
+		Width:  chartWidth,
+		Height: chartHeight,
+	}
+
+	days := ts.Days()
+	series := ts.Series()
+	if len(days) == 0 {
+		return chart
+	}
+	chart.FirstDay = days[0]
+	chart.LastDay = days[len(days)-1]
+
+	for i, name := range series {
+		chart.Legend = append(chart.Legend, htmlChartLegend{
+			Name:  name,
+			Color: seriesColors[i%len(seriesColors)],
+		})
+	}
+
+	for _, day := range days {
+		total := 0
+		for _, name := range series {
+			total += ts.Count(day, name)
+		}
+		if total > chart.Max {
+			chart.Max = total
+		}
+	}
+
+	plotHeight := float64(chartHeight - chartPadding)
+	barWidth := float64(chartWidth) / float64(len(days))
+	for i, day := range days {
+		y := plotHeight
+		for j, name := range series {
+			count := ts.Count(day, name)
+			if count == 0 {
+				continue
+			}
+			height := plotHeight * float64(count) / float64(chart.Max)
+			y -= height
+			chart.Bars = append(chart.Bars, htmlChartBar{
+				X:      float64(i)*barWidth + barWidth*0.1,
+				Y:      y,
+				Width:  barWidth * 0.8,
+				Height: height,
+				Color:  seriesColors[j%len(seriesColors)],
+				Title:  fmt.Sprintf("%s %s: %d", day, name, count),
+			})
+		}
+	}
+
+	return chart
+}
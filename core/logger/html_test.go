@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeSeries_Days(t *testing.T) {
+	var ts TimeSeries
+	assert.Nil(t, ts.Days())
+
+	day := time.Date(2006, 1, 2, 3, 4, 5, 0, time.UTC)
+	ts.Increment(day.UnixMicro(), "FAILURE")
+	ts.Increment(day.AddDate(0, 0, 2).UnixMicro(), "SUCCESS")
+
+	assert.Equal(t, []string{"2006-01-02", "2006-01-03", "2006-01-04"}, ts.Days())
+	assert.Equal(t, []string{"FAILURE", "SUCCESS"}, ts.Series())
+	assert.Equal(t, 1, ts.Count("2006-01-02", "FAILURE"))
+	assert.Equal(t, 0, ts.Count("2006-01-03", "FAILURE"))
+
+	// An outlier timestamp doesn't fill in decades of empty days.
+	ts.Increment(0, "FAILURE")
+	ts.Increment(day.AddDate(0, 0, 2+MaxTimeSeriesGapDays+2).UnixMicro(), "FAILURE")
+	assert.Equal(t, []string{"1970-01-01", TimeSeriesGap, "2006-01-02", "2006-01-03", "2006-01-04", TimeSeriesGap, "2006-02-06"}, ts.Days())
+}
+
+func TestHTMLReport_Render(t *testing.T) {
+	timestamp := time.Date(2006, 1, 2, 3, 4, 5, 0, time.UTC).UnixMicro()
+	entries := []*LogEntry{
+		{
+			TimestampMicros: timestamp,
+			LogType: &LogEntry_LoginAttempt{LoginAttempt: &LoginAttempt{
+				Result:     OperationResult_FAILURE,
+				Username:   "root",
+				Password:   "hunter2",
+				RemoteAddr: "192.0.2.1:1234",
+			}},
+		},
+		{
+			TimestampMicros: timestamp,
+			SessionId:       "session-id",
+			LogType: &LogEntry_OpenTtyLog{OpenTtyLog: &OpenTTYLog{
+				Name: "recording.cast",
+			}},
+		},
+		{
+			TimestampMicros: timestamp,
+			SessionId:       "session-id",
+			LogType: &LogEntry_Download{Download: &Download{
+				Name:    "payload",
+				Source:  "http://example.com/<script>",
+				Command: []string{"wget", "http://example.com/<script>"},
+			}},
+		},
+	}
+
+	report := &HTMLReport{
+		Summary:      &Report{},
+		Interactions: &InteractionReport{},
+		Bugs:         NewBugReport(),
+		OpenDownload: func(name string) (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader("")), nil
+		},
+		OpenTTYLog: func(name string) (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader("{}\n[0, \"o\", \"</textarea>\"]\n")), nil
+		},
+	}
+	for _, le := range entries {
+		report.Summary.Update(le)
+		report.Interactions.Update(le)
+		report.Bugs.Update(le)
+	}
+	report.Interactions.Session("session-id").TypedLines = []TypedLine{
+		{TimestampMicros: timestamp, Text: "cat /etc/<shadow>", Cadence: "pasted"},
+	}
+
+	out := &bytes.Buffer{}
+	assert.NoError(t, report.Render(out))
+
+	html := out.String()
+	assert.Contains(t, html, "root / hunter2")
+	assert.Contains(t, html, "192.0.2.1")
+	assert.Contains(t, html, `id="session-0"`)
+	// SHA-256 of the empty string.
+	assert.Contains(t, html, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855")
+	assert.Contains(t, html, "cat /etc/&lt;shadow&gt;")
+	assert.NotContains(t, html, "http://example.com/<script>")
+	assert.Equal(t, 1, strings.Count(html, "</textarea>"))
+}
@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"sort"
 	"strings"
+	"time"
 
 	"google.golang.org/protobuf/encoding/protojson"
 )
@@ -77,11 +79,29 @@ type InteractiveSession struct {
 	TerminalName string `json:"terminal_name"`
 	IsPty        bool   `json:"is_pty"`
 
+	// Timestamp of the first event in the session.
+	StartTimeMicros int64 `json:"start_time_micros"`
+
 	Commands  []string `json:"commands"`
 	Downloads []string `json:"downloads"`
+
+	// TypedLines are reconstructed from the keystrokes in the TTY log, they
+	// aren't in the event log so they must be added separately.
+	TypedLines []TypedLine `json:"typed_lines,omitempty"`
+}
+
+// TypedLine is a line submitted at the terminal.
+type TypedLine struct {
+	TimestampMicros int64  `json:"timestamp_micros"`
+	Text            string `json:"text"`
+	// Cadence is how the line was typed: human, pasted or scripted.
+	Cadence string `json:"cadence"`
 }
 
 func (i *InteractiveSession) Update(le *LogEntry) {
+	if i.LogEntries == 0 || le.GetTimestampMicros() < i.StartTimeMicros {
+		i.StartTimeMicros = le.GetTimestampMicros()
+	}
 	i.LogEntries++
 
 	switch event := le.GetLogType().(type) {
@@ -93,7 +113,11 @@ func (i *InteractiveSession) Update(le *LogEntry) {
 	case *LogEntry_RunCommand:
 		i.Commands = append(i.Commands, strings.Join(event.RunCommand.GetCommand(), " "))
 	case *LogEntry_Download:
-		i.Downloads = append(i.Downloads, fmt.Sprintf("%q -> %q", event.Download.GetSource(), event.Download.GetName()))
+		name := event.Download.GetName()
+		if sha256Sum := event.Download.GetSha256(); sha256Sum != "" {
+			name = sha256Sum
+		}
+		i.Downloads = append(i.Downloads, fmt.Sprintf("%q -> %q", event.Download.GetSource(), name))
 	case *LogEntry_UnknownCommand:
 		i.Commands = append(i.Commands, strings.Join(event.UnknownCommand.GetCommand(), " "))
 	case *LogEntry_TerminalUpdate:
@@ -117,6 +141,32 @@ func (i *InteractionReport) MarshalJSON() ([]byte, error) {
 	return json.Marshal(i.interactions)
 }
 
+// SessionIDs returns the IDs of all sessions ordered by start time.
+func (i *InteractionReport) SessionIDs() []string {
+	i.init()
+
+	var out []string
+	for sessionID := range i.interactions {
+		out = append(out, sessionID)
+	}
+	sort.Slice(out, func(a, b int) bool {
+		aStart := i.interactions[out[a]].StartTimeMicros
+		bStart := i.interactions[out[b]].StartTimeMicros
+		if aStart == bStart {
+			return out[a] < out[b]
+		}
+		return aStart < bStart
+	})
+	return out
+}
+
+// Session returns the session with the given ID or nil if it doesn't exist.
+func (i *InteractionReport) Session(sessionID string) *InteractiveSession {
+	i.init()
+
+	return i.interactions[sessionID]
+}
+
 func (i *InteractionReport) Update(le *LogEntry) {
 	i.init()
 
@@ -145,6 +195,9 @@ type Report struct {
 	Credentials       CredentialsReport       `json:"credential_report"`
 	Download          DownloadReport          `json:"download_report"`
 	Panic             PanicReport             `json:"panic_report"`
+	PayloadAnalysis   PayloadAnalysisReport   `json:"payload_analysis_report"`
+	HoneytokenAccess  HoneytokenAccessReport  `json:"honeytoken_access_report"`
+	Filesystem        FilesystemReport        `json:"filesystem_report"`
 }
 
 func (r *Report) Update(le *LogEntry) {
@@ -152,18 +205,28 @@ func (r *Report) Update(le *LogEntry) {
 
 	switch event := le.GetLogType().(type) {
 	case *LogEntry_LoginAttempt:
-		r.LoginAttempt.update(event.LoginAttempt)
+		r.LoginAttempt.update(le.GetTimestampMicros(), event.LoginAttempt)
 	case *LogEntry_RunCommand:
 		r.RunCommand.update(event.RunCommand)
 	case *LogEntry_Panic:
 		r.Panic.update(event.Panic)
 	case *LogEntry_Download:
-		r.Download.update(event.Download)
+		r.Download.update(le, event.Download)
+	case *LogEntry_PayloadAnalysis:
+		r.PayloadAnalysis.update(event.PayloadAnalysis)
 	case *LogEntry_UnknownCommand:
 		r.UnknownCommand.update(event.UnknownCommand)
 	case *LogEntry_InvalidInvocation:
 		r.InvalidInvocation.update(event.InvalidInvocation)
-	case *LogEntry_TerminalUpdate, *LogEntry_HoneypotEvent, *LogEntry_OpenTtyLog:
+	case *LogEntry_HoneytokenAccess:
+		r.HoneytokenAccess.update(le, event.HoneytokenAccess)
+	case *LogEntry_FilesystemOperation:
+		r.Filesystem.updateOp(event.FilesystemOperation)
+	case *LogEntry_OpenFile:
+		r.Filesystem.updateOpen(event.OpenFile)
+	case *LogEntry_TerminalUpdate, *LogEntry_HoneypotEvent, *LogEntry_OpenTtyLog,
+		*LogEntry_ConnectionOpened, *LogEntry_ConnectionLost, *LogEntry_ScriptLine,
+		*LogEntry_BinaryExecution, *LogEntry_Pivot:
 		// Ignore
 	default:
 		r.InvalidEntries.Increment(fmt.Sprintf("%T", event))
@@ -177,12 +240,40 @@ type LoginAttemptReport struct {
 	Usernames StrCounter `json:"usernames"`
 	// List of login attempt results and their counts.
 	Results StrCounter `json:"results"`
+	// List of remote IPs and their counts.
+	RemoteIPs StrCounter `json:"remote_ips"`
+	// List of username/password pairs and their counts.
+	Credentials StrCounter `json:"credentials"`
+	// Login attempt results by day.
+	Timeline TimeSeries `json:"timeline"`
+	// New and reused username/password pairs by day.
+	CredentialTimeline TimeSeries `json:"credential_timeline"`
 }
 
-func (r *LoginAttemptReport) update(la *LoginAttempt) {
+func (r *LoginAttemptReport) update(timestampMicros int64, la *LoginAttempt) {
 	r.Passwords.Increment(la.Password)
 	r.Usernames.Increment(la.Username)
 	r.Results.Increment(la.GetResult().String())
+	r.Timeline.Increment(timestampMicros, la.GetResult().String())
+
+	if host, _, err := net.SplitHostPort(la.RemoteAddr); err == nil {
+		r.RemoteIPs.Increment(host)
+	} else if la.RemoteAddr != "" {
+		r.RemoteIPs.Increment(la.RemoteAddr)
+	}
+
+	credential := CredentialKey(la.Username, la.Password)
+	if r.Credentials.Count(credential) == 0 {
+		r.CredentialTimeline.Increment(timestampMicros, "new")
+	} else {
+		r.CredentialTimeline.Increment(timestampMicros, "reused")
+	}
+	r.Credentials.Increment(credential)
+}
+
+// CredentialKey formats a username and password for display.
+func CredentialKey(username, password string) string {
+	return fmt.Sprintf("%s / %s", username, password)
 }
 
 type RunCommandReport struct {
@@ -190,6 +281,10 @@ type RunCommandReport struct {
 	ResolvedCommandPaths StrCounter `json:"resolved_command_names"`
 	// Name of the command
 	CommandNames StrCounter `json:"command_names"`
+	// Categories the commands were classified as.
+	Categories StrCounter `json:"categories"`
+	// MITRE ATT&CK techniques the commands were classified as.
+	AttackTechniques StrCounter `json:"attack_techniques"`
 }
 
 func (r *RunCommandReport) update(rc *RunCommand) {
@@ -197,11 +292,17 @@ func (r *RunCommandReport) update(rc *RunCommand) {
 	if len(rc.Command) > 0 {
 		r.CommandNames.Increment(rc.Command[0])
 	}
+	r.Categories.IncrementAll(rc.Categories)
+	r.AttackTechniques.IncrementAll(rc.AttackTechniques)
 }
 
 type UnknownCommandReport struct {
 	CommandNames    StrCounter `json:"command_names"`
 	CommandStatuses StrCounter `json:"command_statuses"`
+	// Categories the commands were classified as.
+	Categories StrCounter `json:"categories"`
+	// MITRE ATT&CK techniques the commands were classified as.
+	AttackTechniques StrCounter `json:"attack_techniques"`
 }
 
 func (r *UnknownCommandReport) update(logEntry *UnknownCommand) {
@@ -210,6 +311,8 @@ func (r *UnknownCommandReport) update(logEntry *UnknownCommand) {
 	}
 
 	r.CommandStatuses.Increment(logEntry.Status.String())
+	r.Categories.IncrementAll(logEntry.Categories)
+	r.AttackTechniques.IncrementAll(logEntry.AttackTechniques)
 }
 
 type InvalidInvocationReport struct {
@@ -229,14 +332,83 @@ type DownloadReport struct {
 	Count        int        `json:"count"`
 	Sources      StrCounter `json:"sources"`
 	CommandNames StrCounter `json:"command_counts"`
+
+	// Files holds every download in the order they were seen.
+	Files []DownloadRecord `json:"-"`
+}
+
+// DownloadRecord is a download along with the context it was logged in.
+type DownloadRecord struct {
+	*Download
+
+	TimestampMicros int64
+	SessionID       string
 }
 
-func (r *DownloadReport) update(d *Download) {
+func (r *DownloadReport) update(le *LogEntry, d *Download) {
 	r.Count++
 	r.Sources.Increment(d.Source)
 	if len(d.Command) > 0 {
 		r.CommandNames.Increment(d.Command[0])
 	}
+	r.Files = append(r.Files, DownloadRecord{
+		Download:        d,
+		TimestampMicros: le.GetTimestampMicros(),
+		SessionID:       le.GetSessionId(),
+	})
+}
+
+type PayloadAnalysisReport struct {
+	// Kinds of payloads and their counts.
+	Kinds StrCounter `json:"kinds"`
+	// ELF architectures and their counts.
+	Architectures StrCounter `json:"architectures"`
+	// Triage rule matches and their counts.
+	RuleMatches StrCounter `json:"rule_matches"`
+}
+
+func (r *PayloadAnalysisReport) update(pa *PayloadAnalysis) {
+	r.Kinds.Increment(pa.GetKind())
+	if pa.GetArchitecture() != "" {
+		r.Architectures.Increment(pa.GetArchitecture())
+	}
+	r.RuleMatches.IncrementAll(pa.GetRuleMatches())
+}
+
+type HoneytokenAccessReport struct {
+	// Honeytoken paths and their counts.
+	Paths StrCounter `json:"paths"`
+	// Severities and their counts.
+	Severities StrCounter `json:"severities"`
+	// Sessions that accessed honeytokens and their counts.
+	Sessions StrCounter `json:"sessions"`
+}
+
+func (r *HoneytokenAccessReport) update(le *LogEntry, ha *HoneytokenAccess) {
+	r.Paths.Increment(ha.GetPath())
+	r.Severities.Increment(ha.GetSeverity())
+	r.Sessions.Increment(le.GetSessionId())
+}
+
+type FilesystemReport struct {
+	// Filesystem operations and their counts.
+	Ops StrCounter `json:"ops"`
+	// Changed paths and their counts.
+	Changed StrCounter `json:"changed"`
+	// Read paths and their counts.
+	Read StrCounter `json:"read"`
+}
+
+func (r *FilesystemReport) updateOp(op *FilesystemOp) {
+	r.Ops.Increment(op.GetOp().String())
+	r.Changed.Increment(op.GetPath())
+	if op.GetNewPath() != "" {
+		r.Changed.Increment(op.GetNewPath())
+	}
+}
+
+func (r *FilesystemReport) updateOpen(of *OpenFile) {
+	r.Read.Increment(of.GetPath())
 }
 
 type PanicReport struct {
@@ -261,11 +433,137 @@ func (s *StrCounter) Increment(toAdd string) {
 	s.internal[toAdd]++
 }
 
+// IncrementAll adds one to each of the given keys.
+func (s *StrCounter) IncrementAll(toAdd []string) {
+	for _, key := range toAdd {
+		s.Increment(key)
+	}
+}
+
+// Count returns the number of times the key was seen.
+func (s *StrCounter) Count(key string) int {
+	return s.internal[key]
+}
+
+// KeyCount is a key and the number of times it was seen.
+type KeyCount struct {
+	Key   string
+	Count int
+}
+
+// Top returns the n most frequently seen keys, ties are broken by key. If n is
+// less than zero, all keys are returned.
+func (s *StrCounter) Top(n int) []KeyCount {
+	var out []KeyCount
+	for k, v := range s.internal {
+		out = append(out, KeyCount{Key: k, Count: v})
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count == out[j].Count {
+			return out[i].Key < out[j].Key
+		}
+		return out[i].Count > out[j].Count
+	})
+
+	if n >= 0 && len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
+
 // MarshalJSON implemnts custom JSON marshaler.
 func (s StrCounter) MarshalJSON() ([]byte, error) {
 	return json.Marshal(s.internal)
 }
 
+// TimeSeries counts events in named series by UTC day.
+type TimeSeries struct {
+	// Map of day -> series -> count
+	internal map[string]map[string]int
+}
+
+// TimeSeriesDayFormat is the format days are stored in.
+const TimeSeriesDayFormat = "2006-01-02"
+
+// Increment adds one to the series on the day of the timestamp.
+func (t *TimeSeries) Increment(timestampMicros int64, series string) {
+	if t.internal == nil {
+		t.internal = make(map[string]map[string]int)
+	}
+
+	day := time.UnixMicro(timestampMicros).UTC().Format(TimeSeriesDayFormat)
+	if t.internal[day] == nil {
+		t.internal[day] = make(map[string]int)
+	}
+	t.internal[day][series]++
+}
+
+// TimeSeriesGap is returned by Days in place of a run of more than
+// MaxTimeSeriesGapDays days without events.
+const TimeSeriesGap = "..."
+
+// MaxTimeSeriesGapDays is the longest run of days without events Days fills
+// in. Longer runs, e.g. from a bogus timestamp, would make the series huge.
+const MaxTimeSeriesGapDays = 31
+
+// Days returns the days with events in order. Short runs of days without
+// events between them are included and long ones are replaced with
+// TimeSeriesGap.
+func (t *TimeSeries) Days() []string {
+	var seen []string
+	for day := range t.internal {
+		seen = append(seen, day)
+	}
+	if len(seen) == 0 {
+		return nil
+	}
+	sort.Strings(seen)
+
+	out := []string{seen[0]}
+	prev, _ := time.Parse(TimeSeriesDayFormat, seen[0])
+	for _, day := range seen[1:] {
+		next, _ := time.Parse(TimeSeriesDayFormat, day)
+		if next.Sub(prev) > (MaxTimeSeriesGapDays+1)*24*time.Hour {
+			out = append(out, TimeSeriesGap)
+		} else {
+			for empty := prev.AddDate(0, 0, 1); empty.Before(next); empty = empty.AddDate(0, 0, 1) {
+				out = append(out, empty.Format(TimeSeriesDayFormat))
+			}
+		}
+		out = append(out, day)
+		prev = next
+	}
+	return out
+}
+
+// Series returns the sorted names of all series.
+func (t *TimeSeries) Series() []string {
+	seen := make(map[string]bool)
+	for _, series := range t.internal {
+		for name := range series {
+			seen[name] = true
+		}
+	}
+
+	var out []string
+	for name := range seen {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Count returns the number of events in the series on the given day.
+func (t *TimeSeries) Count(day, series string) int {
+	return t.internal[day][series]
+}
+
+// MarshalJSON implemnts custom JSON marshaler.
+func (t TimeSeries) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.internal)
+}
+
 func NewPathCounter(cols ...string) *PathCounter {
 	return &PathCounter{
 		cols:     cols,
@@ -288,17 +586,23 @@ func (ctr *PathCounter) Increment(toAdd ...string) {
 	ctr.internal[toKey(toAdd...)]++
 }
 
-// MarshalJSON implemnts custom JSON marshaler.
-func (ctr *PathCounter) MarshalJSON() ([]byte, error) {
-	type Count struct {
-		Count  int               `json:"count"`
-		Fields map[string]string `json:"event"`
-		Path   string            `json:"-"`
-	}
+// PathCount is a set of column values and the number of times they were seen.
+type PathCount struct {
+	Count  int               `json:"count"`
+	Fields map[string]string `json:"event"`
+	Path   string            `json:"-"`
+}
 
-	var out []Count
+// Columns returns the names of the counter's columns.
+func (ctr *PathCounter) Columns() []string {
+	return ctr.cols
+}
+
+// Entries returns the counts ordered from most to least frequent.
+func (ctr *PathCounter) Entries() []PathCount {
+	var out []PathCount
 	for k, v := range ctr.internal {
-		count := Count{
+		count := PathCount{
 			Count:  v,
 			Path:   k,
 			Fields: make(map[string]string),
@@ -319,7 +623,12 @@ func (ctr *PathCounter) MarshalJSON() ([]byte, error) {
 		return out[i].Count > out[j].Count
 	})
 
-	return json.Marshal(out)
+	return out
+}
+
+// MarshalJSON implemnts custom JSON marshaler.
+func (ctr *PathCounter) MarshalJSON() ([]byte, error) {
+	return json.Marshal(ctr.Entries())
 }
 
 func toKey(vals ...string) string {
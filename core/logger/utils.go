@@ -12,6 +12,21 @@ import (
 // LogRecorder is a callback that stores events in an external datastore.
 type LogRecorder func(le *LogEntry) error
 
+// MultiLogRecorder creates a LogRecorder that sends each event to all the
+// given recorders in order. The first error is returned, but every recorder
+// still sees the event.
+func MultiLogRecorder(recorders ...LogRecorder) LogRecorder {
+	return func(le *LogEntry) error {
+		var firstErr error
+		for _, record := range recorders {
+			if err := record(le); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		return firstErr
+	}
+}
+
 // Logger captures interaction event logs for the honeypot to determine its
 // performance.
 type Logger struct {
@@ -33,10 +48,11 @@ func NewJsonLinesLogRecorder(w io.Writer) *Logger {
 	}
 }
 
-func (l *Logger) recordLogType(sessionID string, event isLogEntry_LogType) error {
+func (l *Logger) recordLogType(sessionID, parentSessionID string, event isLogEntry_LogType) error {
 	le := &LogEntry{}
 	le.TimestampMicros = time.Now().UnixMicro()
 	le.SessionId = sessionID
+	le.ParentSessionId = parentSessionID
 	le.LogType = event
 
 	return l.Record(le)
@@ -55,13 +71,19 @@ func (l *Logger) Sessionless() *SessionLogger {
 // SessionLogger logs messages with a shared session ID.
 type SessionLogger struct {
 	*Logger
-	sessionID string
+	sessionID       string
+	parentSessionID string
 }
 
 type LogType = isLogEntry_LogType
 
 func (l *SessionLogger) Record(event LogType) error {
-	return l.recordLogType(l.sessionID, event)
+	return l.recordLogType(l.sessionID, l.parentSessionID, event)
+}
+
+// Child creates a logger for a session started from this one.
+func (l *SessionLogger) Child(sessionID string) *SessionLogger {
+	return &SessionLogger{Logger: l.Logger, sessionID: sessionID, parentSessionID: l.sessionID}
 }
 
 func (l *SessionLogger) Print(event LogType) error {
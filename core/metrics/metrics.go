@@ -0,0 +1,190 @@
+// Package metrics exposes the honeypot's health and load as Prometheus
+// metrics, derived from the same events the logger records.
+package metrics
+
+import (
+	"net/http"
+	"path"
+	"sync"
+
+	"github.com/josephlewis42/honeyssh/core/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	namespace = "honeyssh"
+
+	// MaxCommandLabels bounds the number of distinct command names exported,
+	// commands seen after the limit is reached are counted as OtherCommand.
+	MaxCommandLabels = 100
+
+	// OtherCommand is the label used for commands past MaxCommandLabels.
+	OtherCommand = "other"
+)
+
+// Metrics tracks honeypot events in a Prometheus registry.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	connections     prometheus.Counter
+	authAttempts    *prometheus.CounterVec
+	activeSessions  prometheus.Gauge
+	commands        *prometheus.CounterVec
+	unknownCommands *prometheus.CounterVec
+	downloads       prometheus.Counter
+	capturedBytes   *prometheus.CounterVec
+	panics          prometheus.Counter
+	honeytokens     *prometheus.CounterVec
+	filesystemOps   *prometheus.CounterVec
+
+	mu           sync.Mutex
+	commandNames map[string]bool
+}
+
+// New creates a new set of metrics. memoryUsage reports the bytes held by
+// tenant filesystems, it may be nil.
+func New(memoryUsage func() int64) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		connections: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "connections_total",
+			Help:      "Number of TCP connections accepted.",
+		}),
+		authAttempts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "auth_attempts_total",
+			Help:      "Number of authentication attempts by result.",
+		}, []string{"result"}),
+		activeSessions: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "active_sessions",
+			Help:      "Number of authenticated sessions currently open.",
+		}),
+		commands: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "commands_total",
+			Help:      "Number of commands executed by command name.",
+		}, []string{"command"}),
+		unknownCommands: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "unknown_commands_total",
+			Help:      "Number of commands that couldn't be run by status.",
+		}, []string{"status"}),
+		downloads: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "downloads_total",
+			Help:      "Number of files downloaded or uploaded by attackers.",
+		}),
+		capturedBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "captured_bytes_total",
+			Help:      "Number of bytes written to disk by kind (download or tty).",
+		}, []string{"kind"}),
+		panics: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "panics_total",
+			Help:      "Number of panics recovered.",
+		}),
+		honeytokens: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "honeytoken_accesses_total",
+			Help:      "Number of times honeytokens were opened by severity.",
+		}, []string{"severity"}),
+		filesystemOps: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "filesystem_operations_total",
+			Help:      "Number of changes made to tenant filesystems by operation.",
+		}, []string{"op"}),
+		commandNames: make(map[string]bool),
+	}
+
+	m.registry.MustRegister(
+		m.connections,
+		m.authAttempts,
+		m.activeSessions,
+		m.commands,
+		m.unknownCommands,
+		m.downloads,
+		m.capturedBytes,
+		m.panics,
+		m.honeytokens,
+		m.filesystemOps,
+	)
+
+	if memoryUsage != nil {
+		m.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "tenant_vfs_bytes",
+			Help:      "Bytes held in memory by open tenant filesystems.",
+		}, func() float64 {
+			return float64(memoryUsage())
+		}))
+	}
+
+	return m
+}
+
+// Record updates the metrics with the given event, it's a logger.LogRecorder.
+func (m *Metrics) Record(le *logger.LogEntry) error {
+	switch event := le.GetLogType().(type) {
+	case *logger.LogEntry_ConnectionOpened:
+		m.connections.Inc()
+	case *logger.LogEntry_LoginAttempt:
+		result := event.LoginAttempt.GetResult()
+		m.authAttempts.WithLabelValues(result.String()).Inc()
+		// Successful logins are recorded once per session.
+		if result == logger.OperationResult_SUCCESS {
+			m.activeSessions.Inc()
+		}
+	case *logger.LogEntry_ConnectionLost:
+		m.activeSessions.Dec()
+		m.capturedBytes.WithLabelValues("tty").Add(float64(event.ConnectionLost.GetTtyLogSize()))
+	case *logger.LogEntry_RunCommand:
+		m.commands.WithLabelValues(m.commandLabel(event.RunCommand)).Inc()
+	case *logger.LogEntry_UnknownCommand:
+		m.unknownCommands.WithLabelValues(event.UnknownCommand.GetStatus().String()).Inc()
+	case *logger.LogEntry_Download:
+		m.downloads.Inc()
+		m.capturedBytes.WithLabelValues("download").Add(float64(event.Download.GetSize()))
+	case *logger.LogEntry_Panic:
+		m.panics.Inc()
+	case *logger.LogEntry_HoneytokenAccess:
+		m.honeytokens.WithLabelValues(event.HoneytokenAccess.GetSeverity()).Inc()
+	case *logger.LogEntry_FilesystemOperation:
+		m.filesystemOps.WithLabelValues(event.FilesystemOperation.GetOp().String()).Inc()
+	}
+
+	return nil
+}
+
+// commandLabel returns the label for a command, bounding the number of
+// distinct labels to MaxCommandLabels.
+func (m *Metrics) commandLabel(rc *logger.RunCommand) string {
+	name := path.Base(rc.GetResolvedCommandPath())
+	if rc.GetResolvedCommandPath() == "" && len(rc.GetCommand()) > 0 {
+		name = path.Base(rc.GetCommand()[0])
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.commandNames[name] {
+		return name
+	}
+	if len(m.commandNames) >= MaxCommandLabels {
+		return OtherCommand
+	}
+	m.commandNames[name] = true
+	return name
+}
+
+// Handler returns an HTTP handler that serves the metrics.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Gatherer returns the underlying Prometheus gatherer.
+func (m *Metrics) Gatherer() prometheus.Gatherer {
+	return m.registry
+}
@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/josephlewis42/honeyssh/core/logger"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics_Record(t *testing.T) {
+	m := New(func() int64 { return 1024 })
+
+	events := []logger.LogType{
+		&logger.LogEntry_ConnectionOpened{ConnectionOpened: &logger.ConnectionOpened{}},
+		&logger.LogEntry_LoginAttempt{LoginAttempt: &logger.LoginAttempt{Result: logger.OperationResult_FAILURE}},
+		&logger.LogEntry_LoginAttempt{LoginAttempt: &logger.LoginAttempt{Result: logger.OperationResult_SUCCESS}},
+		&logger.LogEntry_RunCommand{RunCommand: &logger.RunCommand{ResolvedCommandPath: "/bin/ls"}},
+		&logger.LogEntry_RunCommand{RunCommand: &logger.RunCommand{ResolvedCommandPath: "/usr/bin/ls"}},
+		&logger.LogEntry_UnknownCommand{UnknownCommand: &logger.UnknownCommand{Status: logger.UnknownCommand_NOT_FOUND}},
+		&logger.LogEntry_Download{Download: &logger.Download{Size: 100}},
+		&logger.LogEntry_Panic{Panic: &logger.Panic{}},
+		&logger.LogEntry_ConnectionLost{ConnectionLost: &logger.ConnectionLost{TtyLogSize: 50}},
+		&logger.LogEntry_HoneytokenAccess{HoneytokenAccess: &logger.HoneytokenAccess{Severity: "critical"}},
+		&logger.LogEntry_FilesystemOperation{FilesystemOperation: &logger.FilesystemOp{Op: logger.FilesystemOp_CHMOD}},
+	}
+	for _, event := range events {
+		assert.NoError(t, m.Record(&logger.LogEntry{LogType: event}))
+	}
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.connections))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.authAttempts.WithLabelValues("FAILURE")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.authAttempts.WithLabelValues("SUCCESS")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(m.activeSessions))
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.commands.WithLabelValues("ls")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.unknownCommands.WithLabelValues("NOT_FOUND")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.downloads))
+	assert.Equal(t, 100.0, testutil.ToFloat64(m.capturedBytes.WithLabelValues("download")))
+	assert.Equal(t, 50.0, testutil.ToFloat64(m.capturedBytes.WithLabelValues("tty")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.panics))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.honeytokens.WithLabelValues("critical")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.filesystemOps.WithLabelValues("CHMOD")))
+
+	recorder := httptest.NewRecorder()
+	m.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Contains(t, recorder.Body.String(), "honeyssh_tenant_vfs_bytes 1024")
+}
+
+func TestMetrics_commandLabel(t *testing.T) {
+	m := New(nil)
+
+	for i := 0; i < MaxCommandLabels+10; i++ {
+		m.Record(&logger.LogEntry{LogType: &logger.LogEntry_RunCommand{
+			RunCommand: &logger.RunCommand{ResolvedCommandPath: fmt.Sprintf("/bin/cmd%d", i)},
+		}})
+	}
+
+	assert.Equal(t, MaxCommandLabels+1, testutil.CollectAndCount(m.commands))
+	assert.Equal(t, 10.0, testutil.ToFloat64(m.commands.WithLabelValues(OtherCommand)))
+	assert.Equal(t, "cmd0", m.commandLabel(&logger.RunCommand{Command: []string{"/bin/cmd0"}}))
+}
@@ -0,0 +1,86 @@
+package monitor
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net"
+
+	"github.com/josephlewis42/honeyssh/core/ttylog"
+)
+
+// call sends the request to the server at socketPath and reads its response.
+// The connection is left open so streamed data can be read from r.
+func call(socketPath string, req *request) (net.Conn, *bufio.Reader, *response, error) {
+	conn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	r, resp, err := roundTrip(conn, req)
+	if err != nil {
+		conn.Close()
+		return nil, nil, nil, err
+	}
+	return conn, r, resp, nil
+}
+
+func roundTrip(conn net.Conn, req *request) (*bufio.Reader, *response, error) {
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, nil, err
+	}
+
+	r := bufio.NewReader(conn)
+	line, err := r.ReadBytes('\n')
+	if err != nil {
+		return nil, nil, err
+	}
+	resp := &response{}
+	if err := json.Unmarshal(line, resp); err != nil {
+		return nil, nil, err
+	}
+	if resp.Error != "" {
+		return nil, nil, errors.New(resp.Error)
+	}
+	return r, resp, nil
+}
+
+// List returns the active sessions.
+func List(socketPath string) ([]SessionInfo, error) {
+	conn, _, resp, err := call(socketPath, &request{Command: commandList})
+	if err != nil {
+		return nil, err
+	}
+	conn.Close()
+	return resp.Sessions, nil
+}
+
+// Watch streams the session to sink, starting with its recent output, until
+// the session ends or sink returns an error.
+func Watch(socketPath, sessionID string, sink ttylog.LogSink) error {
+	conn, r, _, err := call(socketPath, &request{Command: commandWatch, SessionID: sessionID})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return ttylog.Replay(ttylog.NewProtoLogSource(r), sink)
+}
+
+// Wall sends a message to the session's terminal formatted like wall(1).
+func Wall(socketPath, sessionID, message string) error {
+	conn, _, _, err := call(socketPath, &request{Command: commandWall, SessionID: sessionID, Message: message})
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// Kill ends the session.
+func Kill(socketPath, sessionID string) error {
+	conn, _, _, err := call(socketPath, &request{Command: commandKill, SessionID: sessionID})
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
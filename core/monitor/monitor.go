@@ -0,0 +1,270 @@
+// Package monitor broadcasts active sessions to operators over a local UNIX
+// socket so they can be watched live, messaged or killed.
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/josephlewis42/honeyssh/core/ttylog"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// maxBacklogBytes is the amount of recent output sent to new watchers so
+	// they see more than a blank screen.
+	maxBacklogBytes = 64 * 1024
+
+	// watcherBuffer is the number of entries queued for a watcher before it's
+	// disconnected for falling behind, so slow watchers never stall a session.
+	watcherBuffer = 1024
+)
+
+var (
+	// ErrNoSession is returned when a session isn't active.
+	ErrNoSession = errors.New("no such session")
+	// ErrControlDisabled is returned when sessions can't be controlled.
+	ErrControlDisabled = errors.New("session control is disabled")
+)
+
+// SessionInfo describes an active session.
+type SessionInfo struct {
+	ID         string    `json:"id"`
+	User       string    `json:"user"`
+	RemoteAddr string    `json:"remote_addr"`
+	StartTime  time.Time `json:"start_time"`
+	// TTYLog is the name of the session's recording.
+	TTYLog string `json:"tty_log"`
+	// Watchers is the number of operators watching the session.
+	Watchers int `json:"watchers"`
+}
+
+// Session is an active session that can be watched.
+type Session struct {
+	info SessionInfo
+
+	// Inject writes output to the session's terminal, it may be nil.
+	Inject func(data []byte) error
+	// Kill ends the session, it may be nil.
+	Kill func() error
+
+	mu           sync.Mutex
+	resize       *ttylog.TTYLogEntry
+	backlog      []*ttylog.TTYLogEntry
+	backlogBytes int
+	watchers     map[chan *ttylog.TTYLogEntry]bool
+	closed       bool
+}
+
+// NewSession creates a session, it must be registered with a Hub to be seen.
+func NewSession(info SessionInfo) *Session {
+	return &Session{
+		info:     info,
+		watchers: make(map[chan *ttylog.TTYLogEntry]bool),
+	}
+}
+
+// Info returns a description of the session.
+func (s *Session) Info() SessionInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info := s.info
+	info.Watchers = len(s.watchers)
+	return info
+}
+
+// Record sends the entry to watchers, it's a ttylog.LogSink.
+func (s *Session) Record(entry *ttylog.TTYLogEntry) error {
+	// The recorder may reuse the entry's buffers.
+	entry = proto.Clone(entry).(*ttylog.TTYLogEntry)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+
+	switch event := entry.GetEvent().(type) {
+	case *ttylog.TTYLogEntry_Resize:
+		s.resize = entry
+	case *ttylog.TTYLogEntry_Io:
+		s.backlog = append(s.backlog, entry)
+		s.backlogBytes += len(event.Io.GetData())
+		for len(s.backlog) > 1 && s.backlogBytes > maxBacklogBytes {
+			s.backlogBytes -= len(s.backlog[0].GetIo().GetData())
+			s.backlog = s.backlog[1:]
+		}
+	}
+
+	for watcher := range s.watchers {
+		select {
+		case watcher <- entry:
+		default:
+			delete(s.watchers, watcher)
+			close(watcher)
+		}
+	}
+	return nil
+}
+
+// watch returns a channel of entries starting with the terminal size and
+// recent output, it's closed when the session ends or the watcher falls
+// behind. cancel must be called when the watcher is done.
+func (s *Session) watch() (entries <-chan *ttylog.TTYLogEntry, cancel func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	watcher := make(chan *ttylog.TTYLogEntry, watcherBuffer+len(s.backlog)+1)
+	if s.resize != nil {
+		watcher <- s.resize
+	}
+	for _, entry := range s.backlog {
+		watcher <- entry
+	}
+
+	if s.closed {
+		close(watcher)
+		return watcher, func() {}
+	}
+
+	s.watchers[watcher] = true
+	return watcher, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.watchers[watcher] {
+			delete(s.watchers, watcher)
+			close(watcher)
+		}
+	}
+}
+
+func (s *Session) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	for watcher := range s.watchers {
+		close(watcher)
+	}
+	s.watchers = nil
+}
+
+// Hub tracks active sessions.
+type Hub struct {
+	// AllowControl allows operators to inject messages and kill sessions.
+	AllowControl bool
+
+	mu sync.Mutex
+	// hostname is shown as the sender of injected messages.
+	hostname string
+	sessions map[string]*Session
+}
+
+// NewHub creates an empty hub.
+func NewHub() *Hub {
+	return &Hub{
+		sessions: make(map[string]*Session),
+	}
+}
+
+// SetHostname sets the hostname shown as the sender of injected messages.
+func (h *Hub) SetHostname(hostname string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.hostname = hostname
+}
+
+// Register makes the session visible to operators until it's unregistered.
+func (h *Hub) Register(session *Session) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.sessions[session.info.ID] = session
+}
+
+// Unregister removes the session and disconnects its watchers.
+func (h *Hub) Unregister(session *Session) {
+	h.mu.Lock()
+	if h.sessions[session.info.ID] == session {
+		delete(h.sessions, session.info.ID)
+	}
+	h.mu.Unlock()
+
+	session.close()
+}
+
+// Sessions returns the active sessions ordered by start time.
+func (h *Hub) Sessions() []SessionInfo {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	out := []SessionInfo{}
+	for _, session := range h.sessions {
+		out = append(out, session.Info())
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].StartTime.Equal(out[j].StartTime) {
+			return out[i].ID < out[j].ID
+		}
+		return out[i].StartTime.Before(out[j].StartTime)
+	})
+	return out
+}
+
+func (h *Hub) session(id string) (*Session, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	session, ok := h.sessions[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrNoSession, id)
+	}
+	return session, nil
+}
+
+// Wall sends a message to the session's terminal formatted like wall(1).
+func (h *Hub) Wall(id, message string) error {
+	if !h.AllowControl {
+		return ErrControlDisabled
+	}
+	session, err := h.session(id)
+	if err != nil {
+		return err
+	}
+	if session.Inject == nil {
+		return ErrControlDisabled
+	}
+	h.mu.Lock()
+	hostname := h.hostname
+	h.mu.Unlock()
+	return session.Inject(WallMessage(hostname, time.Now(), message))
+}
+
+// Kill ends the session.
+func (h *Hub) Kill(id string) error {
+	if !h.AllowControl {
+		return ErrControlDisabled
+	}
+	session, err := h.session(id)
+	if err != nil {
+		return err
+	}
+	if session.Kill == nil {
+		return ErrControlDisabled
+	}
+	return session.Kill()
+}
+
+// WallMessage formats a broadcast message like wall(1).
+func WallMessage(hostname string, now time.Time, message string) []byte {
+	// The terminal is in raw mode so newlines need carriage returns.
+	message = strings.ReplaceAll(strings.ReplaceAll(message, "\r\n", "\n"), "\n", "\r\n")
+	return []byte(fmt.Sprintf("\r\nBroadcast message from root@%s (%s):\r\n\r\n%s\r\n\r\n",
+		hostname, now.Format("Mon Jan 2 15:04:05 2006"), message))
+}
@@ -0,0 +1,116 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/josephlewis42/honeyssh/core/ttylog"
+	"github.com/stretchr/testify/assert"
+)
+
+func stdout(data string) *ttylog.TTYLogEntry {
+	return &ttylog.TTYLogEntry{
+		Event: &ttylog.TTYLogEntry_Io{Io: &ttylog.IO{Fd: ttylog.FD_STDOUT, Data: []byte(data)}},
+	}
+}
+
+func TestHub(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "monitor", "monitor.sock")
+	listener, err := Listen(socketPath)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer listener.Close()
+
+	info, err := os.Stat(socketPath)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	info, err = os.Stat(filepath.Dir(socketPath))
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0700), info.Mode().Perm())
+
+	hub := NewHub()
+	hub.SetHostname("web01")
+	go hub.Serve(listener)
+
+	var injected []string
+	killed := false
+	session := NewSession(SessionInfo{ID: "1234", User: "root", StartTime: time.Unix(1, 0)})
+	session.Inject = func(data []byte) error {
+		injected = append(injected, string(data))
+		return nil
+	}
+	session.Kill = func() error {
+		killed = true
+		return nil
+	}
+	hub.Register(session)
+
+	session.Record(&ttylog.TTYLogEntry{
+		Event: &ttylog.TTYLogEntry_Resize{Resize: &ttylog.Resize{Width: 100, Height: 30}},
+	})
+	session.Record(stdout("before watching\r\n"))
+
+	sessions, err := List(socketPath)
+	assert.NoError(t, err)
+	if assert.Len(t, sessions, 1) {
+		assert.Equal(t, "1234", sessions[0].ID)
+		assert.Equal(t, "root", sessions[0].User)
+	}
+
+	t.Run("watch", func(t *testing.T) {
+		var got []*ttylog.TTYLogEntry
+		done := make(chan error)
+		go func() {
+			done <- Watch(socketPath, "1234", func(entry *ttylog.TTYLogEntry) error {
+				got = append(got, entry)
+				return nil
+			})
+		}()
+
+		// Wait for the watcher to connect.
+		for session.Info().Watchers == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		session.Record(stdout("live\r\n"))
+		hub.Unregister(session)
+
+		assert.NoError(t, <-done)
+		if assert.Len(t, got, 3) {
+			assert.Equal(t, int32(100), got[0].GetResize().GetWidth())
+			assert.Equal(t, "before watching\r\n", string(got[1].GetIo().GetData()))
+			assert.Equal(t, "live\r\n", string(got[2].GetIo().GetData()))
+		}
+		hub.Register(session)
+	})
+
+	t.Run("unknown session", func(t *testing.T) {
+		assert.ErrorContains(t, Watch(socketPath, "5678", func(*ttylog.TTYLogEntry) error { return nil }), "no such session")
+		assert.ErrorContains(t, Kill(socketPath, "5678"), "session control is disabled")
+	})
+
+	t.Run("control disabled", func(t *testing.T) {
+		assert.ErrorContains(t, Wall(socketPath, "1234", "hello"), "session control is disabled")
+		assert.ErrorContains(t, Kill(socketPath, "1234"), "session control is disabled")
+		assert.Empty(t, injected)
+		assert.False(t, killed)
+	})
+
+	t.Run("control", func(t *testing.T) {
+		hub.AllowControl = true
+		defer func() { hub.AllowControl = false }()
+
+		assert.NoError(t, Wall(socketPath, "1234", "maintenance\nin 5 minutes"))
+		if assert.Len(t, injected, 1) {
+			assert.True(t, strings.HasPrefix(injected[0], "\r\nBroadcast message from root@web01 ("))
+			assert.True(t, strings.HasSuffix(injected[0], "maintenance\r\nin 5 minutes\r\n\r\n"))
+		}
+
+		assert.ErrorContains(t, Kill(socketPath, "5678"), "no such session")
+		assert.NoError(t, Kill(socketPath, "1234"))
+		assert.True(t, killed)
+	})
+}
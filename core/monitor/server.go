@@ -0,0 +1,151 @@
+package monitor
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/josephlewis42/honeyssh/core/ttylog"
+)
+
+// Commands understood by the server.
+const (
+	commandList  = "list"
+	commandWatch = "watch"
+	commandWall  = "wall"
+	commandKill  = "kill"
+)
+
+// request is sent by clients as a single JSON line.
+type request struct {
+	Command   string `json:"command"`
+	SessionID string `json:"session_id,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// response is sent by the server as a single JSON line. Successful watch
+// responses are followed by a stream of length delimited TTYLogEntry
+// protobufs until the session ends.
+type response struct {
+	Error    string        `json:"error,omitempty"`
+	Sessions []SessionInfo `json:"sessions,omitempty"`
+}
+
+// Listen creates a UNIX socket at path that only the current user can
+// connect to. The socket's directory is created if needed and restricted to
+// the current user so the socket is never reachable by others, even briefly.
+func Listen(path string) (net.Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	// Remove sockets left behind by a previous run.
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
+
+// Serve handles operator connections until the listener is closed.
+func (h *Hub) Serve(listener net.Listener) error {
+	for {
+		conn, err := listener.Accept()
+		switch {
+		case errors.Is(err, net.ErrClosed):
+			return nil
+		case err != nil:
+			return err
+		}
+		go h.handle(conn)
+	}
+}
+
+func (h *Hub) handle(conn net.Conn) {
+	defer conn.Close()
+
+	var req request
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		writeResponse(conn, &response{Error: err.Error()})
+		return
+	}
+
+	var err error
+	switch req.Command {
+	case commandList:
+		writeResponse(conn, &response{Sessions: h.Sessions()})
+		return
+	case commandWatch:
+		err = h.serveWatch(conn, req.SessionID)
+	case commandWall:
+		err = h.Wall(req.SessionID, req.Message)
+	case commandKill:
+		err = h.Kill(req.SessionID)
+		if err == nil {
+			log.Printf("Operator killed session %s\n", req.SessionID)
+		}
+	default:
+		err = errors.New("unknown command: " + req.Command)
+	}
+
+	if err != nil {
+		writeResponse(conn, &response{Error: err.Error()})
+		return
+	}
+	if req.Command != commandWatch {
+		writeResponse(conn, &response{})
+	}
+}
+
+func (h *Hub) serveWatch(conn net.Conn, sessionID string) error {
+	session, err := h.session(sessionID)
+	if err != nil {
+		return err
+	}
+	entries, cancel := session.watch()
+	defer cancel()
+
+	if err := writeResponse(conn, &response{}); err != nil {
+		return nil
+	}
+
+	// Stop when the watcher hangs up.
+	go func() {
+		io.Copy(io.Discard, conn)
+		cancel()
+	}()
+
+	writer := ttylog.NewProtoLogWriter(conn, false)
+	for entry := range entries {
+		if err := writer.Write(entry); err != nil {
+			break
+		}
+	}
+	return nil
+}
+
+func writeResponse(w io.Writer, resp *response) error {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	bw.Write(data)
+	bw.WriteByte('\n')
+	return bw.Flush()
+}
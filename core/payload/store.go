@@ -0,0 +1,424 @@
+// Package payload stores files captured from attackers in a content addressed
+// store so each unique payload is kept once no matter how often it's fetched.
+package payload
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/glaslos/ssdeep"
+	"github.com/josephlewis42/honeyssh/core/integrity"
+	"github.com/josephlewis42/honeyssh/core/triage"
+	"github.com/spf13/afero"
+)
+
+const (
+	// PayloadName is the name of the file holding a payload's contents.
+	PayloadName = "payload"
+	// InfoName is the name of the file holding a payload's Info.
+	InfoName = "info.json"
+	// SightingsName is the name of the newline delimited JSON file holding a
+	// payload's sightings.
+	SightingsName = "sightings.jsonl"
+
+	// tmpDirName holds captures that are still being written.
+	tmpDirName = "tmp"
+)
+
+// ErrNotFound is returned when a payload isn't in the store.
+var ErrNotFound = errors.New("payload not found")
+
+// Hashes identify a payload.
+type Hashes struct {
+	SHA256 string `json:"sha256"`
+	MD5    string `json:"md5"`
+	SHA1   string `json:"sha1"`
+	// SSDEEP is the fuzzy hash of the payload, it's empty for payloads too
+	// small to fuzzy hash.
+	SSDEEP string `json:"ssdeep,omitempty"`
+}
+
+// Info describes a stored payload.
+type Info struct {
+	Hashes
+	// Size of the payload in bytes.
+	Size int64 `json:"size"`
+	// Analysis holds the results of static triage, if it was run.
+	Analysis *triage.Analysis `json:"analysis,omitempty"`
+}
+
+// Sighting is a single capture of a payload.
+type Sighting struct {
+	// ID uniquely identifies the capture.
+	ID string `json:"id"`
+	// Time the capture started.
+	Time time.Time `json:"time"`
+	// SessionID of the session that captured the payload.
+	SessionID string `json:"session_id"`
+	// Source of the payload e.g. a URL.
+	Source string `json:"source"`
+	// Command that captured the payload.
+	Command []string `json:"command"`
+	// Parent is the SHA-256 of the script the payload's URL was harvested
+	// from, empty if the attacker captured it.
+	Parent string `json:"parent,omitempty"`
+}
+
+// Entry is a payload and all of its sightings.
+type Entry struct {
+	*Info
+	Sightings []Sighting `json:"sightings"`
+}
+
+// FirstSeen returns the time of the earliest sighting.
+func (e *Entry) FirstSeen() time.Time {
+	if len(e.Sightings) == 0 {
+		return time.Time{}
+	}
+	return e.Sightings[0].Time
+}
+
+// LastSeen returns the time of the latest sighting.
+func (e *Entry) LastSeen() time.Time {
+	if len(e.Sightings) == 0 {
+		return time.Time{}
+	}
+	return e.Sightings[len(e.Sightings)-1].Time
+}
+
+// Store is a content addressed payload store. Payloads are kept in
+// <sha256[:2]>/<sha256>/ along with their Info and sightings.
+type Store struct {
+	fs  afero.Fs
+	mu  sync.Mutex
+	key *integrity.Key
+}
+
+// NewStore creates a store in the root of the given filesystem.
+func NewStore(fs afero.Fs) *Store {
+	return &Store{fs: fs}
+}
+
+// SetKey signs new payloads with the key so changes to them can be detected.
+func (s *Store) SetKey(key *integrity.Key) {
+	s.key = key
+}
+
+// Dir returns the directory holding a payload.
+func Dir(sha256Sum string) string {
+	return path.Join(sha256Sum[:2], sha256Sum)
+}
+
+// Create starts a new capture. The payload is added to the store and the
+// sighting recorded when the returned capture is closed.
+func (s *Store) Create(sighting Sighting) (*Capture, error) {
+	if err := s.fs.MkdirAll(tmpDirName, 0700); err != nil {
+		return nil, err
+	}
+	fd, err := afero.TempFile(s.fs, tmpDirName, "capture-")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Capture{File: fd, store: s, sighting: sighting}, nil
+}
+
+// commit moves a finished capture into the store, isNew is true if the
+// payload wasn't stored before.
+func (s *Store) commit(tmpName string, sighting Sighting) (info *Info, isNew bool, err error) {
+	info, err = s.hash(tmpName)
+	if err != nil {
+		s.fs.Remove(tmpName)
+		return nil, false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dir := Dir(info.SHA256)
+	switch _, err := s.fs.Stat(path.Join(dir, PayloadName)); {
+	case err == nil:
+		// Already stored, only the sighting is new.
+		if err := s.fs.Remove(tmpName); err != nil {
+			return nil, false, err
+		}
+	case errors.Is(err, fs.ErrNotExist):
+		isNew = true
+		if err := s.fs.MkdirAll(dir, 0700); err != nil {
+			return nil, false, err
+		}
+		if err := s.fs.Rename(tmpName, path.Join(dir, PayloadName)); err != nil {
+			return nil, false, err
+		}
+		if s.key != nil {
+			if err := integrity.SignFile(s.fs, path.Join(dir, PayloadName), s.key); err != nil {
+				return nil, false, err
+			}
+		}
+		if err := s.writeInfo(info); err != nil {
+			return nil, false, err
+		}
+	default:
+		return nil, false, err
+	}
+
+	sightingData, err := json.Marshal(sighting)
+	if err != nil {
+		return nil, false, err
+	}
+	fd, err := s.fs.OpenFile(path.Join(dir, SightingsName), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, false, err
+	}
+	defer fd.Close()
+	if _, err := fmt.Fprintln(fd, string(sightingData)); err != nil {
+		return nil, false, err
+	}
+
+	return info, isNew, nil
+}
+
+func (s *Store) writeInfo(info *Info) error {
+	infoData, err := json.MarshalIndent(info, "", "    ")
+	if err != nil {
+		return err
+	}
+	return afero.WriteFile(s.fs, path.Join(Dir(info.SHA256), InfoName), infoData, 0600)
+}
+
+// SetAnalysis saves the triage results of a stored payload.
+func (s *Store) SetAnalysis(sha256Sum string, analysis *triage.Analysis) error {
+	entry, err := s.Get(sha256Sum)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry.Info.Analysis = analysis
+	return s.writeInfo(entry.Info)
+}
+
+// hash computes the Info of a file.
+func (s *Store) hash(name string) (*Info, error) {
+	fd, err := s.fs.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+
+	info, err := HashReader(fd)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fuzzy hashes need the size up front so they take a second pass.
+	if _, err := fd.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if fuzzy, err := ssdeep.FuzzyReader(fd); err == nil {
+		info.SSDEEP = fuzzy
+	}
+
+	return info, nil
+}
+
+// HashReader computes the cryptographic hashes and size of the reader's
+// contents. The fuzzy hash isn't computed.
+func HashReader(r io.Reader) (*Info, error) {
+	sha256Hash := sha256.New()
+	md5Hash := md5.New()
+	sha1Hash := sha1.New()
+	size, err := io.Copy(io.MultiWriter(sha256Hash, md5Hash, sha1Hash), r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Info{
+		Hashes: Hashes{
+			SHA256: hex.EncodeToString(sha256Hash.Sum(nil)),
+			MD5:    hex.EncodeToString(md5Hash.Sum(nil)),
+			SHA1:   hex.EncodeToString(sha1Hash.Sum(nil)),
+		},
+		Size: size,
+	}, nil
+}
+
+// Find returns the SHA-256 of the stored payload matching the given hash or
+// SHA-256 prefix.
+func (s *Store) Find(hash string) (string, error) {
+	hash = strings.ToLower(hash)
+	if len(hash) < 2 {
+		return "", fmt.Errorf("hash %q is too short", hash)
+	}
+
+	entries, err := s.List()
+	if err != nil {
+		return "", err
+	}
+
+	var matches []string
+	for _, entry := range entries {
+		switch {
+		case entry.MD5 == hash, entry.SHA1 == hash, entry.SHA256 == hash:
+			return entry.SHA256, nil
+		case strings.HasPrefix(entry.SHA256, hash):
+			matches = append(matches, entry.SHA256)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("%s: %w", hash, ErrNotFound)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("%s: ambiguous, matches %d payloads", hash, len(matches))
+	}
+}
+
+// Get returns the stored payload with the given SHA-256.
+func (s *Store) Get(sha256Sum string) (*Entry, error) {
+	if len(sha256Sum) != sha256.Size*2 {
+		return nil, fmt.Errorf("%s: %w", sha256Sum, ErrNotFound)
+	}
+	dir := Dir(sha256Sum)
+
+	infoData, err := afero.ReadFile(s.fs, path.Join(dir, InfoName))
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil, fmt.Errorf("%s: %w", sha256Sum, ErrNotFound)
+	case err != nil:
+		return nil, err
+	}
+	entry := &Entry{Info: &Info{}}
+	if err := json.Unmarshal(infoData, entry.Info); err != nil {
+		return nil, fmt.Errorf("%s: %v", InfoName, err)
+	}
+
+	sightingsData, err := afero.ReadFile(s.fs, path.Join(dir, SightingsName))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(sightingsData))
+	for decoder.More() {
+		var sighting Sighting
+		if err := decoder.Decode(&sighting); err != nil {
+			return nil, fmt.Errorf("%s: %v", SightingsName, err)
+		}
+		entry.Sightings = append(entry.Sightings, sighting)
+	}
+	sort.SliceStable(entry.Sightings, func(i, j int) bool {
+		return entry.Sightings[i].Time.Before(entry.Sightings[j].Time)
+	})
+
+	return entry, nil
+}
+
+// List returns all stored payloads ordered by when they were first seen.
+func (s *Store) List() ([]*Entry, error) {
+	prefixes, err := afero.ReadDir(s.fs, ".")
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var out []*Entry
+	for _, prefix := range prefixes {
+		if !prefix.IsDir() || len(prefix.Name()) != 2 {
+			continue
+		}
+
+		dirs, err := afero.ReadDir(s.fs, prefix.Name())
+		if err != nil {
+			return nil, err
+		}
+		for _, dir := range dirs {
+			if !dir.IsDir() || !strings.HasPrefix(dir.Name(), prefix.Name()) {
+				continue
+			}
+			entry, err := s.Get(dir.Name())
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, entry)
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].FirstSeen().Before(out[j].FirstSeen())
+	})
+	return out, nil
+}
+
+// Open opens the contents of a stored payload.
+func (s *Store) Open(sha256Sum string) (afero.File, error) {
+	if len(sha256Sum) != sha256.Size*2 {
+		return nil, fmt.Errorf("%s: %w", sha256Sum, ErrNotFound)
+	}
+	return s.fs.Open(path.Join(Dir(sha256Sum), PayloadName))
+}
+
+// Capture is a payload being written to the store.
+type Capture struct {
+	afero.File
+
+	store    *Store
+	sighting Sighting
+
+	once  sync.Once
+	info  *Info
+	isNew bool
+	err   error
+}
+
+// Close finishes writing the payload and adds it to the store.
+func (c *Capture) Close() error {
+	c.once.Do(func() {
+		if err := c.File.Close(); err != nil {
+			c.err = err
+			c.store.fs.Remove(c.File.Name())
+			return
+		}
+		c.info, c.isNew, c.err = c.store.commit(c.File.Name(), c.sighting)
+	})
+	return c.err
+}
+
+// Abort discards the capture without adding it to the store.
+func (c *Capture) Abort() error {
+	c.once.Do(func() {
+		c.err = errors.New("capture aborted")
+		c.File.Close()
+		c.store.fs.Remove(c.File.Name())
+	})
+	return nil
+}
+
+// Info returns the stored payload's information, it's nil until the capture
+// is successfully closed.
+func (c *Capture) Info() *Info {
+	return c.info
+}
+
+// IsNew returns true if the closed capture added a payload that wasn't in
+// the store before.
+func (c *Capture) IsNew() bool {
+	return c.isNew
+}
@@ -0,0 +1,133 @@
+package payload
+
+import (
+	"bytes"
+	"errors"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/josephlewis42/honeyssh/core/integrity"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func capture(t *testing.T, store *Store, sighting Sighting, data []byte) *Info {
+	t.Helper()
+
+	fd, err := store.Create(sighting)
+	assert.NoError(t, err)
+	_, err = fd.Write(data)
+	assert.NoError(t, err)
+	assert.NoError(t, fd.Close())
+	// Closing twice is safe.
+	assert.NoError(t, fd.Close())
+
+	return fd.Info()
+}
+
+func TestStore(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	store := NewStore(fs)
+	start := time.Date(2006, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	miner := bytes.Repeat([]byte("miner"), 2000)
+	first := capture(t, store, Sighting{ID: "1", Time: start, Source: "http://example.com/a"}, miner)
+	second := capture(t, store, Sighting{ID: "2", Time: start.Add(time.Hour), Source: "http://example.com/b"}, miner)
+	other := capture(t, store, Sighting{ID: "3", Time: start.Add(time.Minute)}, []byte("hello"))
+
+	assert.Equal(t, first, second)
+	assert.Equal(t, int64(len(miner)), first.Size)
+	assert.NotEmpty(t, first.SSDEEP)
+	assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", other.SHA256)
+	assert.Equal(t, "5d41402abc4b2a76b9719d911017c592", other.MD5)
+	assert.Equal(t, "aaf4c61ddcc5e8a2dabede0f3b482cd9aea9434d", other.SHA1)
+	assert.Empty(t, other.SSDEEP, "too small to fuzzy hash")
+
+	// The payload is only stored once.
+	exists, err := afero.Exists(fs, "2c/2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824/payload")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	tmpFiles, err := afero.ReadDir(fs, tmpDirName)
+	assert.NoError(t, err)
+	assert.Empty(t, tmpFiles)
+
+	entries, err := store.List()
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, first.SHA256, entries[0].SHA256)
+	assert.Len(t, entries[0].Sightings, 2)
+	assert.Equal(t, start, entries[0].FirstSeen())
+	assert.Equal(t, start.Add(time.Hour), entries[0].LastSeen())
+
+	fd, err := store.Open(other.SHA256)
+	assert.NoError(t, err)
+	contents, err := afero.ReadAll(fd)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(contents))
+}
+
+func TestStore_Find(t *testing.T) {
+	store := NewStore(afero.NewMemMapFs())
+	info := capture(t, store, Sighting{ID: "1"}, []byte("hello"))
+
+	cases := map[string]struct {
+		hash    string
+		wantErr bool
+	}{
+		"sha256": {hash: info.SHA256},
+		"md5":    {hash: info.MD5},
+		"sha1":   {hash: info.SHA1},
+		"prefix": {hash: "2CF24D"},
+		"short":  {hash: "2", wantErr: true},
+		"none":   {hash: "ffff", wantErr: true},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			got, err := store.Find(tc.hash)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, info.SHA256, got)
+		})
+	}
+
+	_, err := store.Get("ffff")
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestCapture_Abort(t *testing.T) {
+	store := NewStore(afero.NewMemMapFs())
+
+	fd, err := store.Create(Sighting{ID: "1"})
+	assert.NoError(t, err)
+	_, err = fd.Write([]byte("hello"))
+	assert.NoError(t, err)
+	assert.NoError(t, fd.Abort())
+	assert.Error(t, fd.Close())
+	assert.Nil(t, fd.Info())
+
+	entries, err := store.List()
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestStore_SetKey(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	store := NewStore(fs)
+	key, err := integrity.NewKey(integrity.MethodEd25519, make([]byte, integrity.SeedSize))
+	assert.NoError(t, err)
+	store.SetKey(key)
+
+	info := capture(t, store, Sighting{ID: "1"}, []byte("hello"))
+	name := path.Join(Dir(info.SHA256), PayloadName)
+	assert.NoError(t, integrity.VerifyFile(fs, name, key))
+
+	// Signing doesn't affect listing.
+	entries, err := store.List()
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
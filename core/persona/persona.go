@@ -0,0 +1,318 @@
+// Package persona describes the machine the honeypot pretends to be.
+//
+// A persona bundles the distribution, kernel, hardware, network interfaces,
+// processes and packages of a host so every command and /proc file that
+// reveals them tells the same story.
+package persona
+
+import (
+	"embed"
+	"fmt"
+	"net"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"sigs.k8s.io/yaml"
+)
+
+// Default is the name of the persona used if none is configured.
+const Default = "ubuntu-vps"
+
+// Package managers.
+const (
+	PackageManagerDpkg = "dpkg"
+	PackageManagerRPM  = "rpm"
+	PackageManagerOpkg = "opkg"
+)
+
+//go:embed builtin/*.yaml
+var builtinData embed.FS
+
+// Persona is a consistent description of a host.
+type Persona struct {
+	// Name of the persona.
+	Name string `json:"name" validate:"required"`
+	// Description of the machine the persona imitates.
+	Description string `json:"description"`
+	// Userland is "gnu" for GNU coreutils and procps or "busybox", it changes
+	// the output format of some commands.
+	Userland string `json:"userland" validate:"omitempty,oneof=gnu busybox"`
+
+	Distro     Distro      `json:"distro"`
+	Kernel     Kernel      `json:"kernel"`
+	CPU        CPU         `json:"cpu"`
+	Memory     Memory      `json:"memory"`
+	Disks      []Disk      `json:"disks" validate:"dive"`
+	Interfaces []Interface `json:"interfaces" validate:"unique=Name,dive"`
+	// Gateway is the IPv4 address of the default route.
+	Gateway string `json:"gateway" validate:"omitempty,ipv4"`
+	// PCIDevices are lines in the format of lspci.
+	PCIDevices []string `json:"pci_devices"`
+	// USBDevices are lines in the format of lsusb.
+	USBDevices []string  `json:"usb_devices"`
+	Processes  []Process `json:"processes" validate:"unique=PID,dive"`
+	Packages   []Package `json:"packages" validate:"dive"`
+	// Files are extra files laid over the root filesystem keyed by absolute
+	// path e.g. /etc/debian_version.
+	Files map[string]string `json:"files"`
+}
+
+// Distro identifies the distribution in the format of /etc/os-release.
+type Distro struct {
+	// ID e.g. "ubuntu".
+	ID string `json:"id" validate:"required"`
+	// IDLike lists related distributions e.g. "debian".
+	IDLike string `json:"id_like"`
+	// Name e.g. "Ubuntu".
+	Name string `json:"name" validate:"required"`
+	// Version e.g. "22.04.4 LTS (Jammy Jellyfish)".
+	Version string `json:"version"`
+	// VersionID e.g. "22.04".
+	VersionID string `json:"version_id"`
+	// VersionCodename e.g. "jammy".
+	VersionCodename string `json:"version_codename"`
+	// PrettyName e.g. "Ubuntu 22.04.4 LTS".
+	PrettyName string `json:"pretty_name" validate:"required"`
+	// HomeURL of the distribution.
+	HomeURL string `json:"home_url"`
+	// PackageManager is one of "dpkg", "rpm" or "opkg".
+	PackageManager string `json:"package_manager" validate:"omitempty,oneof=dpkg rpm opkg"`
+}
+
+// Kernel is the running kernel, it provides the defaults for uname.
+type Kernel struct {
+	// Name e.g. "Linux".
+	Name string `json:"name" validate:"required"`
+	// Release e.g. "5.15.0-105-generic".
+	Release string `json:"release" validate:"required"`
+	// Version e.g. "#115-Ubuntu SMP Mon Apr 15 09:52:04 UTC 2024".
+	Version string `json:"version" validate:"required"`
+	// Machine is the hardware platform e.g. "x86_64".
+	Machine string `json:"machine" validate:"required"`
+}
+
+// CPU describes the processor. Some fields are only shown on some
+// architectures.
+type CPU struct {
+	// ModelName e.g. "Intel(R) Xeon(R) CPU @ 2.20GHz".
+	ModelName string `json:"model_name" validate:"required"`
+	// Vendor e.g. "GenuineIntel" or "ARM".
+	Vendor string `json:"vendor"`
+	// Cores is the number of online CPUs.
+	Cores int `json:"cores" validate:"gte=1"`
+	// Family, Model and Stepping are the cpu family, model and stepping on x86
+	// and the CPU architecture, part and revision on ARM.
+	Family   int `json:"family"`
+	Model    int `json:"model"`
+	Stepping int `json:"stepping"`
+	// MHz is the clock speed.
+	MHz float64 `json:"mhz" validate:"gte=0"`
+	// BogoMIPS reported by the kernel.
+	BogoMIPS float64 `json:"bogomips" validate:"gte=0"`
+	// CacheSizeKB is the size of the last level cache.
+	CacheSizeKB int `json:"cache_size_kb" validate:"gte=0"`
+	// Flags are the CPU features, ASEs on MIPS.
+	Flags []string `json:"flags"`
+	// BigEndian is true if the CPU runs big-endian.
+	BigEndian bool `json:"big_endian"`
+	// Hypervisor vendor e.g. "KVM", empty for physical machines.
+	Hypervisor string `json:"hypervisor"`
+	// Hardware is the SoC of embedded boards e.g. "BCM2835".
+	Hardware string `json:"hardware"`
+	// Board is the model of embedded boards e.g. "Raspberry Pi 4 Model B Rev 1.4".
+	Board string `json:"board"`
+}
+
+// Memory sizes are in KiB like /proc/meminfo.
+type Memory struct {
+	TotalKB     int64 `json:"total_kb" validate:"gt=0"`
+	FreeKB      int64 `json:"free_kb" validate:"gte=0"`
+	AvailableKB int64 `json:"available_kb" validate:"gte=0"`
+	BuffersKB   int64 `json:"buffers_kb" validate:"gte=0"`
+	CachedKB    int64 `json:"cached_kb" validate:"gte=0"`
+	SharedKB    int64 `json:"shared_kb" validate:"gte=0"`
+	SwapTotalKB int64 `json:"swap_total_kb" validate:"gte=0"`
+	SwapFreeKB  int64 `json:"swap_free_kb" validate:"gte=0"`
+}
+
+// UsedKB returns the memory that isn't free or used for buffers and caches.
+func (m *Memory) UsedKB() int64 {
+	return m.TotalKB - m.FreeKB - m.BuffersKB - m.CachedKB
+}
+
+// SwapUsedKB returns the swap in use.
+func (m *Memory) SwapUsedKB() int64 {
+	return m.SwapTotalKB - m.SwapFreeKB
+}
+
+// Disk is a mounted filesystem, sizes are in KiB.
+type Disk struct {
+	// Device e.g. "/dev/sda1".
+	Device string `json:"device" validate:"required"`
+	// MountPoint e.g. "/".
+	MountPoint string `json:"mount_point" validate:"required,startswith=/"`
+	// Type of the filesystem e.g. "ext4".
+	Type string `json:"type" validate:"required"`
+	// Options the filesystem is mounted with, "rw,relatime" if empty.
+	Options string `json:"options"`
+	SizeKB  int64  `json:"size_kb" validate:"gte=0"`
+	UsedKB  int64  `json:"used_kb" validate:"gte=0,ltefield=SizeKB"`
+}
+
+// AvailableKB returns the free space on the disk.
+func (d *Disk) AvailableKB() int64 {
+	return d.SizeKB - d.UsedKB
+}
+
+// Interface is a network interface other than loopback, which every persona
+// has.
+type Interface struct {
+	// Name e.g. "ens4".
+	Name string `json:"name" validate:"required"`
+	// MAC address.
+	MAC string `json:"mac" validate:"required,mac"`
+	// Addresses in CIDR notation e.g. "10.128.0.2/32".
+	Addresses []string `json:"addresses" validate:"dive,cidr"`
+	// MTU of the interface, 1500 if empty.
+	MTU int `json:"mtu" validate:"gte=0"`
+	// Traffic counters.
+	RXPackets int64 `json:"rx_packets" validate:"gte=0"`
+	RXBytes   int64 `json:"rx_bytes" validate:"gte=0"`
+	TXPackets int64 `json:"tx_packets" validate:"gte=0"`
+	TXBytes   int64 `json:"tx_bytes" validate:"gte=0"`
+}
+
+// IPNets returns the parsed addresses of the interface.
+func (i *Interface) IPNets() []*net.IPNet {
+	var out []*net.IPNet
+	for _, address := range i.Addresses {
+		ip, ipNet, err := net.ParseCIDR(address)
+		if err != nil {
+			continue
+		}
+		ipNet.IP = ip
+		out = append(out, ipNet)
+	}
+	return out
+}
+
+// GetMTU returns the MTU of the interface.
+func (i *Interface) GetMTU() int {
+	if i.MTU == 0 {
+		return 1500
+	}
+	return i.MTU
+}
+
+// Process is a system process that's always running.
+type Process struct {
+	// User the process runs as.
+	User string `json:"user" validate:"required"`
+	PID  int    `json:"pid" validate:"gte=1"`
+	// Command line, kernel threads are in brackets e.g. "[kthreadd]".
+	Command string `json:"command" validate:"required"`
+	// TTY of the process, "?" if empty.
+	TTY string `json:"tty"`
+	// Stat is the process state e.g. "Ss", "S" if empty.
+	Stat string `json:"stat"`
+	// CPU and Mem are the percentages the process appears to use.
+	CPU float64 `json:"cpu" validate:"gte=0,lte=100"`
+	Mem float64 `json:"mem" validate:"gte=0,lte=100"`
+	// VSZ and RSS are the virtual and resident memory in KiB.
+	VSZ int64 `json:"vsz" validate:"gte=0"`
+	RSS int64 `json:"rss" validate:"gte=0"`
+}
+
+// Package is an installed package.
+type Package struct {
+	Name    string `json:"name" validate:"required"`
+	Version string `json:"version" validate:"required"`
+	// Arch e.g. "amd64", "x86_64" or "noarch".
+	Arch        string `json:"arch"`
+	Description string `json:"description"`
+}
+
+// Parse reads a persona from YAML and validates it.
+func Parse(data []byte) (*Persona, error) {
+	var out Persona
+	if err := yaml.UnmarshalStrict(data, &out); err != nil {
+		return nil, err
+	}
+	if err := out.Validate(); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Validate the persona for basic semantic errors.
+func (p *Persona) Validate() error {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		return name
+	})
+
+	if err := validate.Struct(p); err != nil {
+		return err
+	}
+	for name := range p.Files {
+		if !path.IsAbs(name) {
+			return fmt.Errorf("file path must be absolute: %q", name)
+		}
+	}
+	return nil
+}
+
+// IsBusyBox returns true if the persona's commands come from BusyBox.
+func (p *Persona) IsBusyBox() bool {
+	return p.Userland == "busybox"
+}
+
+// Builtin returns the bundled persona with the given name.
+func Builtin(name string) (*Persona, error) {
+	data, err := BuiltinYAML(name)
+	if err != nil {
+		return nil, err
+	}
+	persona, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("persona %q: %v", name, err)
+	}
+	return persona, nil
+}
+
+// MustBuiltin is like Builtin, but panics if the persona doesn't exist.
+func MustBuiltin(name string) *Persona {
+	persona, err := Builtin(name)
+	if err != nil {
+		panic(err)
+	}
+	return persona
+}
+
+// BuiltinNames returns the names of the bundled personas in sorted order.
+func BuiltinNames() []string {
+	entries, err := builtinData.ReadDir("builtin")
+	if err != nil {
+		panic(err)
+	}
+	var out []string
+	for _, entry := range entries {
+		out = append(out, strings.TrimSuffix(entry.Name(), ".yaml"))
+	}
+	sort.Strings(out)
+	return out
+}
+
+// BuiltinYAML returns the source of the bundled persona with the given name,
+// e.g. to start a custom persona from.
+func BuiltinYAML(name string) ([]byte, error) {
+	data, err := builtinData.ReadFile(path.Join("builtin", name+".yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("unknown persona %q, use one of: %s", name, strings.Join(BuiltinNames(), ", "))
+	}
+	return data, nil
+}
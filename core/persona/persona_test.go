@@ -0,0 +1,159 @@
+package persona
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuiltin(t *testing.T) {
+	assert.Equal(t, []string{"centos", "mips-router", "raspberry-pi", "ubuntu-vps"}, BuiltinNames())
+	assert.Contains(t, BuiltinNames(), Default)
+
+	for _, name := range BuiltinNames() {
+		t.Run(name, func(t *testing.T) {
+			persona, err := Builtin(name)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, name, persona.Name)
+
+			// The pieces of a persona shouldn't contradict each other.
+			mem := persona.Memory
+			assert.Positive(t, mem.UsedKB(), "memory used")
+			assert.LessOrEqual(t, mem.AvailableKB, mem.TotalKB, "memory available")
+			assert.LessOrEqual(t, mem.SwapFreeKB, mem.SwapTotalKB, "swap free")
+
+			assert.True(t, sort.SliceIsSorted(persona.Processes, func(i, j int) bool {
+				return persona.Processes[i].PID < persona.Processes[j].PID
+			}), "processes sorted by PID")
+			assert.Equal(t, 1, persona.Processes[0].PID)
+
+			var rootMounted bool
+			for _, disk := range persona.Disks {
+				rootMounted = rootMounted || disk.MountPoint == "/"
+			}
+			assert.True(t, rootMounted, "root filesystem mounted")
+
+			assert.NotEmpty(t, persona.Packages)
+			assert.NotEmpty(t, persona.Distro.PackageManager)
+
+			assert.Equal(t, persona.CPU.Cores, strings.Count(persona.CPUInfo(), "processor\t"))
+		})
+	}
+}
+
+func TestBuiltin_Unknown(t *testing.T) {
+	_, err := Builtin("vax")
+	assert.ErrorContains(t, err, `unknown persona "vax", use one of: centos, mips-router`)
+}
+
+func TestParse(t *testing.T) {
+	cases := map[string]struct {
+		yaml    string
+		wantErr string
+	}{
+		"minimal": {
+			yaml: `
+name: minimal
+distro: {id: debian, name: Debian, pretty_name: Debian}
+kernel: {name: Linux, release: "6.1.0", version: "#1 SMP", machine: x86_64}
+cpu: {model_name: "QEMU Virtual CPU", cores: 1}
+memory: {total_kb: 1024}
+`,
+		},
+		"unknown field": {
+			yaml:    "name: x\nkernal: {}\n",
+			wantErr: `unknown field "kernal"`,
+		},
+		"missing kernel": {
+			yaml: `
+name: x
+distro: {id: debian, name: Debian, pretty_name: Debian}
+cpu: {model_name: "QEMU Virtual CPU", cores: 1}
+memory: {total_kb: 1024}
+`,
+			wantErr: "Persona.kernel.release",
+		},
+		"bad address": {
+			yaml: `
+name: x
+distro: {id: debian, name: Debian, pretty_name: Debian}
+kernel: {name: Linux, release: "6.1.0", version: "#1 SMP", machine: x86_64}
+cpu: {model_name: "QEMU Virtual CPU", cores: 1}
+memory: {total_kb: 1024}
+interfaces:
+- {name: eth0, mac: "52:54:00:12:34:56", addresses: ["10.0.0.300/24"]}
+`,
+			wantErr: "Persona.interfaces[0].addresses[0]",
+		},
+		"relative file": {
+			yaml: `
+name: x
+distro: {id: debian, name: Debian, pretty_name: Debian}
+kernel: {name: Linux, release: "6.1.0", version: "#1 SMP", machine: x86_64}
+cpu: {model_name: "QEMU Virtual CPU", cores: 1}
+memory: {total_kb: 1024}
+files:
+  etc/motd: hi
+`,
+			wantErr: `file path must be absolute: "etc/motd"`,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			_, err := Parse([]byte(tc.yaml))
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestPersona_OSRelease(t *testing.T) {
+	want := `PRETTY_NAME="CentOS Linux 7 (Core)"
+NAME="CentOS Linux"
+VERSION_ID="7"
+VERSION="7 (Core)"
+ID=centos
+ID_LIKE="rhel fedora"
+HOME_URL="https://www.centos.org/"
+`
+	assert.Equal(t, want, MustBuiltin("centos").OSRelease())
+}
+
+func TestPersona_CPUInfo(t *testing.T) {
+	cases := map[string][]string{
+		"ubuntu-vps":   {"vendor_id\t: GenuineIntel\n", "model name\t: Intel(R) Xeon(R) CPU @ 2.20GHz\n", "siblings\t: 2\n"},
+		"raspberry-pi": {"CPU implementer\t: 0x41\n", "CPU part\t: 0xd08\n", "Model\t\t: Raspberry Pi 4 Model B Rev 1.4\n"},
+		"mips-router":  {"system type\t\t: Qualcomm Atheros QCA956X ver 1 rev 0\n", "cpu model\t\t: MIPS 74Kc V5.0\n", "ASEs implemented\t: mips16 dsp dsp2\n"},
+	}
+
+	for name, wantLines := range cases {
+		t.Run(name, func(t *testing.T) {
+			cpuinfo := MustBuiltin(name).CPUInfo()
+			for _, line := range wantLines {
+				assert.Contains(t, cpuinfo, line)
+			}
+		})
+	}
+}
+
+func TestPersona_MemInfo(t *testing.T) {
+	meminfo := MustBuiltin("mips-router").MemInfo()
+	assert.True(t, strings.HasPrefix(meminfo, "MemTotal:         121460 kB\nMemFree:           70128 kB\n"), meminfo)
+}
+
+func TestPersona_Mounts(t *testing.T) {
+	want := `/dev/mapper/centos-root / xfs rw,relatime,attr2,inode64,noquota 0 0
+/dev/vda1 /boot xfs rw,relatime,attr2,inode64,noquota 0 0
+proc /proc proc rw,nosuid,nodev,noexec,relatime 0 0
+sysfs /sys sysfs rw,nosuid,nodev,noexec,relatime 0 0
+`
+	assert.Equal(t, want, MustBuiltin("centos").Mounts())
+}
@@ -0,0 +1,210 @@
+package persona
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Architecture families, /proc/cpuinfo and lscpu differ between them.
+const (
+	ArchX86  = "x86"
+	ArchARM  = "arm"
+	ArchMIPS = "mips"
+)
+
+// Arch returns the architecture family of the kernel's machine.
+func (p *Persona) Arch() string {
+	machine := p.Kernel.Machine
+	switch {
+	case strings.HasPrefix(machine, "arm"), strings.HasPrefix(machine, "aarch64"):
+		return ArchARM
+	case strings.HasPrefix(machine, "mips"):
+		return ArchMIPS
+	default:
+		return ArchX86
+	}
+}
+
+// Is64Bit returns true if the kernel's machine is a 64-bit architecture.
+func (p *Persona) Is64Bit() bool {
+	return strings.HasSuffix(p.Kernel.Machine, "64")
+}
+
+// ByteOrder returns the byte order of the CPU as lscpu shows it.
+func (p *Persona) ByteOrder() string {
+	if p.CPU.BigEndian {
+		return "Big Endian"
+	}
+	return "Little Endian"
+}
+
+// armImplementers maps vendors to the CPU implementer codes of ARM CPUs.
+var armImplementers = map[string]int{
+	"ARM":       0x41,
+	"Broadcom":  0x42,
+	"Cavium":    0x43,
+	"Qualcomm":  0x51,
+	"Marvell":   0x56,
+	"Apple":     0x61,
+	"Ampere":    0xc0,
+	"Fujitsu":   0x46,
+	"HiSilicon": 0x48,
+}
+
+// OSRelease returns the contents of /etc/os-release.
+func (p *Persona) OSRelease() string {
+	var sb strings.Builder
+	for _, field := range []struct {
+		key    string
+		value  string
+		quoted bool
+	}{
+		{"PRETTY_NAME", p.Distro.PrettyName, true},
+		{"NAME", p.Distro.Name, true},
+		{"VERSION_ID", p.Distro.VersionID, true},
+		{"VERSION", p.Distro.Version, true},
+		{"VERSION_CODENAME", p.Distro.VersionCodename, false},
+		{"ID", p.Distro.ID, false},
+		{"ID_LIKE", p.Distro.IDLike, false},
+		{"HOME_URL", p.Distro.HomeURL, true},
+	} {
+		switch {
+		case field.value == "":
+			continue
+		case field.quoted || strings.Contains(field.value, " "):
+			fmt.Fprintf(&sb, "%s=%q\n", field.key, field.value)
+		default:
+			fmt.Fprintf(&sb, "%s=%s\n", field.key, field.value)
+		}
+	}
+	return sb.String()
+}
+
+// CPUInfo returns the contents of /proc/cpuinfo.
+func (p *Persona) CPUInfo() string {
+	var sb strings.Builder
+	cpu := p.CPU
+	switch p.Arch() {
+	case ArchARM:
+		implementer, ok := armImplementers[cpu.Vendor]
+		if !ok {
+			implementer = armImplementers["ARM"]
+		}
+		for i := 0; i < cpu.Cores; i++ {
+			fmt.Fprintf(&sb, "processor\t: %d\n", i)
+			// Only 32-bit kernels show the model name.
+			if !p.Is64Bit() {
+				fmt.Fprintf(&sb, "model name\t: %s\n", cpu.ModelName)
+			}
+			fmt.Fprintf(&sb, "BogoMIPS\t: %.2f\n", cpu.BogoMIPS)
+			fmt.Fprintf(&sb, "Features\t: %s\n", strings.Join(cpu.Flags, " "))
+			fmt.Fprintf(&sb, "CPU implementer\t: 0x%02x\n", implementer)
+			fmt.Fprintf(&sb, "CPU architecture: %d\n", cpu.Family)
+			fmt.Fprintf(&sb, "CPU variant\t: 0x0\n")
+			fmt.Fprintf(&sb, "CPU part\t: 0x%03x\n", cpu.Model)
+			fmt.Fprintf(&sb, "CPU revision\t: %d\n", cpu.Stepping)
+			sb.WriteString("\n")
+		}
+		if cpu.Hardware != "" {
+			fmt.Fprintf(&sb, "Hardware\t: %s\n", cpu.Hardware)
+		}
+		if cpu.Board != "" {
+			fmt.Fprintf(&sb, "Model\t\t: %s\n", cpu.Board)
+		}
+
+	case ArchMIPS:
+		if cpu.Hardware != "" {
+			fmt.Fprintf(&sb, "system type\t\t: %s\n", cpu.Hardware)
+		}
+		if cpu.Board != "" {
+			fmt.Fprintf(&sb, "machine\t\t\t: %s\n", cpu.Board)
+		}
+		for i := 0; i < cpu.Cores; i++ {
+			fmt.Fprintf(&sb, "processor\t\t: %d\n", i)
+			fmt.Fprintf(&sb, "cpu model\t\t: %s\n", cpu.ModelName)
+			fmt.Fprintf(&sb, "BogoMIPS\t\t: %.2f\n", cpu.BogoMIPS)
+			sb.WriteString("wait instruction\t: yes\n")
+			sb.WriteString("microsecond timers\t: yes\n")
+			sb.WriteString("tlb_entries\t\t: 32\n")
+			sb.WriteString("extra interrupt vector\t: yes\n")
+			sb.WriteString("hardware watchpoint\t: yes, count: 4, address/irw mask: [0x0ffc, 0x0ffc, 0x0ffb, 0x0ffb]\n")
+			sb.WriteString("isa\t\t\t: mips1 mips2 mips32r1 mips32r2\n")
+			fmt.Fprintf(&sb, "ASEs implemented\t: %s\n", strings.Join(cpu.Flags, " "))
+			sb.WriteString("shadow register sets\t: 1\n")
+			sb.WriteString("kscratch registers\t: 0\n")
+			sb.WriteString("package\t\t\t: 0\n")
+			fmt.Fprintf(&sb, "core\t\t\t: %d\n", i)
+			sb.WriteString("VCED exceptions\t\t: not available\n")
+			sb.WriteString("VCEI exceptions\t\t: not available\n")
+			sb.WriteString("\n")
+		}
+
+	default:
+		for i := 0; i < cpu.Cores; i++ {
+			fmt.Fprintf(&sb, "processor\t: %d\n", i)
+			fmt.Fprintf(&sb, "vendor_id\t: %s\n", cpu.Vendor)
+			fmt.Fprintf(&sb, "cpu family\t: %d\n", cpu.Family)
+			fmt.Fprintf(&sb, "model\t\t: %d\n", cpu.Model)
+			fmt.Fprintf(&sb, "model name\t: %s\n", cpu.ModelName)
+			fmt.Fprintf(&sb, "stepping\t: %d\n", cpu.Stepping)
+			fmt.Fprintf(&sb, "cpu MHz\t\t: %.3f\n", cpu.MHz)
+			fmt.Fprintf(&sb, "cache size\t: %d KB\n", cpu.CacheSizeKB)
+			fmt.Fprintf(&sb, "physical id\t: 0\n")
+			fmt.Fprintf(&sb, "siblings\t: %d\n", cpu.Cores)
+			fmt.Fprintf(&sb, "core id\t\t: %d\n", i)
+			fmt.Fprintf(&sb, "cpu cores\t: %d\n", cpu.Cores)
+			fmt.Fprintf(&sb, "apicid\t\t: %d\n", i)
+			fmt.Fprintf(&sb, "initial apicid\t: %d\n", i)
+			sb.WriteString("fpu\t\t: yes\n")
+			sb.WriteString("fpu_exception\t: yes\n")
+			sb.WriteString("cpuid level\t: 13\n")
+			sb.WriteString("wp\t\t: yes\n")
+			fmt.Fprintf(&sb, "flags\t\t: %s\n", strings.Join(cpu.Flags, " "))
+			fmt.Fprintf(&sb, "bogomips\t: %.2f\n", cpu.BogoMIPS)
+			sb.WriteString("clflush size\t: 64\n")
+			sb.WriteString("cache_alignment\t: 64\n")
+			sb.WriteString("address sizes\t: 46 bits physical, 48 bits virtual\n")
+			sb.WriteString("power management:\n")
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// MemInfo returns the contents of /proc/meminfo.
+func (p *Persona) MemInfo() string {
+	var sb strings.Builder
+	mem := p.Memory
+	for _, field := range []struct {
+		key   string
+		value int64
+	}{
+		{"MemTotal", mem.TotalKB},
+		{"MemFree", mem.FreeKB},
+		{"MemAvailable", mem.AvailableKB},
+		{"Buffers", mem.BuffersKB},
+		{"Cached", mem.CachedKB},
+		{"SwapCached", 0},
+		{"SwapTotal", mem.SwapTotalKB},
+		{"SwapFree", mem.SwapFreeKB},
+		{"Shmem", mem.SharedKB},
+	} {
+		fmt.Fprintf(&sb, "%-16s%8d kB\n", field.key+":", field.value)
+	}
+	return sb.String()
+}
+
+// Mounts returns the contents of /proc/mounts.
+func (p *Persona) Mounts() string {
+	var sb strings.Builder
+	for _, disk := range p.Disks {
+		options := disk.Options
+		if options == "" {
+			options = "rw,relatime"
+		}
+		fmt.Fprintf(&sb, "%s %s %s %s 0 0\n", disk.Device, disk.MountPoint, disk.Type, options)
+	}
+	sb.WriteString("proc /proc proc rw,nosuid,nodev,noexec,relatime 0 0\n")
+	sb.WriteString("sysfs /sys sysfs rw,nosuid,nodev,noexec,relatime 0 0\n")
+	return sb.String()
+}
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"log"
+	"reflect"
+
+	"github.com/josephlewis42/honeyssh/core/classify"
+	"github.com/josephlewis42/honeyssh/core/config"
+	"github.com/josephlewis42/honeyssh/core/logger"
+	"github.com/josephlewis42/honeyssh/core/triage"
+)
+
+// restartOnlySettings are read when the honeypot starts, e.g. to open
+// listeners or build the filesystem, so reloading can't change them.
+var restartOnlySettings = []struct {
+	name  string
+	field func(*config.Configuration) any
+}{
+	{"ssh_port", func(c *config.Configuration) any { return &c.SSHPort }},
+	{"log_path", func(c *config.Configuration) any { return &c.LogPaht }},
+	{"metrics_listen_addr", func(c *config.Configuration) any { return &c.MetricsListenAddr }},
+	{"harvest", func(c *config.Configuration) any { return &c.Harvest }},
+	{"network", func(c *config.Configuration) any { return &c.Network }},
+	{"monitor", func(c *config.Configuration) any { return &c.Monitor }},
+	{"integrity", func(c *config.Configuration) any { return &c.Integrity }},
+	{"honeytokens", func(c *config.Configuration) any { return &c.Honeytokens }},
+}
+
+// Reload reads config.yaml and the classification and triage rules again and
+// uses them for new connections, sessions that are already running keep the
+// configuration they started with. If the new configuration or rules can't be
+// loaded or aren't valid the current ones are kept.
+func (h *Honeypot) Reload() error {
+	current := h.sharedOS.Config()
+	next, err := current.Reload()
+
+	// Rules files live next to config.yaml and are reloaded with it.
+	var classifier *classify.Classifier
+	var analyzer *triage.Analyzer
+	if err == nil {
+		classifier, err = newClassifier(next)
+	}
+	if err == nil {
+		analyzer, err = newAnalyzer(next)
+	}
+	if err != nil {
+		log.Printf("Couldn't reload configuration, keeping the current one: %v\n", err)
+		h.logger.Sessionless().Record(&logger.LogEntry_HoneypotEvent{
+			HoneypotEvent: &logger.HoneypotEvent{
+				EventType: logger.HoneypotEvent_RELOAD_FAILED,
+				Error:     err.Error(),
+			},
+		})
+		return err
+	}
+
+	restartRequired := keepRestartOnlySettings(current, next)
+	h.sharedOS.SetConfig(next)
+	h.sharedOS.SetClassifier(classifier)
+	h.sharedOS.SetAnalyzer(analyzer)
+	h.monitor.SetHostname(next.Uname.Nodename)
+
+	log.Println("Reloaded configuration")
+	for _, name := range restartRequired {
+		log.Printf("- %s changed, restart to apply it\n", name)
+	}
+	h.logger.Sessionless().Record(&logger.LogEntry_HoneypotEvent{
+		HoneypotEvent: &logger.HoneypotEvent{
+			EventType:       logger.HoneypotEvent_RELOAD,
+			RestartRequired: restartRequired,
+		},
+	})
+	return nil
+}
+
+// keepRestartOnlySettings copies the settings reloading can't change from
+// current to next and returns the names of the ones that differed.
+func keepRestartOnlySettings(current, next *config.Configuration) []string {
+	var changed []string
+	for _, setting := range restartOnlySettings {
+		currentValue := reflect.ValueOf(setting.field(current)).Elem()
+		nextValue := reflect.ValueOf(setting.field(next)).Elem()
+		if !reflect.DeepEqual(currentValue.Interface(), nextValue.Interface()) {
+			changed = append(changed, setting.name)
+			nextValue.Set(currentValue)
+		}
+	}
+	return changed
+}
@@ -0,0 +1,172 @@
+package triage
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"sigs.k8s.io/yaml"
+)
+
+//go:embed rules.yaml
+var builtinRulesData []byte
+
+// Rule is a YARA-like rule matched against a payload's contents.
+type Rule struct {
+	// Name of the rule.
+	Name string `json:"name" validate:"required"`
+	// Description of what the rule detects.
+	Description string `json:"description,omitempty"`
+	// Tags to apply to matching payloads e.g. "miner".
+	Tags []string `json:"tags,omitempty"`
+	// Strings to search for in the payload.
+	Strings []RuleString `json:"strings" validate:"required,min=1,dive"`
+	// Condition is one of "any of them", "all of them" or "N of them", it
+	// defaults to "any of them".
+	Condition string `json:"condition,omitempty"`
+}
+
+// RuleString is a single pattern in a rule, exactly one of Text, Hex or
+// Regex must be set. Text and Hex match anywhere in the payload while Regex
+// only matches text or the printable strings of binaries to keep scanning
+// large payloads fast.
+type RuleString struct {
+	// Text to search for.
+	Text string `json:"text,omitempty" validate:"required_without_all=Hex Regex,excluded_with=Hex Regex"`
+	// NoCase makes Text match case insensitively.
+	NoCase bool `json:"nocase,omitempty"`
+	// Hex encoded bytes to search for, whitespace is ignored.
+	Hex string `json:"hex,omitempty" validate:"required_without_all=Text Regex,excluded_with=Text Regex"`
+	// Regex is a regular expression to search for.
+	Regex string `json:"regex,omitempty" validate:"required_without_all=Text Hex,excluded_with=Text Hex"`
+}
+
+// RuleMatch is a rule that matched a payload.
+type RuleMatch struct {
+	Rule string   `json:"rule"`
+	Tags []string `json:"tags,omitempty"`
+}
+
+// ParseRules parses a YAML list of rules.
+func ParseRules(data []byte) ([]Rule, error) {
+	var out []Rule
+	if err := yaml.UnmarshalStrict(data, &out); err != nil {
+		return nil, err
+	}
+
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		return strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+	})
+	for i := range out {
+		if err := validate.Struct(&out[i]); err != nil {
+			return nil, fmt.Errorf("rule %d: %v", i, err)
+		}
+	}
+
+	return out, nil
+}
+
+// BuiltinRules returns the rules that ship with the honeypot.
+func BuiltinRules() []Rule {
+	rules, err := ParseRules(builtinRulesData)
+	if err != nil {
+		panic(err)
+	}
+	return rules
+}
+
+// scanInput holds the views of a payload that rules match against.
+type scanInput struct {
+	data []byte
+	// strings holds data if it's text, otherwise the printable strings in
+	// data separated by newlines.
+	strings []byte
+
+	lower []byte
+}
+
+// lowerData returns data in lower case, it's computed on first use.
+func (s *scanInput) lowerData() []byte {
+	if s.lower == nil {
+		s.lower = bytes.ToLower(s.data)
+	}
+	return s.lower
+}
+
+type compiledRule struct {
+	Rule
+	matchers []func(*scanInput) bool
+	// required is the number of matchers that must match.
+	required int
+}
+
+func compileRule(rule Rule) (*compiledRule, error) {
+	out := &compiledRule{Rule: rule}
+
+	for i, s := range rule.Strings {
+		switch {
+		case s.Text != "" && s.NoCase:
+			text := bytes.ToLower([]byte(s.Text))
+			out.matchers = append(out.matchers, func(in *scanInput) bool {
+				return bytes.Contains(in.lowerData(), text)
+			})
+		case s.Text != "":
+			text := []byte(s.Text)
+			out.matchers = append(out.matchers, func(in *scanInput) bool {
+				return bytes.Contains(in.data, text)
+			})
+		case s.Hex != "":
+			decoded, err := hex.DecodeString(strings.Join(strings.Fields(s.Hex), ""))
+			if err != nil {
+				return nil, fmt.Errorf("string %d: %v", i, err)
+			}
+			out.matchers = append(out.matchers, func(in *scanInput) bool {
+				return bytes.Contains(in.data, decoded)
+			})
+		case s.Regex != "":
+			pattern, err := regexp.Compile(s.Regex)
+			if err != nil {
+				return nil, fmt.Errorf("string %d: %v", i, err)
+			}
+			out.matchers = append(out.matchers, func(in *scanInput) bool {
+				return pattern.Match(in.strings)
+			})
+		}
+	}
+
+	switch condition := strings.TrimSpace(rule.Condition); condition {
+	case "", "any of them":
+		out.required = 1
+	case "all of them":
+		out.required = len(out.matchers)
+	default:
+		count, ok := strings.CutSuffix(condition, " of them")
+		n, err := strconv.Atoi(count)
+		if !ok || err != nil || n < 1 || n > len(out.matchers) {
+			return nil, fmt.Errorf("invalid condition %q", rule.Condition)
+		}
+		out.required = n
+	}
+
+	return out, nil
+}
+
+func (r *compiledRule) matches(in *scanInput) bool {
+	matched := 0
+	for _, matcher := range r.matchers {
+		if matcher(in) {
+			matched++
+			if matched >= r.required {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,481 @@
+// Package triage statically analyzes payloads captured by the honeypot so
+// analysts don't need to run file, strings and YARA by hand.
+package triage
+
+import (
+	"bytes"
+	"debug/elf"
+	"debug/pe"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// MaxAnalyzedBytes is the largest prefix of a payload that's analyzed.
+	MaxAnalyzedBytes = 16 << 20
+
+	// MinStringLength is the shortest printable run reported as a string.
+	MinStringLength = 6
+	// MaxStrings is the maximum number of strings kept in an Analysis.
+	MaxStrings = 1000
+	// maxIndicators is the maximum number of each kind of indicator kept.
+	maxIndicators = 200
+)
+
+// Kinds of payloads.
+const (
+	KindELF         = "elf"
+	KindPE          = "pe"
+	KindShellScript = "shell-script"
+	KindScript      = "script"
+	KindArchive     = "archive"
+	KindText        = "text"
+	KindData        = "data"
+)
+
+// Analysis is the result of triaging a payload.
+type Analysis struct {
+	// Kind is a broad category of the payload e.g. KindELF.
+	Kind string `json:"kind"`
+	// FileType is a file(1) like description of the payload.
+	FileType string `json:"file_type"`
+	// Truncated is set if the payload was larger than MaxAnalyzedBytes.
+	Truncated bool `json:"truncated,omitempty"`
+
+	ELF *ELFInfo `json:"elf,omitempty"`
+
+	// Strings holds printable strings from the payload, up to MaxStrings.
+	Strings []string `json:"strings,omitempty"`
+	// URLs, IPs and Wallets hold unique indicators found in the payload.
+	URLs    []string `json:"urls,omitempty"`
+	IPs     []string `json:"ips,omitempty"`
+	Wallets []Wallet `json:"wallets,omitempty"`
+
+	// Matches holds the rules that matched the payload.
+	Matches []RuleMatch `json:"matches,omitempty"`
+}
+
+// RuleNames returns the names of the matching rules.
+func (a *Analysis) RuleNames() []string {
+	var out []string
+	for _, match := range a.Matches {
+		out = append(out, match.Rule)
+	}
+	return out
+}
+
+// Tags returns the sorted unique tags of the matching rules.
+func (a *Analysis) Tags() []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, match := range a.Matches {
+		for _, tag := range match.Tags {
+			if !seen[tag] {
+				seen[tag] = true
+				out = append(out, tag)
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// WalletAddresses returns the wallets formatted as "currency:address".
+func (a *Analysis) WalletAddresses() []string {
+	var out []string
+	for _, wallet := range a.Wallets {
+		out = append(out, wallet.Currency+":"+wallet.Address)
+	}
+	return out
+}
+
+// ELFInfo holds information parsed from ELF headers.
+type ELFInfo struct {
+	Class       string       `json:"class"`
+	Bits        int          `json:"bits"`
+	Endianness  string       `json:"endianness"`
+	Machine     string       `json:"machine"`
+	Type        string       `json:"type"`
+	OSABI       string       `json:"os_abi"`
+	Entry       uint64       `json:"entry"`
+	Interpreter string       `json:"interpreter,omitempty"`
+	Static      bool         `json:"static"`
+	Stripped    bool         `json:"stripped"`
+	Sections    []ELFSection `json:"sections,omitempty"`
+}
+
+// ELFSection is a section in an ELF file.
+type ELFSection struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Size uint64 `json:"size"`
+}
+
+// Wallet is a cryptocurrency wallet address.
+type Wallet struct {
+	Currency string `json:"currency"`
+	Address  string `json:"address"`
+}
+
+var (
+	urlPattern = regexp.MustCompile(`(?i)\b(?:https?|ftp|tftp)://[^\s'"<>\x60;|)]+`)
+	ipPattern  = regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`)
+
+	walletPatterns = []struct {
+		currency string
+		pattern  *regexp.Regexp
+	}{
+		{"monero", regexp.MustCompile(`\b[48][0-9AB][1-9A-HJ-NP-Za-km-z]{93}\b`)},
+		{"bitcoin", regexp.MustCompile(`\b(?:bc1[ac-hj-np-z02-9]{39,59}|[13][a-km-zA-HJ-NP-Z1-9]{25,34})\b`)},
+		{"ethereum", regexp.MustCompile(`\b0x[0-9a-fA-F]{40}\b`)},
+	}
+)
+
+// Analyzer triages payloads.
+type Analyzer struct {
+	rules []*compiledRule
+}
+
+// New creates an analyzer that scans payloads with the given rules.
+func New(rules []Rule) (*Analyzer, error) {
+	out := &Analyzer{}
+	for _, rule := range rules {
+		compiled, err := compileRule(rule)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %v", rule.Name, err)
+		}
+		out.rules = append(out.rules, compiled)
+	}
+	return out, nil
+}
+
+// Default returns an analyzer with only the builtin rules.
+func Default() *Analyzer {
+	analyzer, err := New(BuiltinRules())
+	if err != nil {
+		panic(err)
+	}
+	return analyzer
+}
+
+// AnalyzeReader reads up to MaxAnalyzedBytes from r and analyzes them.
+func (a *Analyzer) AnalyzeReader(r io.Reader) (*Analysis, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxAnalyzedBytes+1))
+	if err != nil {
+		return nil, err
+	}
+
+	truncated := len(data) > MaxAnalyzedBytes
+	if truncated {
+		data = data[:MaxAnalyzedBytes]
+	}
+	out := a.Analyze(data)
+	out.Truncated = truncated
+	return out, nil
+}
+
+// Analyze triages the payload.
+func (a *Analyzer) Analyze(data []byte) *Analysis {
+	out := &Analysis{}
+	identify(data, out)
+
+	strs := printableStrings(data, MinStringLength)
+	if len(strs) > MaxStrings {
+		out.Strings = strs[:MaxStrings]
+	} else {
+		out.Strings = strs
+	}
+
+	// Indicators are searched for in the strings so they're found in both
+	// text and binaries, text is searched as a whole so short lines count.
+	text := strings.Join(strs, "\n")
+	if isText(data) {
+		text = string(data)
+	}
+	out.URLs = uniqueMatches(urlPattern, text, nil)
+	out.IPs = uniqueMatches(ipPattern, text, func(s string) bool {
+		return net.ParseIP(s) != nil
+	})
+	for _, wp := range walletPatterns {
+		for _, address := range uniqueMatches(wp.pattern, text, nil) {
+			out.Wallets = append(out.Wallets, Wallet{Currency: wp.currency, Address: address})
+		}
+	}
+
+	if a != nil {
+		in := &scanInput{data: data, strings: []byte(text)}
+		for _, rule := range a.rules {
+			if rule.matches(in) {
+				out.Matches = append(out.Matches, RuleMatch{Rule: rule.Name, Tags: rule.Tags})
+			}
+		}
+	}
+
+	return out
+}
+
+// identify sets the kind and file type of the payload.
+func identify(data []byte, out *Analysis) {
+	switch {
+	case bytes.HasPrefix(data, []byte(elf.ELFMAG)):
+		out.Kind = KindELF
+		out.FileType = "ELF"
+		info, err := parseELF(data)
+		if err != nil {
+			out.FileType = fmt.Sprintf("ELF, corrupt: %v", err)
+			return
+		}
+		out.ELF = info
+		out.FileType = info.describe()
+	case bytes.HasPrefix(data, []byte("MZ")):
+		out.Kind = KindPE
+		out.FileType = describePE(data)
+	case bytes.HasPrefix(data, []byte("#!")):
+		interpreter := strings.Fields(string(firstLine(data[2:])))
+		out.Kind = KindScript
+		out.FileType = "script text executable"
+		if len(interpreter) > 0 {
+			name := interpreter[0]
+			if strings.HasSuffix(name, "/env") && len(interpreter) > 1 {
+				name = interpreter[1]
+			}
+			out.FileType = fmt.Sprintf("%s script text executable", name)
+			if isShell(name) {
+				out.Kind = KindShellScript
+			}
+		}
+	case bytes.HasPrefix(data, []byte{0x1f, 0x8b}):
+		out.Kind = KindArchive
+		out.FileType = "gzip compressed data"
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		out.Kind = KindArchive
+		out.FileType = "Zip archive data"
+	case bytes.HasPrefix(data, []byte("BZh")):
+		out.Kind = KindArchive
+		out.FileType = "bzip2 compressed data"
+	case bytes.HasPrefix(data, []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}):
+		out.Kind = KindArchive
+		out.FileType = "XZ compressed data"
+	case bytes.HasPrefix(data, []byte{'7', 'z', 0xbc, 0xaf, 0x27, 0x1c}):
+		out.Kind = KindArchive
+		out.FileType = "7-zip archive data"
+	case len(data) > 262 && bytes.Equal(data[257:262], []byte("ustar")):
+		out.Kind = KindArchive
+		out.FileType = "POSIX tar archive"
+	case isText(data):
+		out.Kind = KindText
+		out.FileType = "ASCII text"
+		if looksLikeShell(data) {
+			out.Kind = KindShellScript
+			out.FileType = "POSIX shell script text"
+		}
+	default:
+		out.Kind = KindData
+		out.FileType = "data"
+	}
+}
+
+func parseELF(data []byte) (*ELFInfo, error) {
+	f, err := elf.NewFile(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info := &ELFInfo{
+		Class:      f.Class.String(),
+		Endianness: "little",
+		Machine:    f.Machine.String(),
+		Type:       f.Type.String(),
+		OSABI:      f.OSABI.String(),
+		Entry:      f.Entry,
+		Static:     true,
+		Stripped:   true,
+	}
+	if f.Class == elf.ELFCLASS64 {
+		info.Bits = 64
+	} else {
+		info.Bits = 32
+	}
+	if f.Data == elf.ELFDATA2MSB {
+		info.Endianness = "big"
+	}
+
+	for _, prog := range f.Progs {
+		switch prog.Type {
+		case elf.PT_INTERP:
+			// The size comes from the sample, so don't trust it to be sane.
+			if prog.Filesz <= uint64(len(data)) {
+				interp := make([]byte, prog.Filesz)
+				if _, err := prog.ReadAt(interp, 0); err == nil {
+					info.Interpreter = string(bytes.TrimRight(interp, "\x00"))
+				}
+			}
+			info.Static = false
+		case elf.PT_DYNAMIC:
+			info.Static = false
+		}
+	}
+
+	for _, section := range f.Sections {
+		if section.Type == elf.SHT_SYMTAB {
+			info.Stripped = false
+		}
+		if section.Name == "" && section.Type == elf.SHT_NULL {
+			continue
+		}
+		info.Sections = append(info.Sections, ELFSection{
+			Name: section.Name,
+			Type: section.Type.String(),
+			Size: section.Size,
+		})
+	}
+
+	return info, nil
+}
+
+// describe formats the ELF information like file(1).
+func (e *ELFInfo) describe() string {
+	endian := "LSB"
+	if e.Endianness == "big" {
+		endian = "MSB"
+	}
+
+	var kind string
+	switch e.Type {
+	case elf.ET_EXEC.String():
+		kind = "executable"
+	case elf.ET_DYN.String():
+		kind = "shared object"
+	case elf.ET_REL.String():
+		kind = "relocatable"
+	case elf.ET_CORE.String():
+		kind = "core file"
+	default:
+		kind = strings.ToLower(e.Type)
+	}
+
+	linking := "dynamically linked"
+	if e.Static {
+		linking = "statically linked"
+	}
+	stripped := "stripped"
+	if !e.Stripped {
+		stripped = "not stripped"
+	}
+
+	return fmt.Sprintf("ELF %d-bit %s %s, %s, %s, %s",
+		e.Bits, endian, kind, strings.TrimPrefix(e.Machine, "EM_"), linking, stripped)
+}
+
+func describePE(data []byte) string {
+	f, err := pe.NewFile(bytes.NewReader(data))
+	if err != nil {
+		return "MS-DOS executable"
+	}
+	defer f.Close()
+
+	arch := fmt.Sprintf("machine 0x%x", f.Machine)
+	switch f.Machine {
+	case pe.IMAGE_FILE_MACHINE_I386:
+		arch = "Intel 80386"
+	case pe.IMAGE_FILE_MACHINE_AMD64:
+		arch = "x86-64"
+	case pe.IMAGE_FILE_MACHINE_ARM64:
+		arch = "Aarch64"
+	case pe.IMAGE_FILE_MACHINE_ARMNT:
+		arch = "ARMv7 Thumb"
+	}
+
+	kind := "executable"
+	if f.Characteristics&pe.IMAGE_FILE_DLL != 0 {
+		kind = "DLL"
+	}
+
+	return fmt.Sprintf("PE32 %s, %s", kind, arch)
+}
+
+func firstLine(data []byte) []byte {
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		return data[:i]
+	}
+	return data
+}
+
+func isShell(interpreter string) bool {
+	switch interpreter[strings.LastIndex(interpreter, "/")+1:] {
+	case "sh", "bash", "dash", "ash", "ksh", "zsh", "busybox":
+		return true
+	}
+	return false
+}
+
+// isText returns true if the data looks like UTF-8 text.
+func isText(data []byte) bool {
+	if len(data) == 0 || !utf8.Valid(data) {
+		return false
+	}
+	for _, b := range data {
+		if b < 0x20 && b != '\n' && b != '\r' && b != '\t' && b != '\f' && b != 0x1b {
+			return false
+		}
+	}
+	return true
+}
+
+var shellLinePattern = regexp.MustCompile(`(?m)^\s*(cd|wget|curl|chmod|rm|echo|export|busybox|tftp|nohup|if|for)\b`)
+
+// looksLikeShell returns true if text without a shebang has several lines
+// that look like shell commands.
+func looksLikeShell(data []byte) bool {
+	return len(shellLinePattern.FindAllIndex(data, 3)) >= 3
+}
+
+// printableStrings returns runs of printable ASCII at least minLength long.
+func printableStrings(data []byte, minLength int) []string {
+	var out []string
+	start := -1
+	for i, b := range data {
+		printable := (b >= 0x20 && b < 0x7f) || b == '\t'
+		switch {
+		case printable && start < 0:
+			start = i
+		case !printable && start >= 0:
+			if i-start >= minLength {
+				out = append(out, string(data[start:i]))
+			}
+			start = -1
+		}
+	}
+	if start >= 0 && len(data)-start >= minLength {
+		out = append(out, string(data[start:]))
+	}
+	return out
+}
+
+// uniqueMatches returns the sorted unique matches of the pattern in text
+// that pass the filter.
+func uniqueMatches(pattern *regexp.Regexp, text string, filter func(string) bool) []string {
+	seen := make(map[string]bool)
+	for _, match := range pattern.FindAllString(text, -1) {
+		if filter != nil && !filter(match) {
+			continue
+		}
+		seen[match] = true
+	}
+
+	var out []string
+	for match := range seen {
+		out = append(out, match)
+	}
+	sort.Strings(out)
+	if len(out) > maxIndicators {
+		out = out[:maxIndicators]
+	}
+	return out
+}
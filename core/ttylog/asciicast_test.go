@@ -1,6 +1,8 @@
 package ttylog
 
 import (
+	"bytes"
+	"fmt"
 	"testing"
 	"time"
 
@@ -41,3 +43,76 @@ func TestTimeConversions(t *testing.T) {
 		})
 	}
 }
+
+func TestAsciicastLogSink(t *testing.T) {
+	start := time.Date(2006, 1, 2, 3, 4, 5, 0, time.UTC).UnixMicro()
+	output := func(offsetMicros int64, data string) *TTYLogEntry {
+		return &TTYLogEntry{
+			TimestampMicros: start + offsetMicros,
+			Event:           &TTYLogEntry_Io{Io: &IO{Fd: FD_STDOUT, Data: []byte(data)}},
+		}
+	}
+	resize := func(offsetMicros int64, r *Resize) *TTYLogEntry {
+		return &TTYLogEntry{
+			TimestampMicros: start + offsetMicros,
+			Event:           &TTYLogEntry_Resize{Resize: r},
+		}
+	}
+
+	cases := map[string]struct {
+		entries   []*TTYLogEntry
+		want      string
+		wantSizes []string
+	}{
+		"default header": {
+			entries: []*TTYLogEntry{output(0, "$ ")},
+			want: `{"version":2,"width":80,"height":24,"timestamp":1136171045,"title":"github.com/josephlewis42/honeyssh session","env":{"SHELL":"/bin/sh","TERM":"xterm-256color"}}
+[0,"o","$ "]
+`,
+			wantSizes: []string{"80x24"},
+		},
+		"initial terminal": {
+			entries: []*TTYLogEntry{
+				resize(0, &Resize{Width: 200, Height: 50, Term: "xterm", Shell: "/bin/bash"}),
+				output(1e6, "$ "),
+				resize(2e6, &Resize{Width: 100, Height: 40}),
+			},
+			want: `{"version":2,"width":200,"height":50,"timestamp":1136171045,"title":"github.com/josephlewis42/honeyssh session","env":{"SHELL":"/bin/bash","TERM":"xterm"}}
+[1,"o","$ "]
+[2,"r","100x40"]
+`,
+			wantSizes: []string{"200x50", "100x40"},
+		},
+		"no pty": {
+			entries: []*TTYLogEntry{
+				resize(0, &Resize{Shell: "/bin/sh"}),
+				output(0, "uid=0(root)\n"),
+			},
+			want: `{"version":2,"width":80,"height":24,"timestamp":1136171045,"title":"github.com/josephlewis42/honeyssh session","env":{"SHELL":"/bin/sh","TERM":"xterm-256color"}}
+[0,"o","uid=0(root)\n"]
+`,
+			wantSizes: []string{"80x24"},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			sink := NewAsciicastLogSink(buf)
+			for _, entry := range tc.entries {
+				assert.NoError(t, sink(entry))
+			}
+			assert.Equal(t, tc.want, buf.String())
+
+			// Reading the recording back gives the terminal sizes.
+			var sizes []string
+			assert.NoError(t, Replay(NewAsciicastLogSource(buf), func(entry *TTYLogEntry) error {
+				if r := entry.GetResize(); r != nil {
+					sizes = append(sizes, fmt.Sprintf("%dx%d", r.GetWidth(), r.GetHeight()))
+				}
+				return nil
+			}))
+			assert.Equal(t, tc.wantSizes, sizes)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package ttylog
 
 import (
+	"fmt"
 	"io"
 	"log"
 	"regexp"
@@ -78,6 +79,34 @@ func NewClientOutput(w io.Writer) LogSink {
 	}
 }
 
+// NewClientResize resizes the terminal written to by w, e.g. for playback, to
+// match the recording's terminal before passing events to next.
+func NewClientResize(w io.Writer, next LogSink) LogSink {
+	return func(logEntry *TTYLogEntry) error {
+		if event, ok := logEntry.Event.(*TTYLogEntry_Resize); ok {
+			// XTWINOPS resize, terminals that don't support it ignore the sequence.
+			if _, err := fmt.Fprintf(w, "\x1b[8;%d;%dt", event.Resize.GetHeight(), event.Resize.GetWidth()); err != nil {
+				return err
+			}
+		}
+		return next(logEntry)
+	}
+}
+
+// MultiLogSink creates a LogSink that sends each event to all the given sinks
+// in order. The first error is returned, but every sink still sees the event.
+func MultiLogSink(sinks ...LogSink) LogSink {
+	return func(logEntry *TTYLogEntry) error {
+		var firstErr error
+		for _, sink := range sinks {
+			if err := sink(logEntry); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		return firstErr
+	}
+}
+
 // Replay reads a stream of events to a callback.
 func Replay(recording LogSource, callback LogSink) (err error) {
 	for {
@@ -101,24 +130,44 @@ type Recorder struct {
 	output LogSink
 }
 
+// Resize records a change to the terminal, shell should be set at the start
+// of the session.
+func (r *Recorder) Resize(pty vos.PTY, shell string) {
+	r.record(&TTYLogEntry{
+		TimestampMicros: time.Now().UnixMicro(),
+		Event: &TTYLogEntry_Resize{
+			Resize: &Resize{
+				Width:  int32(pty.Width),
+				Height: int32(pty.Height),
+				Term:   pty.Term,
+				Shell:  shell,
+			},
+		},
+	})
+}
+
+func (r *Recorder) record(entry *TTYLogEntry) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if err := r.output(entry); err != nil {
+		log.Print(err)
+	}
+}
+
 func (r *Recorder) recordIO(mockFd FD, data []byte, dest func([]byte) (int, error)) (int, error) {
 	eventTime := time.Now()
 	amount, err := dest(data)
-	if err == nil {
-		r.mutex.Lock()
-		e2 := r.output(&TTYLogEntry{
+	// Reads only fill part of the buffer.
+	if amount > 0 {
+		r.record(&TTYLogEntry{
 			TimestampMicros: eventTime.UnixMicro(),
 			Event: &TTYLogEntry_Io{
 				Io: &IO{
 					Fd:   mockFd,
-					Data: data,
+					Data: data[:amount],
 				},
 			},
 		})
-		r.mutex.Unlock()
-		if e2 != nil {
-			log.Print(e2)
-		}
 	}
 	return amount, err
 }
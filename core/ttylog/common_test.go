@@ -0,0 +1,43 @@
+package ttylog
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/josephlewis42/honeyssh/core/vos"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	var got []*TTYLogEntry
+	var copies int
+	recorder := NewRecorder(
+		vos.NewVIOAdapter(strings.NewReader("id\r"), io.Discard, io.Discard),
+		MultiLogSink(func(entry *TTYLogEntry) error {
+			got = append(got, entry)
+			return nil
+		}, func(*TTYLogEntry) error {
+			copies++
+			return nil
+		}),
+	)
+
+	buf := make([]byte, 1024)
+	n, err := recorder.Stdin().Read(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	_, err = recorder.Stdout().Write([]byte("id\r\n"))
+	assert.NoError(t, err)
+	// Reading at EOF doesn't record anything.
+	_, err = recorder.Stdin().Read(buf)
+	assert.Equal(t, io.EOF, err)
+
+	if assert.Len(t, got, 2) {
+		assert.Equal(t, FD_STDIN, got[0].GetIo().GetFd())
+		assert.Equal(t, "id\r", string(got[0].GetIo().GetData()))
+		assert.Equal(t, FD_STDOUT, got[1].GetIo().GetFd())
+		assert.Equal(t, "id\r\n", string(got[1].GetIo().GetData()))
+	}
+	assert.Equal(t, 2, copies)
+}
@@ -0,0 +1,51 @@
+package ttylog
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+)
+
+// CowrieFileExt is the extension of compressed Cowrie TTY logs, uncompressed
+// logs are named by their SHA-256 and have no extension.
+const CowrieFileExt = "gz"
+
+// gzipMagic starts every gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// CowrieLogSource parses log events from a Cowrie TTY log. Cowrie kept
+// Kippo's format but can compress logs with gzip.
+type CowrieLogSource struct {
+	r   io.Reader
+	uml *UMLLogSource
+}
+
+var _ LogSource = (*CowrieLogSource)(nil)
+
+// NewCowrieLogSource reads log events from a Cowrie TTY log, compressed or
+// not.
+func NewCowrieLogSource(r io.Reader) *CowrieLogSource {
+	return &CowrieLogSource{r: r}
+}
+
+// Next gets the next log entry, it returns io.EOF if there are no more.
+func (log *CowrieLogSource) Next() (*TTYLogEntry, error) {
+	if log.uml == nil {
+		buffered := bufio.NewReader(log.r)
+		var r io.Reader = buffered
+		if magic, _ := buffered.Peek(len(gzipMagic)); bytes.Equal(magic, gzipMagic) {
+			gzipReader, err := gzip.NewReader(buffered)
+			if err != nil {
+				return nil, err
+			}
+			r = gzipReader
+		}
+		log.uml = NewUMLLogSource(r)
+	}
+
+	// Keystrokes Cowrie's operators type with its interact feature are logged
+	// as their own direction, they're output as far as the attacker knows,
+	// which is how UMLLogSource reports unknown directions.
+	return log.uml.Next()
+}
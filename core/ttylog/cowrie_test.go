@@ -0,0 +1,69 @@
+package ttylog
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCowrieLogSource(t *testing.T) {
+	// writeLog writes a log the way Cowrie does, including keystrokes from
+	// its interact feature.
+	writeLog := func(w io.Writer) {
+		sink := NewUMLLogSink(w)
+		sink(&TTYLogEntry{TimestampMicros: 1000000, Event: &TTYLogEntry_Io{Io: &IO{Fd: FD_STDIN, Data: []byte("id\r")}}})
+		sink(&TTYLogEntry{TimestampMicros: 2000000, Event: &TTYLogEntry_Io{Io: &IO{Fd: FD_STDOUT, Data: []byte("uid=0(root)\r\n")}}})
+		for _, v := range []interface{}{int32(opWrite), uint32(0), int32(3), int32(3), uint32(3), uint32(0)} {
+			binary.Write(w, binary.LittleEndian, v)
+		}
+		w.Write([]byte("hi\n"))
+		sink(&TTYLogEntry{TimestampMicros: 4000000, Event: &TTYLogEntry_Close{Close: &Close{Fd: FD_STDOUT}}})
+	}
+	want := []string{
+		`1:STDIN "id\r"`,
+		`2:STDOUT "uid=0(root)\r\n"`,
+		`3:STDOUT "hi\n"`,
+	}
+
+	cases := map[string]struct {
+		data func() []byte
+	}{
+		"uncompressed": {
+			data: func() []byte {
+				out := &bytes.Buffer{}
+				writeLog(out)
+				return out.Bytes()
+			},
+		},
+		"compressed": {
+			data: func() []byte {
+				out := &bytes.Buffer{}
+				gzipWriter := gzip.NewWriter(out)
+				writeLog(gzipWriter)
+				gzipWriter.Close()
+				return out.Bytes()
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			var got []*TTYLogEntry
+			err := Replay(NewCowrieLogSource(bytes.NewReader(tc.data())), func(entry *TTYLogEntry) error {
+				got = append(got, entry)
+				return nil
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, want, describe(got))
+		})
+	}
+
+	t.Run("bad gzip", func(t *testing.T) {
+		_, err := NewCowrieLogSource(bytes.NewReader([]byte{0x1f, 0x8b, 0})).Next()
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,264 @@
+package ttylog
+
+import (
+	"io"
+	"regexp"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// grepContext is the amount of earlier output kept so matches can span
+// writes.
+const grepContext = 1024
+
+// isOutput returns true if the entry is terminal output.
+func isOutput(logEntry *TTYLogEntry) bool {
+	event, ok := logEntry.GetEvent().(*TTYLogEntry_Io)
+	return ok && event.Io.GetFd() != FD_STDIN
+}
+
+// heldResize remembers the latest resize from skipped entries so the terminal
+// has the right size when playback starts.
+type heldResize struct {
+	entry *TTYLogEntry
+}
+
+// hold remembers the entry if it's a resize.
+func (h *heldResize) hold(logEntry *TTYLogEntry) {
+	if logEntry.GetResize() != nil {
+		h.entry = logEntry
+	}
+}
+
+// release passes the held resize to next as if it happened at the start of
+// playback so no time is spent waiting for it. Nothing is passed if playback
+// starts with a resize.
+func (h *heldResize) release(start *TTYLogEntry, next LogSink) error {
+	held := h.entry
+	h.entry = nil
+	if held == nil || start.GetResize() != nil {
+		return nil
+	}
+	resize := proto.Clone(held).(*TTYLogEntry)
+	resize.TimestampMicros = start.GetTimestampMicros()
+	return next(resize)
+}
+
+// NewTimeWindowAdapter only passes entries recorded between from and to,
+// measured from the first entry. If to is zero the window has no end.
+func NewTimeWindowAdapter(from, to time.Duration, next LogSink) LogSink {
+	var started, inWindow bool
+	var startMicros int64
+	var held heldResize
+
+	return func(logEntry *TTYLogEntry) error {
+		if !started {
+			started = true
+			startMicros = logEntry.GetTimestampMicros()
+		}
+
+		offset := time.Duration(logEntry.GetTimestampMicros()-startMicros) * time.Microsecond
+		switch {
+		case offset < from:
+			held.hold(logEntry)
+			return nil
+		case to > 0 && offset > to:
+			return nil
+		}
+
+		if !inWindow {
+			inWindow = true
+			if err := held.release(logEntry, next); err != nil {
+				return err
+			}
+		}
+		return next(logEntry)
+	}
+}
+
+// NewFDFilterAdapter only passes I/O on the given file descriptors, other
+// events like resizes are always passed.
+func NewFDFilterAdapter(fds []FD, next LogSink) LogSink {
+	return func(logEntry *TTYLogEntry) error {
+		if event, ok := logEntry.GetEvent().(*TTYLogEntry_Io); ok {
+			keep := false
+			for _, fd := range fds {
+				keep = keep || event.Io.GetFd() == fd
+			}
+			if !keep {
+				return nil
+			}
+		}
+
+		return next(logEntry)
+	}
+}
+
+// NewSeekToMatchAdapter skips entries until terminal output matches the
+// pattern. Escape sequences are ignored when matching.
+func NewSeekToMatchAdapter(pattern *regexp.Regexp, next LogSink) LogSink {
+	var matched bool
+	var held heldResize
+	stripper := &ANSIStripper{}
+	var recent []byte
+
+	return func(logEntry *TTYLogEntry) error {
+		if matched {
+			return next(logEntry)
+		}
+
+		held.hold(logEntry)
+		if !isOutput(logEntry) {
+			return nil
+		}
+
+		recent = append(recent, stripper.Strip(logEntry.GetIo().GetData())...)
+		if !pattern.Match(recent) {
+			if len(recent) > grepContext {
+				recent = recent[len(recent)-grepContext:]
+			}
+			return nil
+		}
+
+		matched = true
+		if err := held.release(logEntry, next); err != nil {
+			return err
+		}
+		return next(logEntry)
+	}
+}
+
+// NewStripANSIAdapter removes escape sequences and control characters from
+// terminal I/O so it can be read as plain text. Carriage returns become
+// newlines.
+func NewStripANSIAdapter(next LogSink) LogSink {
+	strippers := make(map[FD]*ANSIStripper)
+
+	return func(logEntry *TTYLogEntry) error {
+		if event, ok := logEntry.GetEvent().(*TTYLogEntry_Io); ok {
+			// Sequences can span writes, but not streams.
+			stripper, ok := strippers[event.Io.GetFd()]
+			if !ok {
+				stripper = &ANSIStripper{}
+				strippers[event.Io.GetFd()] = stripper
+			}
+			event.Io.Data = stripper.Strip(event.Io.GetData())
+		}
+
+		return next(logEntry)
+	}
+}
+
+// NewClientInput writes stdin to the given writer, e.g. to show keystrokes.
+// Enter is written as a newline.
+func NewClientInput(w io.Writer) LogSink {
+	stripper := &ANSIStripper{}
+
+	return func(logEntry *TTYLogEntry) error {
+		event, ok := logEntry.GetEvent().(*TTYLogEntry_Io)
+		if !ok || event.Io.GetFd() != FD_STDIN {
+			return nil
+		}
+		_, err := w.Write(stripper.Strip(event.Io.GetData()))
+		return err
+	}
+}
+
+// NewJSONLinesSink writes each entry to w as a line of JSON.
+func NewJSONLinesSink(w io.Writer) LogSink {
+	// Zero values like the STDIN file descriptor are kept so lines are easy
+	// to filter.
+	marshaler := protojson.MarshalOptions{EmitUnpopulated: true}
+
+	return func(logEntry *TTYLogEntry) error {
+		data, err := marshaler.Marshal(logEntry)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(append(data, '\n'))
+		return err
+	}
+}
+
+// ansiState is the state of an ANSIStripper.
+type ansiState int
+
+const (
+	ansiGround ansiState = iota
+	ansiEscape
+	ansiCSI
+	ansiString
+	ansiStringEscape
+	ansiCharset
+)
+
+// ANSIStripper removes escape sequences and control characters other than
+// tabs and newlines from terminal I/O. Carriage returns become newlines. It
+// keeps state so sequences can be split across calls to Strip.
+type ANSIStripper struct {
+	state   ansiState
+	afterCR bool
+}
+
+// Strip returns the data without escape sequences.
+func (s *ANSIStripper) Strip(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	for _, b := range data {
+		// The newline of a CRLF was already written for the CR.
+		afterCR := s.afterCR
+		s.afterCR = false
+		if afterCR && b == '\n' {
+			continue
+		}
+
+		switch s.state {
+		case ansiGround:
+			switch {
+			case b == 0x1b:
+				s.state = ansiEscape
+			case b == '\r':
+				s.afterCR = true
+				out = append(out, '\n')
+			case b == '\n', b == '\t':
+				out = append(out, b)
+			case b < 0x20, b == 0x7f:
+				// Other control characters.
+			default:
+				out = append(out, b)
+			}
+		case ansiEscape:
+			switch b {
+			case '[':
+				s.state = ansiCSI
+			case ']', 'P', 'X', '^', '_':
+				s.state = ansiString
+			case '(', ')', '*', '+':
+				s.state = ansiCharset
+			default:
+				s.state = ansiGround
+			}
+		case ansiCSI:
+			if b >= 0x40 && b <= 0x7e {
+				s.state = ansiGround
+			}
+		case ansiString:
+			switch b {
+			case 0x07:
+				s.state = ansiGround
+			case 0x1b:
+				s.state = ansiStringEscape
+			}
+		case ansiStringEscape:
+			if b == '\\' {
+				s.state = ansiGround
+			} else {
+				s.state = ansiString
+			}
+		case ansiCharset:
+			s.state = ansiGround
+		}
+	}
+	return out
+}
@@ -0,0 +1,208 @@
+package ttylog
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// describe summarizes entries as "time:event" strings for comparison.
+func describe(entries []*TTYLogEntry) []string {
+	var out []string
+	for _, entry := range entries {
+		seconds := float64(entry.GetTimestampMicros()) / 1e6
+		switch {
+		case entry.GetResize() != nil:
+			out = append(out, fmt.Sprintf("%g:resize %dx%d", seconds, entry.GetResize().GetWidth(), entry.GetResize().GetHeight()))
+		case entry.GetIo() != nil:
+			out = append(out, fmt.Sprintf("%g:%s %q", seconds, entry.GetIo().GetFd(), entry.GetIo().GetData()))
+		}
+	}
+	return out
+}
+
+func TestFilterAdapters(t *testing.T) {
+	newEntries := func() []*TTYLogEntry {
+		return []*TTYLogEntry{
+			{TimestampMicros: 0, Event: &TTYLogEntry_Resize{Resize: &Resize{Width: 80, Height: 24}}},
+			{TimestampMicros: 0, Event: &TTYLogEntry_Io{Io: &IO{Fd: FD_STDOUT, Data: []byte("$ ")}}},
+			{TimestampMicros: 1000000, Event: &TTYLogEntry_Io{Io: &IO{Fd: FD_STDIN, Data: []byte("id\r")}}},
+			{TimestampMicros: 2000000, Event: &TTYLogEntry_Resize{Resize: &Resize{Width: 100, Height: 30}}},
+			{TimestampMicros: 3000000, Event: &TTYLogEntry_Io{Io: &IO{Fd: FD_STDOUT, Data: []byte("uid=0(ro")}}},
+			{TimestampMicros: 4000000, Event: &TTYLogEntry_Io{Io: &IO{Fd: FD_STDOUT, Data: []byte("\x1b[1mot)\x1b[0m\r\n")}}},
+			{TimestampMicros: 5000000, Event: &TTYLogEntry_Io{Io: &IO{Fd: FD_STDERR, Data: []byte("oops\r\n")}}},
+		}
+	}
+
+	cases := map[string]struct {
+		adapter func(next LogSink) LogSink
+		want    []string
+	}{
+		"time window": {
+			adapter: func(next LogSink) LogSink {
+				return NewTimeWindowAdapter(2500*time.Millisecond, 4*time.Second, next)
+			},
+			want: []string{
+				"3:resize 100x30",
+				`3:STDOUT "uid=0(ro"`,
+				`4:STDOUT "\x1b[1mot)\x1b[0m\r\n"`,
+			},
+		},
+		"time window without an end": {
+			adapter: func(next LogSink) LogSink {
+				return NewTimeWindowAdapter(5*time.Second, 0, next)
+			},
+			want: []string{
+				"5:resize 100x30",
+				`5:STDERR "oops\r\n"`,
+			},
+		},
+		"time window starting with a resize": {
+			adapter: func(next LogSink) LogSink {
+				return NewTimeWindowAdapter(2*time.Second, 3*time.Second, next)
+			},
+			want: []string{
+				"2:resize 100x30",
+				`3:STDOUT "uid=0(ro"`,
+			},
+		},
+		"stdin only": {
+			adapter: func(next LogSink) LogSink {
+				return NewFDFilterAdapter([]FD{FD_STDIN}, next)
+			},
+			want: []string{
+				"0:resize 80x24",
+				`1:STDIN "id\r"`,
+				"2:resize 100x30",
+			},
+		},
+		"match across writes and escapes": {
+			adapter: func(next LogSink) LogSink {
+				return NewSeekToMatchAdapter(regexp.MustCompile(`\(root\)`), next)
+			},
+			want: []string{
+				"4:resize 100x30",
+				`4:STDOUT "\x1b[1mot)\x1b[0m\r\n"`,
+				`5:STDERR "oops\r\n"`,
+			},
+		},
+		"no match": {
+			adapter: func(next LogSink) LogSink {
+				return NewSeekToMatchAdapter(regexp.MustCompile(`nobody`), next)
+			},
+		},
+		"strip ansi": {
+			adapter: NewStripANSIAdapter,
+			want: []string{
+				"0:resize 80x24",
+				`0:STDOUT "$ "`,
+				`1:STDIN "id\n"`,
+				"2:resize 100x30",
+				`3:STDOUT "uid=0(ro"`,
+				`4:STDOUT "ot)\n"`,
+				`5:STDERR "oops\n"`,
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			var got []*TTYLogEntry
+			sink := tc.adapter(func(entry *TTYLogEntry) error {
+				got = append(got, entry)
+				return nil
+			})
+			for _, entry := range newEntries() {
+				assert.NoError(t, sink(entry))
+			}
+			assert.Equal(t, tc.want, describe(got))
+		})
+	}
+}
+
+func TestANSIStripper(t *testing.T) {
+	cases := map[string]struct {
+		input []string
+		want  string
+	}{
+		"colors": {
+			input: []string{"\x1b[01;32mroot@localhost\x1b[00m:~# "},
+			want:  "root@localhost:~# ",
+		},
+		"split sequence": {
+			input: []string{"a\x1b[3", "1mb\x1b", "[0mc"},
+			want:  "abc",
+		},
+		"titles": {
+			input: []string{"\x1b]0;user@host\x07a\x1b]2;title\x1b\\b"},
+			want:  "ab",
+		},
+		"charset and keypad": {
+			input: []string{"\x1b(Ba\x1b=b\x1b>"},
+			want:  "ab",
+		},
+		"line endings": {
+			input: []string{"a\r\nb\r", "\nc\rd\n"},
+			want:  "a\nb\nc\nd\n",
+		},
+		"controls": {
+			input: []string{"a\x07\b\x7fb\tc"},
+			want:  "ab\tc",
+		},
+		"utf-8": {
+			input: []string{"caf\xc3", "\xa9 ✓"},
+			want:  "café ✓",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			stripper := &ANSIStripper{}
+			var got []byte
+			for _, input := range tc.input {
+				got = append(got, stripper.Strip([]byte(input))...)
+			}
+			assert.Equal(t, tc.want, string(got))
+		})
+	}
+}
+
+func TestNewClientInput(t *testing.T) {
+	out := &strings.Builder{}
+	sink := NewClientInput(out)
+	for _, entry := range []*TTYLogEntry{
+		{Event: &TTYLogEntry_Io{Io: &IO{Fd: FD_STDOUT, Data: []byte("$ ")}}},
+		{Event: &TTYLogEntry_Io{Io: &IO{Fd: FD_STDIN, Data: []byte("id\r")}}},
+		{Event: &TTYLogEntry_Io{Io: &IO{Fd: FD_STDIN, Data: []byte("\x1b[Aexit\r")}}},
+	} {
+		assert.NoError(t, sink(entry))
+	}
+	assert.Equal(t, "id\nexit\n", out.String())
+}
+
+func TestNewJSONLinesSink(t *testing.T) {
+	out := &bytes.Buffer{}
+	sink := NewJSONLinesSink(out)
+	entries := []*TTYLogEntry{
+		{TimestampMicros: 1, Event: &TTYLogEntry_Resize{Resize: &Resize{Width: 80, Height: 24}}},
+		{TimestampMicros: 2, Event: &TTYLogEntry_Io{Io: &IO{Fd: FD_STDOUT, Data: []byte("hi\r\n")}}},
+	}
+	for _, entry := range entries {
+		assert.NoError(t, sink(entry))
+	}
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if assert.Len(t, lines, len(entries)) {
+		for i, line := range lines {
+			got := &TTYLogEntry{}
+			assert.NoError(t, protojson.Unmarshal([]byte(line), got))
+			assert.Equal(t, describe(entries[i:i+1]), describe([]*TTYLogEntry{got}))
+		}
+	}
+}
@@ -0,0 +1,356 @@
+package ttylog
+
+import (
+	"math"
+	"slices"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Cadence describes how a line was typed.
+type Cadence string
+
+const (
+	// CadenceHuman lines were typed a key at a time at an uneven pace.
+	CadenceHuman Cadence = "human"
+	// CadencePasted lines arrived in bulk, but were submitted separately.
+	CadencePasted Cadence = "pasted"
+	// CadenceScripted lines were sent in bulk along with the enter key, or typed
+	// faster or more evenly than a person could.
+	CadenceScripted Cadence = "scripted"
+)
+
+const (
+	// scriptedMaxMedianGapMicros is the fastest a person is assumed to type.
+	scriptedMaxMedianGapMicros = 10000
+	// scriptedMaxGapVariation is the coefficient of variation between
+	// keystrokes below which typing is too even to be a person.
+	scriptedMaxGapVariation = 0.1
+	// scriptedMinGaps is the number of keystrokes needed to judge evenness.
+	scriptedMinGaps = 4
+)
+
+// TypedLine is a line submitted at the terminal.
+type TypedLine struct {
+	// TimestampMicros is when the line was submitted.
+	TimestampMicros int64
+	// Text holds the line after editing.
+	Text string
+	// Cadence is how the line was typed.
+	Cadence Cadence
+	// Keystrokes is the number of reads that edited the line, including enter.
+	Keystrokes int
+	// DurationMicros is the time from the first keystroke to submission.
+	DurationMicros int64
+}
+
+// KeystrokeAnalyzer reconstructs the lines a user submitted by replaying
+// their keystrokes through a line editor similar to readline. Tab completions
+// are taken from the output echoed after the tab key. Its Write method is a
+// LogSink.
+type KeystrokeAnalyzer struct {
+	// Lines holds the submitted lines in order.
+	Lines []TypedLine
+
+	line    []rune
+	cursor  int
+	history []string
+	// historyPos is the index into history being edited.
+	historyPos int
+
+	// Partial escape sequences and UTF-8 runes split across reads.
+	escape  []byte
+	partial []byte
+
+	// keyTimes holds the timestamp of each read that edited the line.
+	keyTimes []int64
+	// bulk is set if a single read inserted more than one rune.
+	bulk bool
+	// bracketed is set if the line contains a bracketed paste.
+	bracketed bool
+	// pendingTab is set if output should be checked for a completion.
+	pendingTab bool
+	// lastCR is set if the last byte was a carriage return so a following
+	// line feed doesn't submit an empty line.
+	lastCR bool
+}
+
+// NewKeystrokeAnalyzer creates an empty KeystrokeAnalyzer.
+func NewKeystrokeAnalyzer() *KeystrokeAnalyzer {
+	return &KeystrokeAnalyzer{}
+}
+
+// AnalyzeKeystrokes returns the lines submitted in the log.
+func AnalyzeKeystrokes(source LogSource) ([]TypedLine, error) {
+	analyzer := NewKeystrokeAnalyzer()
+	if err := Replay(source, analyzer.Write); err != nil {
+		return nil, err
+	}
+	return analyzer.Lines, nil
+}
+
+// Write processes the log entry.
+func (k *KeystrokeAnalyzer) Write(entry *TTYLogEntry) error {
+	event := entry.GetIo()
+	if event == nil {
+		return nil
+	}
+
+	if event.GetFd() != FD_STDIN {
+		if k.pendingTab {
+			k.pendingTab = false
+			k.complete(event.GetData())
+		}
+		return nil
+	}
+	k.pendingTab = false
+
+	timestamp := entry.GetTimestampMicros()
+	inserted := 0
+	touched := false
+	touch := func() {
+		if !touched {
+			touched = true
+			k.keyTimes = append(k.keyTimes, timestamp)
+		}
+	}
+
+	data := append(k.partial, event.GetData()...)
+	k.partial = nil
+	for len(data) > 0 {
+		b := data[0]
+		if b == '\n' && k.lastCR {
+			// Skip the line feed of a CRLF.
+			k.lastCR = false
+			data = data[1:]
+			continue
+		}
+		k.lastCR = b == '\r'
+		touch()
+
+		if len(k.escape) > 0 || b == '\x1b' {
+			k.escape = append(k.escape, b)
+			data = data[1:]
+			if k.escapeDone() {
+				k.handleEscape(string(k.escape))
+				k.escape = nil
+			}
+			continue
+		}
+
+		if b >= utf8.RuneSelf {
+			if !utf8.FullRune(data) {
+				k.partial = slices.Clone(data)
+				break
+			}
+			r, size := utf8.DecodeRune(data)
+			data = data[size:]
+			k.insert(r)
+			inserted++
+			continue
+		}
+		data = data[1:]
+
+		switch b {
+		case '\r', '\n':
+			k.submit(timestamp, inserted)
+			inserted = 0
+			touched = false
+		case '\x7f', '\b':
+			if k.cursor > 0 {
+				k.line = slices.Delete(k.line, k.cursor-1, k.cursor)
+				k.cursor--
+			}
+		case '\x01': // Ctrl+A
+			k.cursor = 0
+		case '\x05': // Ctrl+E
+			k.cursor = len(k.line)
+		case '\x02': // Ctrl+B
+			k.cursor = max(k.cursor-1, 0)
+		case '\x06': // Ctrl+F
+			k.cursor = min(k.cursor+1, len(k.line))
+		case '\x0b': // Ctrl+K
+			k.line = k.line[:k.cursor]
+		case '\x15': // Ctrl+U
+			k.line = slices.Delete(k.line, 0, k.cursor)
+			k.cursor = 0
+		case '\x17': // Ctrl+W
+			start := k.cursor
+			for start > 0 && unicode.IsSpace(k.line[start-1]) {
+				start--
+			}
+			for start > 0 && !unicode.IsSpace(k.line[start-1]) {
+				start--
+			}
+			k.line = slices.Delete(k.line, start, k.cursor)
+			k.cursor = start
+		case '\x03': // Ctrl+C abandons the line.
+			k.resetLine()
+			inserted = 0
+			touched = false
+		case '\t':
+			k.pendingTab = true
+		default:
+			if b >= ' ' {
+				k.insert(rune(b))
+				inserted++
+			}
+		}
+	}
+
+	if inserted > 1 {
+		k.bulk = true
+	}
+	return nil
+}
+
+// escapeDone returns true if the escape sequence being read is complete.
+func (k *KeystrokeAnalyzer) escapeDone() bool {
+	switch {
+	case len(k.escape) < 2:
+		return false
+	case k.escape[1] == 'O':
+		// SS3 sequences are a single character.
+		return len(k.escape) == 3
+	case k.escape[1] != '[':
+		// Alt+key
+		return true
+	default:
+		// CSI sequences end with a byte in the range @ to ~.
+		last := k.escape[len(k.escape)-1]
+		return len(k.escape) > 2 && last >= '@' && last <= '~'
+	}
+}
+
+func (k *KeystrokeAnalyzer) handleEscape(sequence string) {
+	switch sequence {
+	case "\x1b[D", "\x1bOD":
+		k.cursor = max(k.cursor-1, 0)
+	case "\x1b[C", "\x1bOC":
+		k.cursor = min(k.cursor+1, len(k.line))
+	case "\x1b[H", "\x1bOH", "\x1b[1~":
+		k.cursor = 0
+	case "\x1b[F", "\x1bOF", "\x1b[4~":
+		k.cursor = len(k.line)
+	case "\x1b[3~":
+		if k.cursor < len(k.line) {
+			k.line = slices.Delete(k.line, k.cursor, k.cursor+1)
+		}
+	case "\x1b[A", "\x1bOA":
+		k.recall(k.historyPos - 1)
+	case "\x1b[B", "\x1bOB":
+		k.recall(k.historyPos + 1)
+	case "\x1b[200~":
+		k.bracketed = true
+	}
+}
+
+// recall replaces the line with the history entry at pos, the position after
+// the last entry is an empty line.
+func (k *KeystrokeAnalyzer) recall(pos int) {
+	if pos < 0 || pos > len(k.history) {
+		return
+	}
+	k.historyPos = pos
+	k.line = nil
+	if pos < len(k.history) {
+		k.line = []rune(k.history[pos])
+	}
+	k.cursor = len(k.line)
+}
+
+func (k *KeystrokeAnalyzer) insert(r rune) {
+	k.line = slices.Insert(k.line, k.cursor, r)
+	k.cursor++
+}
+
+// complete inserts the output echoed after a tab if it looks like a completion
+// rather than a list of candidates.
+func (k *KeystrokeAnalyzer) complete(output []byte) {
+	if !utf8.Valid(output) {
+		return
+	}
+	for _, r := range string(output) {
+		if unicode.IsControl(r) {
+			return
+		}
+	}
+	for _, r := range string(output) {
+		k.insert(r)
+	}
+}
+
+// submit ends the line, inserted is the number of runes inserted by the read
+// that submitted it.
+func (k *KeystrokeAnalyzer) submit(timestampMicros int64, inserted int) {
+	text := string(k.line)
+	line := TypedLine{
+		TimestampMicros: timestampMicros,
+		Text:            text,
+		Cadence:         k.cadence(inserted),
+		Keystrokes:      len(k.keyTimes),
+	}
+	if len(k.keyTimes) > 0 {
+		line.DurationMicros = timestampMicros - k.keyTimes[0]
+	}
+	k.Lines = append(k.Lines, line)
+
+	if text != "" {
+		k.history = append(k.history, text)
+	}
+	k.resetLine()
+}
+
+func (k *KeystrokeAnalyzer) resetLine() {
+	k.line = nil
+	k.cursor = 0
+	k.historyPos = len(k.history)
+	k.keyTimes = nil
+	k.bulk = false
+	k.bracketed = false
+}
+
+// cadence classifies how the current line was typed, inserted is the number of
+// runes inserted by the read that submitted it.
+func (k *KeystrokeAnalyzer) cadence(inserted int) Cadence {
+	bulk := k.bulk || inserted > 1
+	switch {
+	case k.bracketed:
+		return CadencePasted
+	case inserted > 0 && (bulk || len(k.keyTimes) == 1):
+		return CadenceScripted
+	case bulk:
+		return CadencePasted
+	}
+
+	var gaps []float64
+	for i := 1; i < len(k.keyTimes); i++ {
+		gaps = append(gaps, float64(k.keyTimes[i]-k.keyTimes[i-1]))
+	}
+	if len(gaps) == 0 {
+		return CadenceHuman
+	}
+
+	sorted := slices.Clone(gaps)
+	slices.Sort(sorted)
+	if sorted[len(sorted)/2] < scriptedMaxMedianGapMicros {
+		return CadenceScripted
+	}
+
+	if len(gaps) >= scriptedMinGaps {
+		var mean, variance float64
+		for _, gap := range gaps {
+			mean += gap
+		}
+		mean /= float64(len(gaps))
+		for _, gap := range gaps {
+			variance += (gap - mean) * (gap - mean)
+		}
+		variance /= float64(len(gaps))
+		if math.Sqrt(variance)/mean < scriptedMaxGapVariation {
+			return CadenceScripted
+		}
+	}
+
+	return CadenceHuman
+}
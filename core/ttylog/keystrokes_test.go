@@ -0,0 +1,149 @@
+package ttylog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeystrokeAnalyzer(t *testing.T) {
+	// typed returns stdin entries for each character spaced by gapMicros.
+	typed := func(startMicros, gapMicros int64, text string) (out []*TTYLogEntry) {
+		for i, b := range []byte(text) {
+			out = append(out, &TTYLogEntry{
+				TimestampMicros: startMicros + int64(i)*gapMicros,
+				Event:           &TTYLogEntry_Io{Io: &IO{Fd: FD_STDIN, Data: []byte{b}}},
+			})
+		}
+		return
+	}
+	// human returns stdin entries for each character with uneven gaps.
+	human := func(startMicros int64, text string) (out []*TTYLogEntry) {
+		gaps := []int64{150000, 90000, 310000, 120000, 200000}
+		for i, b := range []byte(text) {
+			out = append(out, &TTYLogEntry{
+				TimestampMicros: startMicros,
+				Event:           &TTYLogEntry_Io{Io: &IO{Fd: FD_STDIN, Data: []byte{b}}},
+			})
+			startMicros += gaps[i%len(gaps)]
+		}
+		return
+	}
+	read := func(timestampMicros int64, fd FD, data string) []*TTYLogEntry {
+		return []*TTYLogEntry{{
+			TimestampMicros: timestampMicros,
+			Event:           &TTYLogEntry_Io{Io: &IO{Fd: fd, Data: []byte(data)}},
+		}}
+	}
+	join := func(parts ...[]*TTYLogEntry) (out []*TTYLogEntry) {
+		for _, part := range parts {
+			out = append(out, part...)
+		}
+		return
+	}
+
+	cases := map[string]struct {
+		entries []*TTYLogEntry
+		want    []string
+		cadence []Cadence
+	}{
+		"human": {
+			entries: human(0, "uname -a\r"),
+			want:    []string{"uname -a"},
+			cadence: []Cadence{CadenceHuman},
+		},
+		"deleted text": {
+			entries: human(0, "rm -rf /\x7f\x7f\x7f\x7f\x7f\x7f\x7f\x7fid\r"),
+			want:    []string{"id"},
+			cadence: []Cadence{CadenceHuman},
+		},
+		"ctrl-u and ctrl-w": {
+			entries: human(0, "wget evil\x15curl -O a b\x17c\r"),
+			want:    []string{"curl -O a c"},
+			cadence: []Cadence{CadenceHuman},
+		},
+		"arrows": {
+			entries: join(human(0, "ct /etc/passwd"), read(5000000, FD_STDIN, "\x1b[H\x1b[C"), human(6000000, "a\r")),
+			want:    []string{"cat /etc/passwd"},
+			cadence: []Cadence{CadenceHuman},
+		},
+		"escape split across reads": {
+			entries: join(human(0, "ls"), read(1000000, FD_STDIN, "\x1b"), read(1000001, FD_STDIN, "[D"), human(2000000, "-\r")),
+			want:    []string{"l-s"},
+			cadence: []Cadence{CadenceHuman},
+		},
+		"history": {
+			entries: join(human(0, "whoami\r"), read(5000000, FD_STDIN, "\x1b[A"), human(6000000, "\x7f\x7f\x7f\x7f\x7f\x7fid\r")),
+			want:    []string{"whoami", "id"},
+			cadence: []Cadence{CadenceHuman, CadenceHuman},
+		},
+		"tab completion": {
+			entries: join(human(0, "cat /etc/pas\t"), read(3000000, FD_STDOUT, "swd "), human(4000000, "\r")),
+			want:    []string{"cat /etc/passwd "},
+			cadence: []Cadence{CadenceHuman},
+		},
+		"tab candidates are ignored": {
+			entries: join(human(0, "cat /etc/p\t"), read(3000000, FD_STDOUT, "\r\npasswd  profile\r\n"), human(4000000, "asswd\r")),
+			want:    []string{"cat /etc/passwd"},
+			cadence: []Cadence{CadenceHuman},
+		},
+		"ctrl-c abandons the line": {
+			entries: human(0, "secret\x03ls\r"),
+			want:    []string{"ls"},
+			cadence: []Cadence{CadenceHuman},
+		},
+		"crlf submits once": {
+			entries: read(0, FD_STDIN, "uname\r\n"),
+			want:    []string{"uname"},
+			cadence: []Cadence{CadenceScripted},
+		},
+		"scripted bulk": {
+			entries: read(0, FD_STDIN, "cd /tmp\nwget http://example.com/x\n"),
+			want:    []string{"cd /tmp", "wget http://example.com/x"},
+			cadence: []Cadence{CadenceScripted, CadenceScripted},
+		},
+		"scripted fast typing": {
+			entries: typed(0, 2000, "uname -a\r"),
+			want:    []string{"uname -a"},
+			cadence: []Cadence{CadenceScripted},
+		},
+		"scripted even typing": {
+			entries: typed(0, 100000, "uname -a\r"),
+			want:    []string{"uname -a"},
+			cadence: []Cadence{CadenceScripted},
+		},
+		"pasted": {
+			entries: join(read(0, FD_STDIN, "cat /etc/shadow"), read(2000000, FD_STDIN, "\r")),
+			want:    []string{"cat /etc/shadow"},
+			cadence: []Cadence{CadencePasted},
+		},
+		"bracketed paste": {
+			entries: read(0, FD_STDIN, "\x1b[200~id\x1b[201~\r"),
+			want:    []string{"id"},
+			cadence: []Cadence{CadencePasted},
+		},
+		"unicode": {
+			entries: join(read(0, FD_STDIN, "\xc3"), read(100000, FD_STDIN, "\xa9"), human(200000, "\x7fe\r")),
+			want:    []string{"e"},
+			cadence: []Cadence{CadenceHuman},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			analyzer := NewKeystrokeAnalyzer()
+			for _, entry := range tc.entries {
+				assert.NoError(t, analyzer.Write(entry))
+			}
+
+			var gotText []string
+			var gotCadence []Cadence
+			for _, line := range analyzer.Lines {
+				gotText = append(gotText, line.Text)
+				gotCadence = append(gotCadence, line.Cadence)
+			}
+			assert.Equal(t, tc.want, gotText)
+			assert.Equal(t, tc.cadence, gotCadence)
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package ttylog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// PlayerControl is a command sent to a Player while it's playing.
+type PlayerControl int
+
+const (
+	// PlayerTogglePause pauses or resumes playback.
+	PlayerTogglePause PlayerControl = iota
+	// PlayerFaster doubles the playback speed.
+	PlayerFaster
+	// PlayerSlower halves the playback speed.
+	PlayerSlower
+	// PlayerForward jumps forward by the player's Jump duration.
+	PlayerForward
+	// PlayerBack jumps back by the player's Jump duration.
+	PlayerBack
+	// PlayerNextCommand jumps to the next time the user pressed enter.
+	PlayerNextCommand
+	// PlayerToggleInput shows or hides the user's keystrokes.
+	PlayerToggleInput
+	// PlayerQuit stops playback.
+	PlayerQuit
+)
+
+const (
+	minPlayerSpeed = 1.0 / 16
+	maxPlayerSpeed = 64
+
+	// ansiReset resets the terminal to its initial state.
+	ansiReset = "\x1bc"
+	// playerInputColor highlights keystrokes, it's reset with playerColorReset.
+	playerInputColor = "\x1b[30;43m"
+	playerColorReset = "\x1b[0m"
+)
+
+// Player plays a recording interactively.
+type Player struct {
+	// Jump is how far PlayerForward and PlayerBack move.
+	Jump time.Duration
+	// MaxIdle caps the pause between events if greater than zero.
+	MaxIdle time.Duration
+	// ShowInput shows the user's keystrokes inline.
+	ShowInput bool
+
+	out     io.Writer
+	entries []*TTYLogEntry
+
+	// next is the index of the next entry to play.
+	next int
+	// positionMicros is the current position in the recording.
+	positionMicros int64
+	speed          float64
+	paused         bool
+}
+
+// NewPlayer creates a player that writes to out, the recording is loaded by
+// passing each entry to Add.
+func NewPlayer(out io.Writer) *Player {
+	return &Player{
+		Jump:  10 * time.Second,
+		out:   out,
+		speed: 1,
+	}
+}
+
+// Add appends the entry to the recording, it's a LogSink.
+func (p *Player) Add(entry *TTYLogEntry) error {
+	if len(p.entries) == 0 {
+		p.positionMicros = entry.GetTimestampMicros()
+	}
+	p.entries = append(p.entries, entry)
+	return nil
+}
+
+// Play plays the recording until it ends or PlayerQuit is received.
+func (p *Player) Play(controls <-chan PlayerControl) error {
+	p.updateTitle()
+
+	for p.next < len(p.entries) {
+		entry := p.entries[p.next]
+
+		var timer <-chan time.Time
+		wait := p.wallDuration(entry.GetTimestampMicros() - p.positionMicros)
+		if !p.paused {
+			timer = time.After(wait)
+		}
+		waitStart := time.Now()
+
+		select {
+		case <-timer:
+			if err := p.emit(entry); err != nil {
+				return err
+			}
+			p.positionMicros = entry.GetTimestampMicros()
+			p.next++
+
+		case control, ok := <-controls:
+			if !ok {
+				// Keep playing without controls.
+				controls = nil
+				continue
+			}
+			if !p.paused {
+				// Keep the time already waited.
+				elapsed := int64(float64(time.Since(waitStart)/time.Microsecond) * p.speed)
+				p.positionMicros = min(p.positionMicros+elapsed, entry.GetTimestampMicros())
+			}
+			if control == PlayerQuit {
+				return nil
+			}
+			if err := p.Control(control); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// wallDuration converts a duration in the recording to the time to wait.
+func (p *Player) wallDuration(deltaMicros int64) time.Duration {
+	wait := time.Duration(float64(deltaMicros)/p.speed) * time.Microsecond
+	if p.MaxIdle > 0 && wait > p.MaxIdle {
+		wait = p.MaxIdle
+	}
+	return max(wait, 0)
+}
+
+// Control applies the control to the player, PlayerQuit is ignored.
+func (p *Player) Control(control PlayerControl) error {
+	switch control {
+	case PlayerTogglePause:
+		p.paused = !p.paused
+	case PlayerFaster:
+		p.speed = min(p.speed*2, maxPlayerSpeed)
+	case PlayerSlower:
+		p.speed = max(p.speed/2, minPlayerSpeed)
+	case PlayerToggleInput:
+		p.ShowInput = !p.ShowInput
+	case PlayerForward:
+		return p.SeekTo(p.positionMicros + p.Jump.Microseconds())
+	case PlayerBack:
+		return p.SeekTo(p.positionMicros - p.Jump.Microseconds())
+	case PlayerNextCommand:
+		for _, entry := range p.entries[p.next:] {
+			if isCommandEntry(entry) {
+				return p.SeekTo(entry.GetTimestampMicros())
+			}
+		}
+		return p.SeekTo(p.endMicros())
+	}
+
+	p.updateTitle()
+	return nil
+}
+
+// isCommandEntry returns true if the entry is the user pressing enter.
+func isCommandEntry(entry *TTYLogEntry) bool {
+	io := entry.GetIo()
+	return io != nil && io.GetFd() == FD_STDIN && bytes.ContainsAny(io.GetData(), "\r\n")
+}
+
+// SeekTo moves playback to the given time in the recording, every event up to
+// and including that time is shown immediately.
+func (p *Player) SeekTo(timestampMicros int64) error {
+	if len(p.entries) == 0 {
+		return nil
+	}
+	timestampMicros = max(timestampMicros, p.entries[0].GetTimestampMicros())
+	timestampMicros = min(timestampMicros, p.endMicros())
+
+	if timestampMicros < p.positionMicros {
+		// The screen can't be rewound, so redraw it from the start.
+		if _, err := io.WriteString(p.out, ansiReset); err != nil {
+			return err
+		}
+		p.next = 0
+	}
+
+	for ; p.next < len(p.entries); p.next++ {
+		entry := p.entries[p.next]
+		if entry.GetTimestampMicros() > timestampMicros {
+			break
+		}
+		if err := p.emit(entry); err != nil {
+			return err
+		}
+	}
+	p.positionMicros = timestampMicros
+	p.updateTitle()
+	return nil
+}
+
+func (p *Player) endMicros() int64 {
+	return p.entries[len(p.entries)-1].GetTimestampMicros()
+}
+
+func (p *Player) emit(entry *TTYLogEntry) (err error) {
+	switch event := entry.GetEvent().(type) {
+	case *TTYLogEntry_Io:
+		switch {
+		case event.Io.GetFd() != FD_STDIN:
+			_, err = p.out.Write(event.Io.GetData())
+		case p.ShowInput:
+			_, err = fmt.Fprintf(p.out, "%s%s%s", playerInputColor, quoteInput(event.Io.GetData()), playerColorReset)
+		}
+	case *TTYLogEntry_Resize:
+		_, err = fmt.Fprintf(p.out, "\x1b[8;%d;%dt", event.Resize.GetHeight(), event.Resize.GetWidth())
+	}
+	return err
+}
+
+// quoteInput makes keystrokes visible, e.g. enter is shown as \r.
+func quoteInput(data []byte) string {
+	quoted := strconv.Quote(string(data))
+	return quoted[1 : len(quoted)-1]
+}
+
+// updateTitle shows the player's state in the terminal's title.
+func (p *Player) updateTitle() {
+	var elapsed time.Duration
+	if len(p.entries) > 0 {
+		elapsed = time.Duration(p.positionMicros-p.entries[0].GetTimestampMicros()) * time.Microsecond
+	}
+	state := "playing"
+	if p.paused {
+		state = "paused"
+	}
+	fmt.Fprintf(p.out, "\x1b]0;%s %s %gx\x07", elapsed.Truncate(time.Second), state, p.speed)
+}
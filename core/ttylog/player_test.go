@@ -0,0 +1,128 @@
+package ttylog
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var titleRegexp = regexp.MustCompile("\x1b]0;[^\x07]*\x07")
+
+func TestPlayer(t *testing.T) {
+	entries := []*TTYLogEntry{
+		{TimestampMicros: 0, Event: &TTYLogEntry_Io{Io: &IO{Fd: FD_STDOUT, Data: []byte("$ ")}}},
+		{TimestampMicros: 1000000, Event: &TTYLogEntry_Io{Io: &IO{Fd: FD_STDIN, Data: []byte("l")}}},
+		{TimestampMicros: 1000000, Event: &TTYLogEntry_Io{Io: &IO{Fd: FD_STDOUT, Data: []byte("l")}}},
+		{TimestampMicros: 2000000, Event: &TTYLogEntry_Io{Io: &IO{Fd: FD_STDIN, Data: []byte("s\r")}}},
+		{TimestampMicros: 2000000, Event: &TTYLogEntry_Io{Io: &IO{Fd: FD_STDOUT, Data: []byte("s\r\n")}}},
+		{TimestampMicros: 3000000, Event: &TTYLogEntry_Resize{Resize: &Resize{Width: 100, Height: 30}}},
+		{TimestampMicros: 30000000, Event: &TTYLogEntry_Io{Io: &IO{Fd: FD_STDOUT, Data: []byte("bin\r\n")}}},
+	}
+
+	cases := map[string]struct {
+		showInput bool
+		controls  []PlayerControl
+		want      string
+	}{
+		"forward": {
+			controls: []PlayerControl{PlayerForward},
+			want:     "$ ls\r\n\x1b[8;30;100t",
+		},
+		"forward to end": {
+			controls: []PlayerControl{PlayerForward, PlayerForward, PlayerForward, PlayerForward},
+			want:     "$ ls\r\n\x1b[8;30;100tbin\r\n",
+		},
+		"back redraws": {
+			controls: []PlayerControl{PlayerForward, PlayerBack},
+			want:     "$ ls\r\n\x1b[8;30;100t" + ansiReset + "$ ",
+		},
+		"next command": {
+			controls: []PlayerControl{PlayerNextCommand},
+			want:     "$ ls\r\n",
+		},
+		"next command without more commands goes to end": {
+			controls: []PlayerControl{PlayerNextCommand, PlayerNextCommand},
+			want:     "$ ls\r\n\x1b[8;30;100tbin\r\n",
+		},
+		"show input": {
+			showInput: true,
+			controls:  []PlayerControl{PlayerNextCommand},
+			want:      "$ " + playerInputColor + "l" + playerColorReset + "l" + playerInputColor + `s\r` + playerColorReset + "s\r\n",
+		},
+		"toggle input": {
+			controls: []PlayerControl{PlayerToggleInput, PlayerNextCommand},
+			want:     "$ " + playerInputColor + "l" + playerColorReset + "l" + playerInputColor + `s\r` + playerColorReset + "s\r\n",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			player := NewPlayer(out)
+			player.ShowInput = tc.showInput
+			for _, entry := range entries {
+				assert.NoError(t, player.Add(entry))
+			}
+
+			for _, control := range tc.controls {
+				assert.NoError(t, player.Control(control))
+			}
+
+			assert.Equal(t, tc.want, titleRegexp.ReplaceAllString(out.String(), ""))
+		})
+	}
+}
+
+func TestPlayer_Play(t *testing.T) {
+	entries := []*TTYLogEntry{
+		{TimestampMicros: 0, Event: &TTYLogEntry_Io{Io: &IO{Fd: FD_STDOUT, Data: []byte("a")}}},
+		{TimestampMicros: 1000000, Event: &TTYLogEntry_Io{Io: &IO{Fd: FD_STDOUT, Data: []byte("b")}}},
+		{TimestampMicros: 3600000000, Event: &TTYLogEntry_Io{Io: &IO{Fd: FD_STDOUT, Data: []byte("c")}}},
+	}
+
+	newPlayer := func(out *bytes.Buffer) *Player {
+		player := NewPlayer(out)
+		for _, entry := range entries {
+			assert.NoError(t, player.Add(entry))
+		}
+		return player
+	}
+
+	t.Run("plays to end", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		player := newPlayer(out)
+		player.MaxIdle = time.Millisecond
+
+		controls := make(chan PlayerControl)
+		close(controls)
+		assert.NoError(t, player.Play(controls))
+		assert.Equal(t, "abc", titleRegexp.ReplaceAllString(out.String(), ""))
+	})
+
+	t.Run("quit", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		player := newPlayer(out)
+
+		controls := make(chan PlayerControl, 1)
+		controls <- PlayerQuit
+		assert.NoError(t, player.Play(controls))
+		assert.NotContains(t, out.String(), "c")
+	})
+
+	t.Run("speed", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		player := newPlayer(out)
+		for i := 0; i < 10; i++ {
+			player.Control(PlayerFaster)
+		}
+		assert.Equal(t, float64(maxPlayerSpeed), player.speed)
+		assert.Contains(t, out.String(), "64x")
+		for i := 0; i < 20; i++ {
+			player.Control(PlayerSlower)
+		}
+		assert.Equal(t, minPlayerSpeed, player.speed)
+	})
+}
@@ -0,0 +1,180 @@
+package ttylog
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+// ProtoFileExt holds the suggested file extension for protobuf logs.
+const ProtoFileExt = "ttylog"
+
+// protoIndexInterval is the minimum time between indexed events.
+const protoIndexInterval = 1000000 // 1s in micros
+
+// indexLocationSize returns the encoded size of an IndexLocation entry
+// including its length prefix, it's fixed because the location uses a fixed64.
+func indexLocationSize() int64 {
+	size := proto.Size(&TTYLogEntry{
+		Event: &TTYLogEntry_IndexLocation{IndexLocation: &IndexLocation{IndexSize: 1}},
+	})
+	return int64(protowire.SizeVarint(uint64(size)) + size)
+}
+
+// ErrNoIndex is returned when reading the index of a log that doesn't have
+// one.
+var ErrNoIndex = errors.New("log has no index")
+
+// ProtoLogWriter writes length delimited TTYLogEntry protobufs, its Write
+// method is a LogSink.
+type ProtoLogWriter struct {
+	w      io.Writer
+	offset int64
+
+	// index is nil if the log isn't indexed.
+	index           *Index
+	lastIndexMicros int64
+}
+
+// NewProtoLogWriter writes a protobuf log to w. If indexed is true, Close
+// appends an index of timestamps to offsets so the log can be seeked.
+func NewProtoLogWriter(w io.Writer, indexed bool) *ProtoLogWriter {
+	writer := &ProtoLogWriter{w: w}
+	if indexed {
+		writer.index = &Index{}
+	}
+	return writer
+}
+
+// Write appends the entry to the log.
+func (p *ProtoLogWriter) Write(entry *TTYLogEntry) error {
+	if p.index != nil {
+		timestamp := entry.GetTimestampMicros()
+		if len(p.index.Entries) == 0 || timestamp-p.lastIndexMicros >= protoIndexInterval {
+			p.index.Entries = append(p.index.Entries, &Index_Entry{
+				TimestampMicros: timestamp,
+				Offset:          p.offset,
+			})
+			p.lastIndexMicros = timestamp
+		}
+	}
+
+	n, err := protodelim.MarshalTo(p.w, entry)
+	p.offset += int64(n)
+	return err
+}
+
+// Close writes the index if the log is indexed, it doesn't close the
+// underlying writer.
+func (p *ProtoLogWriter) Close() error {
+	if p.index == nil {
+		return nil
+	}
+
+	n, err := protodelim.MarshalTo(p.w, &TTYLogEntry{Event: &TTYLogEntry_Index{Index: p.index}})
+	if err != nil {
+		return err
+	}
+	_, err = protodelim.MarshalTo(p.w, &TTYLogEntry{
+		Event: &TTYLogEntry_IndexLocation{IndexLocation: &IndexLocation{IndexSize: uint64(n)}},
+	})
+	return err
+}
+
+// ProtoLogSource reads log events from a length delimited protobuf log.
+type ProtoLogSource struct {
+	r *bufio.Reader
+}
+
+var _ LogSource = (*ProtoLogSource)(nil)
+
+// NewProtoLogSource reads log events from a length delimited protobuf log.
+func NewProtoLogSource(r io.Reader) *ProtoLogSource {
+	return &ProtoLogSource{r: bufio.NewReader(r)}
+}
+
+// Next gets the next log entry, it returns io.EOF if there are no more.
+func (log *ProtoLogSource) Next() (*TTYLogEntry, error) {
+	for {
+		entry := &TTYLogEntry{}
+		if err := protodelim.UnmarshalFrom(log.r, entry); err != nil {
+			return nil, err
+		}
+
+		switch entry.GetEvent().(type) {
+		case *TTYLogEntry_Index, *TTYLogEntry_IndexLocation:
+			// Skip the index.
+			continue
+		}
+		return entry, nil
+	}
+}
+
+// ReadProtoLogIndex reads the index from the end of a protobuf log, it returns
+// ErrNoIndex if the log isn't indexed.
+func ReadProtoLogIndex(r io.ReadSeeker) (*Index, error) {
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	locationSize := indexLocationSize()
+	if end < locationSize {
+		return nil, ErrNoIndex
+	}
+
+	location := &TTYLogEntry{}
+	if err := readDelimitedAt(r, end-locationSize, location); err != nil || location.GetIndexLocation() == nil {
+		return nil, ErrNoIndex
+	}
+
+	indexStart := end - locationSize - int64(location.GetIndexLocation().GetIndexSize())
+	if indexStart < 0 {
+		return nil, fmt.Errorf("malformed index location %d", indexStart)
+	}
+	index := &TTYLogEntry{}
+	if err := readDelimitedAt(r, indexStart, index); err != nil {
+		return nil, err
+	}
+	if index.GetIndex() == nil {
+		return nil, fmt.Errorf("expected index at offset %d", indexStart)
+	}
+	return index.GetIndex(), nil
+}
+
+func readDelimitedAt(r io.ReadSeeker, offset int64, entry *TTYLogEntry) error {
+	if _, err := r.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	return protodelim.UnmarshalFrom(bufio.NewReader(r), entry)
+}
+
+// SeekProtoLog positions r so reading continues from the last indexed event
+// at or before timestampMicros. Logs without an index are read from the start.
+func SeekProtoLog(r io.ReadSeeker, timestampMicros int64) error {
+	index, err := ReadProtoLogIndex(r)
+	switch {
+	case errors.Is(err, ErrNoIndex):
+		_, err := r.Seek(0, io.SeekStart)
+		return err
+	case err != nil:
+		return err
+	}
+
+	entries := index.GetEntries()
+	next := sort.Search(len(entries), func(i int) bool {
+		return entries[i].GetTimestampMicros() > timestampMicros
+	})
+
+	var offset int64
+	if next > 0 {
+		offset = entries[next-1].GetOffset()
+	}
+	_, err = r.Seek(offset, io.SeekStart)
+	return err
+}
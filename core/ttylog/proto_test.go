@@ -0,0 +1,99 @@
+package ttylog
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestProtoLog(t *testing.T) {
+	var entries []*TTYLogEntry
+	entries = append(entries, &TTYLogEntry{
+		Event: &TTYLogEntry_Resize{Resize: &Resize{Width: 120, Height: 40, Term: "xterm"}},
+	})
+	// 10 events over 5 seconds.
+	for i := 0; i < 10; i++ {
+		entries = append(entries, &TTYLogEntry{
+			TimestampMicros: int64(i) * 500000,
+			Event:           &TTYLogEntry_Io{Io: &IO{Fd: FD_STDOUT, Data: []byte(fmt.Sprintf("line %d\r\n", i))}},
+		})
+	}
+	entries = append(entries, &TTYLogEntry{
+		TimestampMicros: 5000000,
+		Event:           &TTYLogEntry_Close{Close: &Close{Fd: FD_STDOUT}},
+	})
+
+	readAll := func(t *testing.T, source LogSource) (out []*TTYLogEntry) {
+		assert.NoError(t, Replay(source, func(entry *TTYLogEntry) error {
+			out = append(out, entry)
+			return nil
+		}))
+		return
+	}
+
+	assertEntries := func(t *testing.T, want, got []*TTYLogEntry) {
+		if assert.Len(t, got, len(want)) {
+			for i := range want {
+				assert.True(t, proto.Equal(want[i], got[i]), "entry %d: want %v got %v", i, want[i], got[i])
+			}
+		}
+	}
+
+	cases := map[string]struct {
+		indexed     bool
+		seekMicros  int64
+		wantIndex   []int64
+		wantSkipped int
+	}{
+		"unindexed": {
+			seekMicros:  3000000,
+			wantSkipped: 0,
+		},
+		"indexed": {
+			indexed:     true,
+			seekMicros:  3200000,
+			wantIndex:   []int64{0, 1000000, 2000000, 3000000, 4000000, 5000000},
+			wantSkipped: 7,
+		},
+		"indexed before start": {
+			indexed:     true,
+			seekMicros:  -1,
+			wantIndex:   []int64{0, 1000000, 2000000, 3000000, 4000000, 5000000},
+			wantSkipped: 0,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			writer := NewProtoLogWriter(buf, tc.indexed)
+			for _, entry := range entries {
+				assert.NoError(t, writer.Write(entry))
+			}
+			assert.NoError(t, writer.Close())
+			data := buf.Bytes()
+
+			// The index is invisible to sequential readers.
+			assertEntries(t, entries, readAll(t, NewProtoLogSource(bytes.NewReader(data))))
+
+			index, err := ReadProtoLogIndex(bytes.NewReader(data))
+			if tc.indexed {
+				assert.NoError(t, err)
+				var gotIndex []int64
+				for _, entry := range index.GetEntries() {
+					gotIndex = append(gotIndex, entry.GetTimestampMicros())
+				}
+				assert.Equal(t, tc.wantIndex, gotIndex)
+			} else {
+				assert.ErrorIs(t, err, ErrNoIndex)
+			}
+
+			r := bytes.NewReader(data)
+			assert.NoError(t, SeekProtoLog(r, tc.seekMicros))
+			assertEntries(t, entries[tc.wantSkipped:], readAll(t, NewProtoLogSource(r)))
+		})
+	}
+}
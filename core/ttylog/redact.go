@@ -0,0 +1,64 @@
+package ttylog
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultRedaction replaces redacted text if no replacement is given.
+const DefaultRedaction = "[REDACTED]"
+
+// Redactor masks sensitive text, like the sensor's own addresses or
+// honeytoken secrets, in recordings before they're shared.
+type Redactor struct {
+	pattern     *regexp.Regexp
+	replacement []byte
+}
+
+// NewRedactor creates a redactor that replaces matches of any of the regular
+// expressions or literal strings with the replacement.
+func NewRedactor(patterns, literals []string, replacement string) (*Redactor, error) {
+	var alternatives []string
+	for _, pattern := range patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("bad redaction pattern: %v", err)
+		}
+		alternatives = append(alternatives, "(?:"+pattern+")")
+	}
+	for _, literal := range literals {
+		if literal != "" {
+			alternatives = append(alternatives, regexp.QuoteMeta(literal))
+		}
+	}
+	if replacement == "" {
+		replacement = DefaultRedaction
+	}
+
+	redactor := &Redactor{replacement: []byte(replacement)}
+	if len(alternatives) > 0 {
+		redactor.pattern = regexp.MustCompile(strings.Join(alternatives, "|"))
+	}
+	return redactor, nil
+}
+
+// Redact returns the data with sensitive text replaced.
+func (r *Redactor) Redact(data []byte) []byte {
+	if r.pattern == nil {
+		return data
+	}
+	return r.pattern.ReplaceAllLiteral(data, r.replacement)
+}
+
+// NewRedactingAdapter redacts terminal I/O before passing it to next. Text is
+// only matched within a single write so secrets typed a key at a time aren't
+// masked.
+func NewRedactingAdapter(redactor *Redactor, next LogSink) LogSink {
+	return func(logEntry *TTYLogEntry) error {
+		if event, ok := logEntry.GetEvent().(*TTYLogEntry_Io); ok {
+			event.Io.Data = redactor.Redact(event.Io.GetData())
+		}
+
+		return next(logEntry)
+	}
+}
@@ -0,0 +1,216 @@
+package ttylog
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Frame is the screen at a point in a recording.
+type Frame struct {
+	// TimestampMicros is when the frame starts being shown.
+	TimestampMicros int64
+	Screen          *Screen
+}
+
+// RenderFrames replays the output in source on a virtual terminal. A frame is
+// returned for each interval in which the screen changed, showing the screen
+// at the end of the interval. An interval of zero returns a frame for every
+// change. The last frame is the final screen.
+func RenderFrames(source LogSource, interval time.Duration) ([]Frame, error) {
+	var screen *Screen
+	var frames []Frame
+	// started is set after the first output.
+	started := false
+	changed := false
+	var frameStart int64
+
+	flush := func() {
+		if changed {
+			frames = append(frames, Frame{TimestampMicros: frameStart, Screen: screen.Clone()})
+			changed = false
+		}
+	}
+
+	err := Replay(source, func(entry *TTYLogEntry) error {
+		if screen == nil {
+			screen = NewScreen(DefaultScreenWidth, DefaultScreenHeight)
+		}
+
+		timestamp := entry.GetTimestampMicros()
+		switch event := entry.GetEvent().(type) {
+		case *TTYLogEntry_Io:
+			if event.Io.GetFd() == FD_STDIN {
+				return nil
+			}
+			if changed && timestamp >= frameStart+interval.Microseconds() {
+				flush()
+			}
+			started = true
+			screen.Write(event.Io.GetData())
+		case *TTYLogEntry_Resize:
+			if event.Resize.GetWidth() <= 0 || event.Resize.GetHeight() <= 0 {
+				return nil
+			}
+			if !started {
+				// The initial size isn't a visible change.
+				screen.Resize(int(event.Resize.GetWidth()), int(event.Resize.GetHeight()))
+				return nil
+			}
+			if changed && timestamp >= frameStart+interval.Microseconds() {
+				flush()
+			}
+			screen.Resize(int(event.Resize.GetWidth()), int(event.Resize.GetHeight()))
+		default:
+			return nil
+		}
+
+		if !changed {
+			changed = true
+			frameStart = timestamp
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	flush()
+	return frames, nil
+}
+
+// SVG layout and theme.
+const (
+	svgFontSize    = 14
+	svgCellWidth   = 8.4
+	svgCellHeight  = 17
+	svgPadding     = 10
+	svgBaseline    = 13
+	svgForeground  = "#d0d0d0"
+	svgBackground  = "#1e1e1e"
+	svgHoldSeconds = 2.0
+)
+
+// WriteSVG writes the frames as an animated SVG that loops. Pauses between
+// frames are capped at maxIdle if it's greater than zero, and the last frame
+// is held briefly before looping.
+func WriteSVG(w io.Writer, frames []Frame, maxIdle time.Duration) error {
+	if len(frames) == 0 {
+		return errors.New("no frames to render")
+	}
+
+	// Compute when each frame starts in seconds.
+	starts := make([]float64, len(frames))
+	maxWidth, maxHeight := 0, 0
+	for i, frame := range frames {
+		width, height := frame.Screen.Size()
+		maxWidth, maxHeight = max(maxWidth, width), max(maxHeight, height)
+		if i == 0 {
+			continue
+		}
+		gap := time.Duration(frame.TimestampMicros-frames[i-1].TimestampMicros) * time.Microsecond
+		if maxIdle > 0 && gap > maxIdle {
+			gap = maxIdle
+		}
+		starts[i] = starts[i-1] + max(gap, 0).Seconds()
+	}
+	total := starts[len(starts)-1] + svgHoldSeconds
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%g" height="%d" font-family="monospace" font-size="%d">`+"\n",
+		float64(maxWidth)*svgCellWidth+2*svgPadding, maxHeight*svgCellHeight+2*svgPadding, svgFontSize)
+	fmt.Fprintf(bw, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", svgBackground)
+
+	for i, frame := range frames {
+		if len(frames) == 1 {
+			bw.WriteString("<g>\n")
+		} else {
+			end := total
+			if i+1 < len(frames) {
+				end = starts[i+1]
+			}
+			values, keyTimes := []string{}, []string{}
+			if starts[i] > 0 {
+				values, keyTimes = append(values, "none"), append(keyTimes, "0")
+			}
+			values, keyTimes = append(values, "inline"), append(keyTimes, fmt.Sprintf("%.4f", starts[i]/total))
+			if end < total {
+				values, keyTimes = append(values, "none"), append(keyTimes, fmt.Sprintf("%.4f", end/total))
+			}
+			fmt.Fprintf(bw, `<g display="none"><animate attributeName="display" values="%s" keyTimes="%s" dur="%.3fs" calcMode="discrete" repeatCount="indefinite"/>`+"\n",
+				strings.Join(values, ";"), strings.Join(keyTimes, ";"), total)
+		}
+		writeSVGScreen(bw, frame.Screen)
+		bw.WriteString("</g>\n")
+	}
+
+	bw.WriteString("</svg>\n")
+	return bw.Flush()
+}
+
+// writeSVGScreen writes the backgrounds and text of the screen, text is split
+// into runs of the same style.
+func writeSVGScreen(w *bufio.Writer, screen *Screen) {
+	width, height := screen.Size()
+	for y := 0; y < height; y++ {
+		top := svgPadding + y*svgCellHeight
+		for x := 0; x < width; {
+			style := screen.Cell(x, y).Style
+			end := x + 1
+			for end < width && screen.Cell(end, y).Style == style {
+				end++
+			}
+
+			fg, bg := svgColors(style)
+			left := svgPadding + float64(x)*svgCellWidth
+			if bg != "" {
+				fmt.Fprintf(w, `<rect x="%g" y="%d" width="%g" height="%d" fill="%s"/>`+"\n",
+					left, top, float64(end-x)*svgCellWidth, svgCellHeight, bg)
+			}
+
+			var text strings.Builder
+			for i := x; i < end; i++ {
+				if r := screen.Cell(i, y).Rune; r != 0 {
+					text.WriteRune(r)
+				} else {
+					text.WriteRune(' ')
+				}
+			}
+			if strings.TrimSpace(text.String()) != "" {
+				fmt.Fprintf(w, `<text x="%g" y="%d" fill="%s" xml:space="preserve"`, left, top+svgBaseline, fg)
+				if style.Bold {
+					w.WriteString(` font-weight="bold"`)
+				}
+				if style.Underline {
+					w.WriteString(` text-decoration="underline"`)
+				}
+				w.WriteString(">")
+				xml.EscapeText(w, []byte(text.String()))
+				w.WriteString("</text>\n")
+			}
+			x = end
+		}
+	}
+}
+
+// svgColors returns the foreground and background colors of the style, the
+// background is empty if it's the default.
+func svgColors(style CellStyle) (fg, bg string) {
+	fg, bg = svgForeground, ""
+	if r, g, b, ok := style.Foreground.RGB(); ok {
+		fg = fmt.Sprintf("#%02x%02x%02x", r, g, b)
+	}
+	if r, g, b, ok := style.Background.RGB(); ok {
+		bg = fmt.Sprintf("#%02x%02x%02x", r, g, b)
+	}
+	if style.Inverse {
+		if bg == "" {
+			bg = svgBackground
+		}
+		fg, bg = bg, fg
+	}
+	return fg, bg
+}
@@ -0,0 +1,590 @@
+package ttylog
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Default terminal size if a recording doesn't include one.
+const (
+	DefaultScreenWidth  = 80
+	DefaultScreenHeight = 24
+)
+
+// Color is a 24-bit RGB color, the zero value is the terminal's default.
+type Color uint32
+
+// DefaultColor is the terminal's default foreground or background color.
+const DefaultColor Color = 0
+
+const colorSet Color = 1 << 24
+
+// RGBColor creates a color from its components.
+func RGBColor(r, g, b uint8) Color {
+	return colorSet | Color(r)<<16 | Color(g)<<8 | Color(b)
+}
+
+// RGB returns the components of the color, ok is false for DefaultColor.
+func (c Color) RGB() (r, g, b uint8, ok bool) {
+	return uint8(c >> 16), uint8(c >> 8), uint8(c), c&colorSet != 0
+}
+
+// ansiColors holds the 16 standard xterm colors.
+var ansiColors = [16][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// paletteColor returns a color from the xterm 256 color palette.
+func paletteColor(n int) Color {
+	switch {
+	case n < 0 || n > 255:
+		return DefaultColor
+	case n < 16:
+		c := ansiColors[n]
+		return RGBColor(c[0], c[1], c[2])
+	case n < 232:
+		levels := [6]uint8{0, 95, 135, 175, 215, 255}
+		n -= 16
+		return RGBColor(levels[n/36], levels[n/6%6], levels[n%6])
+	default:
+		gray := uint8(8 + 10*(n-232))
+		return RGBColor(gray, gray, gray)
+	}
+}
+
+// CellStyle holds the display attributes of a cell.
+type CellStyle struct {
+	Foreground Color
+	Background Color
+	Bold       bool
+	Underline  bool
+	Inverse    bool
+}
+
+// Cell is a single character on the screen, a zero Rune is blank.
+type Cell struct {
+	Rune  rune
+	Style CellStyle
+}
+
+type screenParserState int
+
+const (
+	stateGround screenParserState = iota
+	stateEscape
+	stateCSI
+	stateOSC
+	stateOSCEscape
+	stateCharset
+)
+
+// Screen is a VT100/xterm terminal emulator that keeps the contents of the
+// screen. Its Write method accepts terminal output. Sequences that don't
+// affect what's shown (e.g. titles and bells) are ignored.
+type Screen struct {
+	width, height int
+	cells         [][]Cell
+
+	cursorX, cursorY int
+	style            CellStyle
+	// wrapPending is set when a character was written to the last column, the
+	// cursor moves to the next line when the next character is written.
+	wrapPending bool
+	// Scroll region, inclusive.
+	scrollTop, scrollBottom int
+
+	saved struct {
+		x, y  int
+		style CellStyle
+	}
+	// primary holds the main screen while the alternate screen is shown.
+	primary [][]Cell
+
+	state  screenParserState
+	params []byte
+	// partial holds an incomplete UTF-8 sequence.
+	partial []byte
+}
+
+// NewScreen creates a blank screen of the given size.
+func NewScreen(width, height int) *Screen {
+	s := &Screen{}
+	s.Resize(width, height)
+	return s
+}
+
+// Size returns the width and height of the screen.
+func (s *Screen) Size() (width, height int) {
+	return s.width, s.height
+}
+
+// Cell returns the cell at the given position.
+func (s *Screen) Cell(x, y int) Cell {
+	return s.cells[y][x]
+}
+
+// Cursor returns the position of the cursor.
+func (s *Screen) Cursor() (x, y int) {
+	return s.cursorX, s.cursorY
+}
+
+// Resize changes the size of the screen keeping the top left contents.
+func (s *Screen) Resize(width, height int) {
+	width, height = max(width, 1), max(height, 1)
+	s.cells = resizeCells(s.cells, width, height)
+	if s.primary != nil {
+		s.primary = resizeCells(s.primary, width, height)
+	}
+	s.width, s.height = width, height
+	s.cursorX = min(s.cursorX, width-1)
+	s.cursorY = min(s.cursorY, height-1)
+	s.scrollTop, s.scrollBottom = 0, height-1
+	s.wrapPending = false
+}
+
+func resizeCells(cells [][]Cell, width, height int) [][]Cell {
+	out := make([][]Cell, height)
+	for y := range out {
+		out[y] = make([]Cell, width)
+		if y < len(cells) {
+			copy(out[y], cells[y])
+		}
+	}
+	return out
+}
+
+// Clone returns a copy of the screen.
+func (s *Screen) Clone() *Screen {
+	out := *s
+	out.cells = resizeCells(s.cells, s.width, s.height)
+	if s.primary != nil {
+		out.primary = resizeCells(s.primary, s.width, s.height)
+	}
+	out.params = append([]byte(nil), s.params...)
+	out.partial = append([]byte(nil), s.partial...)
+	return &out
+}
+
+// String returns the text on the screen with trailing whitespace removed.
+func (s *Screen) String() string {
+	lines := make([]string, s.height)
+	for y, row := range s.cells {
+		var sb strings.Builder
+		for _, cell := range row {
+			if cell.Rune == 0 {
+				sb.WriteRune(' ')
+			} else {
+				sb.WriteRune(cell.Rune)
+			}
+		}
+		lines[y] = strings.TrimRight(sb.String(), " ")
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Write processes terminal output, it never fails.
+func (s *Screen) Write(p []byte) (int, error) {
+	data := append(s.partial, p...)
+	s.partial = nil
+
+	for len(data) > 0 {
+		b := data[0]
+		if s.state == stateGround && b >= utf8.RuneSelf {
+			if !utf8.FullRune(data) {
+				s.partial = append([]byte(nil), data...)
+				break
+			}
+			r, size := utf8.DecodeRune(data)
+			data = data[size:]
+			s.put(r)
+			continue
+		}
+		data = data[1:]
+
+		switch s.state {
+		case stateGround:
+			s.control(b)
+		case stateEscape:
+			s.state = stateGround
+			s.escape(b)
+		case stateCSI:
+			s.csi(b)
+		case stateOSC:
+			switch b {
+			case '\a':
+				s.state = stateGround
+			case '\x1b':
+				s.state = stateOSCEscape
+			}
+		case stateOSCEscape, stateCharset:
+			s.state = stateGround
+		}
+	}
+	return len(p), nil
+}
+
+// control handles a byte outside of an escape sequence.
+func (s *Screen) control(b byte) {
+	switch b {
+	case '\x1b':
+		s.state = stateEscape
+	case '\r':
+		s.cursorX = 0
+		s.wrapPending = false
+	case '\n', '\v', '\f':
+		s.lineFeed()
+	case '\b':
+		s.cursorX = max(s.cursorX-1, 0)
+		s.wrapPending = false
+	case '\t':
+		s.cursorX = min((s.cursorX/8+1)*8, s.width-1)
+	default:
+		if b >= ' ' && b != '\x7f' {
+			s.put(rune(b))
+		}
+	}
+}
+
+func (s *Screen) put(r rune) {
+	if s.wrapPending {
+		s.cursorX = 0
+		s.lineFeed()
+	}
+	s.cells[s.cursorY][s.cursorX] = Cell{Rune: r, Style: s.style}
+	if s.cursorX == s.width-1 {
+		s.wrapPending = true
+	} else {
+		s.cursorX++
+	}
+}
+
+func (s *Screen) lineFeed() {
+	s.wrapPending = false
+	switch {
+	case s.cursorY == s.scrollBottom:
+		s.scrollUp(1)
+	case s.cursorY < s.height-1:
+		s.cursorY++
+	}
+}
+
+func (s *Screen) reverseIndex() {
+	s.wrapPending = false
+	switch {
+	case s.cursorY == s.scrollTop:
+		s.scrollDown(1)
+	case s.cursorY > 0:
+		s.cursorY--
+	}
+}
+
+// blank returns an erased cell, it keeps the current background color.
+func (s *Screen) blank() Cell {
+	return Cell{Style: CellStyle{Background: s.style.Background}}
+}
+
+func (s *Screen) blankRow() []Cell {
+	row := make([]Cell, s.width)
+	s.erase(row, 0, s.width)
+	return row
+}
+
+func (s *Screen) erase(row []Cell, from, to int) {
+	blank := s.blank()
+	for x := max(from, 0); x < min(to, len(row)); x++ {
+		row[x] = blank
+	}
+}
+
+// scrollLines scrolls lines from top to bottom inclusive up by n.
+func (s *Screen) scrollLines(top, bottom, n int) {
+	rows := s.cells[top : bottom+1]
+	n = min(n, len(rows))
+	copy(rows, rows[n:])
+	for i := len(rows) - n; i < len(rows); i++ {
+		rows[i] = s.blankRow()
+	}
+}
+
+// scrollLinesDown scrolls lines from top to bottom inclusive down by n.
+func (s *Screen) scrollLinesDown(top, bottom, n int) {
+	rows := s.cells[top : bottom+1]
+	n = min(n, len(rows))
+	copy(rows[n:], rows)
+	for i := 0; i < n; i++ {
+		rows[i] = s.blankRow()
+	}
+}
+
+func (s *Screen) scrollUp(n int) {
+	s.scrollLines(s.scrollTop, s.scrollBottom, n)
+}
+
+func (s *Screen) scrollDown(n int) {
+	s.scrollLinesDown(s.scrollTop, s.scrollBottom, n)
+}
+
+// escape handles the byte following an ESC.
+func (s *Screen) escape(b byte) {
+	switch b {
+	case '[':
+		s.state = stateCSI
+		s.params = s.params[:0]
+	case ']':
+		s.state = stateOSC
+	case '(', ')', '*', '+':
+		s.state = stateCharset
+	case '7':
+		s.saveCursor()
+	case '8':
+		s.restoreCursor()
+	case 'D':
+		s.lineFeed()
+	case 'E':
+		s.cursorX = 0
+		s.lineFeed()
+	case 'M':
+		s.reverseIndex()
+	case 'c':
+		*s = *NewScreen(s.width, s.height)
+	}
+}
+
+func (s *Screen) saveCursor() {
+	s.saved.x, s.saved.y, s.saved.style = s.cursorX, s.cursorY, s.style
+}
+
+func (s *Screen) restoreCursor() {
+	s.cursorX = min(s.saved.x, s.width-1)
+	s.cursorY = min(s.saved.y, s.height-1)
+	s.style = s.saved.style
+	s.wrapPending = false
+}
+
+// csi handles a byte in a control sequence.
+func (s *Screen) csi(b byte) {
+	switch {
+	case b >= 0x20 && b <= 0x3f:
+		// Parameters and intermediate bytes.
+		s.params = append(s.params, b)
+	case b >= 0x40 && b <= 0x7e:
+		s.state = stateGround
+		s.executeCSI(b)
+	case b == '\x1b':
+		s.state = stateEscape
+	default:
+		// Control characters are executed mid-sequence.
+		s.control(b)
+	}
+}
+
+// csiParams parses numeric parameters, missing values are -1.
+func (s *Screen) csiParams() (params []int, private bool) {
+	raw := string(s.params)
+	if strings.HasPrefix(raw, "?") {
+		private = true
+		raw = raw[1:]
+	}
+	if raw == "" {
+		return nil, private
+	}
+	for _, field := range strings.Split(strings.ReplaceAll(raw, ":", ";"), ";") {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			n = -1
+		}
+		params = append(params, n)
+	}
+	return params, private
+}
+
+func (s *Screen) executeCSI(final byte) {
+	params, private := s.csiParams()
+	// param returns the i-th parameter or def if it's missing or zero.
+	param := func(i, def int) int {
+		if i < len(params) && params[i] > 0 {
+			return params[i]
+		}
+		return def
+	}
+	clampX := func(x int) int { return min(max(x, 0), s.width-1) }
+	clampY := func(y int) int { return min(max(y, 0), s.height-1) }
+
+	if private {
+		switch final {
+		case 'h', 'l':
+			for _, mode := range params {
+				switch mode {
+				case 47, 1047, 1049:
+					s.alternateScreen(final == 'h')
+				}
+			}
+		}
+		return
+	}
+
+	s.wrapPending = false
+	switch final {
+	case 'A':
+		s.cursorY = clampY(s.cursorY - param(0, 1))
+	case 'B', 'e':
+		s.cursorY = clampY(s.cursorY + param(0, 1))
+	case 'C', 'a':
+		s.cursorX = clampX(s.cursorX + param(0, 1))
+	case 'D':
+		s.cursorX = clampX(s.cursorX - param(0, 1))
+	case 'E':
+		s.cursorX = 0
+		s.cursorY = clampY(s.cursorY + param(0, 1))
+	case 'F':
+		s.cursorX = 0
+		s.cursorY = clampY(s.cursorY - param(0, 1))
+	case 'G', '`':
+		s.cursorX = clampX(param(0, 1) - 1)
+	case 'd':
+		s.cursorY = clampY(param(0, 1) - 1)
+	case 'H', 'f':
+		s.cursorY = clampY(param(0, 1) - 1)
+		s.cursorX = clampX(param(1, 1) - 1)
+	case 'J':
+		switch param(0, 0) {
+		case 0:
+			s.erase(s.cells[s.cursorY], s.cursorX, s.width)
+			for y := s.cursorY + 1; y < s.height; y++ {
+				s.erase(s.cells[y], 0, s.width)
+			}
+		case 1:
+			for y := 0; y < s.cursorY; y++ {
+				s.erase(s.cells[y], 0, s.width)
+			}
+			s.erase(s.cells[s.cursorY], 0, s.cursorX+1)
+		default:
+			for y := range s.cells {
+				s.erase(s.cells[y], 0, s.width)
+			}
+		}
+	case 'K':
+		row := s.cells[s.cursorY]
+		switch param(0, 0) {
+		case 0:
+			s.erase(row, s.cursorX, s.width)
+		case 1:
+			s.erase(row, 0, s.cursorX+1)
+		default:
+			s.erase(row, 0, s.width)
+		}
+	case 'L':
+		if s.cursorY >= s.scrollTop && s.cursorY <= s.scrollBottom {
+			s.scrollLinesDown(s.cursorY, s.scrollBottom, param(0, 1))
+		}
+	case 'M':
+		if s.cursorY >= s.scrollTop && s.cursorY <= s.scrollBottom {
+			s.scrollLines(s.cursorY, s.scrollBottom, param(0, 1))
+		}
+	case '@':
+		row := s.cells[s.cursorY]
+		n := min(param(0, 1), s.width-s.cursorX)
+		copy(row[s.cursorX+n:], row[s.cursorX:])
+		s.erase(row, s.cursorX, s.cursorX+n)
+	case 'P':
+		row := s.cells[s.cursorY]
+		n := min(param(0, 1), s.width-s.cursorX)
+		copy(row[s.cursorX:], row[s.cursorX+n:])
+		s.erase(row, s.width-n, s.width)
+	case 'X':
+		s.erase(s.cells[s.cursorY], s.cursorX, s.cursorX+param(0, 1))
+	case 'S':
+		s.scrollUp(param(0, 1))
+	case 'T':
+		s.scrollDown(param(0, 1))
+	case 'm':
+		s.sgr(params)
+	case 'r':
+		top, bottom := param(0, 1)-1, param(1, s.height)-1
+		if top < bottom && bottom < s.height {
+			s.scrollTop, s.scrollBottom = top, bottom
+			s.cursorX, s.cursorY = 0, 0
+		}
+	case 's':
+		s.saveCursor()
+	case 'u':
+		s.restoreCursor()
+	}
+}
+
+// alternateScreen switches to or from the alternate screen used by full
+// screen programs like editors.
+func (s *Screen) alternateScreen(enable bool) {
+	switch {
+	case enable && s.primary == nil:
+		s.saveCursor()
+		s.primary = s.cells
+		s.cells = resizeCells(nil, s.width, s.height)
+	case !enable && s.primary != nil:
+		s.cells = s.primary
+		s.primary = nil
+		s.restoreCursor()
+	}
+}
+
+// sgr handles Select Graphic Rendition parameters.
+func (s *Screen) sgr(params []int) {
+	if len(params) == 0 {
+		params = []int{0}
+	}
+	for i := 0; i < len(params); i++ {
+		switch p := params[i]; {
+		case p == 0 || p == -1:
+			s.style = CellStyle{}
+		case p == 1:
+			s.style.Bold = true
+		case p == 22:
+			s.style.Bold = false
+		case p == 4:
+			s.style.Underline = true
+		case p == 24:
+			s.style.Underline = false
+		case p == 7:
+			s.style.Inverse = true
+		case p == 27:
+			s.style.Inverse = false
+		case p >= 30 && p <= 37:
+			s.style.Foreground = paletteColor(p - 30)
+		case p == 39:
+			s.style.Foreground = DefaultColor
+		case p >= 40 && p <= 47:
+			s.style.Background = paletteColor(p - 40)
+		case p == 49:
+			s.style.Background = DefaultColor
+		case p >= 90 && p <= 97:
+			s.style.Foreground = paletteColor(p - 90 + 8)
+		case p >= 100 && p <= 107:
+			s.style.Background = paletteColor(p - 100 + 8)
+		case p == 38 || p == 48:
+			var color Color
+			switch {
+			case i+2 < len(params) && params[i+1] == 5:
+				color = paletteColor(params[i+2])
+				i += 2
+			case i+4 < len(params) && params[i+1] == 2:
+				color = RGBColor(uint8(params[i+2]), uint8(params[i+3]), uint8(params[i+4]))
+				i += 4
+			default:
+				// Malformed, skip the rest.
+				return
+			}
+			if p == 38 {
+				s.style.Foreground = color
+			} else {
+				s.style.Background = color
+			}
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package ttylog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScreen(t *testing.T) {
+	cases := map[string]struct {
+		width, height int
+		output        []string
+		want          string
+	}{
+		"plain text": {
+			output: []string{"hello\r\nworld"},
+			want:   "hello\nworld",
+		},
+		"wraps at the edge": {
+			width:  4,
+			output: []string{"abcdef"},
+			want:   "abcd\nef",
+		},
+		"no wrap until next character": {
+			width:  4,
+			output: []string{"abcd\r\nef"},
+			want:   "abcd\nef",
+		},
+		"scrolls": {
+			height: 2,
+			output: []string{"1\r\n2\r\n3"},
+			want:   "2\n3",
+		},
+		"backspace and erase line": {
+			output: []string{"cat /etc/pass\b\b\b\b\x1b[K"},
+			want:   "cat /etc/",
+		},
+		"cursor position and clear": {
+			output: []string{"junk\x1b[2J\x1b[2;3Hx\x1b[Hy"},
+			want:   "y\n  x",
+		},
+		"utf-8 split across writes": {
+			output: []string{"caf\xc3", "\xa9"},
+			want:   "café",
+		},
+		"colors and titles are hidden": {
+			output: []string{"\x1b]0;title\x07\x1b[1;31mred\x1b[0m \x1b[38;5;202morange\x1b[m"},
+			want:   "red orange",
+		},
+		"alternate screen is restored": {
+			output: []string{"$ vi\r\n\x1b[?1049h\x1b[Hediting\x1b[?1049l$ "},
+			want:   "$ vi\n$",
+		},
+		"insert and delete characters": {
+			output: []string{"abcd\x1b[3G\x1b[@X\x1b[1G\x1b[P"},
+			want:   "bXcd",
+		},
+		"scroll region": {
+			height: 4,
+			output: []string{"header\x1b[2;3r\x1b[2;1Ha\r\nb\r\nc\x1b[4;1Hfooter"},
+			want:   "header\nb\nc\nfooter",
+		},
+		"reverse index": {
+			height: 2,
+			output: []string{"a\r\nb\x1b[H\x1bMz"},
+			want:   "z\na",
+		},
+		"reset": {
+			output: []string{"old\x1bcnew"},
+			want:   "new",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			width, height := tc.width, tc.height
+			if width == 0 {
+				width = DefaultScreenWidth
+			}
+			if height == 0 {
+				height = DefaultScreenHeight
+			}
+
+			screen := NewScreen(width, height)
+			for _, output := range tc.output {
+				screen.Write([]byte(output))
+			}
+			assert.Equal(t, tc.want, screen.String())
+		})
+	}
+}
+
+func TestScreen_Style(t *testing.T) {
+	screen := NewScreen(10, 1)
+	screen.Write([]byte("\x1b[1;31ma\x1b[7;38;2;1;2;3mb\x1b[0mc"))
+
+	assert.Equal(t, CellStyle{Foreground: paletteColor(1), Bold: true}, screen.Cell(0, 0).Style)
+	assert.Equal(t, CellStyle{Foreground: RGBColor(1, 2, 3), Bold: true, Inverse: true}, screen.Cell(1, 0).Style)
+	assert.Equal(t, CellStyle{}, screen.Cell(2, 0).Style)
+}
+
+func TestRenderFrames(t *testing.T) {
+	entries := []*TTYLogEntry{
+		{TimestampMicros: 0, Event: &TTYLogEntry_Resize{Resize: &Resize{Width: 20, Height: 5}}},
+		{TimestampMicros: 0, Event: &TTYLogEntry_Io{Io: &IO{Fd: FD_STDOUT, Data: []byte("$ ")}}},
+		{TimestampMicros: 100000, Event: &TTYLogEntry_Io{Io: &IO{Fd: FD_STDIN, Data: []byte("id\r")}}},
+		{TimestampMicros: 200000, Event: &TTYLogEntry_Io{Io: &IO{Fd: FD_STDOUT, Data: []byte("id\r\n")}}},
+		{TimestampMicros: 5000000, Event: &TTYLogEntry_Io{Io: &IO{Fd: FD_STDOUT, Data: []byte("uid=0(root)\r\n$ ")}}},
+	}
+
+	cases := map[string]struct {
+		interval   time.Duration
+		wantTimes  []int64
+		wantScreen []string
+	}{
+		"every change": {
+			wantTimes:  []int64{0, 200000, 5000000},
+			wantScreen: []string{"$", "$ id", "$ id\nuid=0(root)\n$"},
+		},
+		"one second": {
+			interval:   time.Second,
+			wantTimes:  []int64{0, 5000000},
+			wantScreen: []string{"$ id", "$ id\nuid=0(root)\n$"},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			writer := NewProtoLogWriter(buf, false)
+			for _, entry := range entries {
+				assert.NoError(t, writer.Write(entry))
+			}
+
+			frames, err := RenderFrames(NewProtoLogSource(buf), tc.interval)
+			assert.NoError(t, err)
+
+			var gotTimes []int64
+			var gotScreen []string
+			for _, frame := range frames {
+				gotTimes = append(gotTimes, frame.TimestampMicros)
+				gotScreen = append(gotScreen, frame.Screen.String())
+			}
+			assert.Equal(t, tc.wantTimes, gotTimes)
+			assert.Equal(t, tc.wantScreen, gotScreen)
+
+			width, height := frames[len(frames)-1].Screen.Size()
+			assert.Equal(t, 20, width)
+			assert.Equal(t, 5, height)
+		})
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	first := NewScreen(10, 2)
+	first.Write([]byte("$ <b>"))
+	second := first.Clone()
+	second.Write([]byte("\x1b[41mred"))
+
+	out := &strings.Builder{}
+	assert.NoError(t, WriteSVG(out, []Frame{
+		{TimestampMicros: 0, Screen: first},
+		{TimestampMicros: 60000000, Screen: second},
+	}, 3*time.Second))
+
+	svg := out.String()
+	assert.Contains(t, svg, "$ &lt;b&gt;")
+	assert.Contains(t, svg, `fill="#cd0000"`)
+	// The 60s pause is capped at 3s and the last frame is held for 2s.
+	assert.Contains(t, svg, `values="inline;none" keyTimes="0.0000;0.6000" dur="5.000s"`)
+	assert.Contains(t, svg, `values="none;inline" keyTimes="0;0.6000" dur="5.000s"`)
+
+	assert.Error(t, WriteSVG(out, nil, 0))
+}
@@ -80,6 +80,9 @@ type TTYLogEntry struct {
 	// Types that are assignable to Event:
 	//	*TTYLogEntry_Io
 	//	*TTYLogEntry_Close
+	//	*TTYLogEntry_Resize
+	//	*TTYLogEntry_Index
+	//	*TTYLogEntry_IndexLocation
 	Event isTTYLogEntry_Event `protobuf_oneof:"event"`
 }
 
@@ -143,6 +146,27 @@ func (x *TTYLogEntry) GetClose() *Close {
 	return nil
 }
 
+func (x *TTYLogEntry) GetResize() *Resize {
+	if x, ok := x.GetEvent().(*TTYLogEntry_Resize); ok {
+		return x.Resize
+	}
+	return nil
+}
+
+func (x *TTYLogEntry) GetIndex() *Index {
+	if x, ok := x.GetEvent().(*TTYLogEntry_Index); ok {
+		return x.Index
+	}
+	return nil
+}
+
+func (x *TTYLogEntry) GetIndexLocation() *IndexLocation {
+	if x, ok := x.GetEvent().(*TTYLogEntry_IndexLocation); ok {
+		return x.IndexLocation
+	}
+	return nil
+}
+
 type isTTYLogEntry_Event interface {
 	isTTYLogEntry_Event()
 }
@@ -155,10 +179,28 @@ type TTYLogEntry_Close struct {
 	Close *Close `protobuf:"bytes,3,opt,name=close,proto3,oneof"`
 }
 
+type TTYLogEntry_Resize struct {
+	Resize *Resize `protobuf:"bytes,4,opt,name=resize,proto3,oneof"`
+}
+
+type TTYLogEntry_Index struct {
+	Index *Index `protobuf:"bytes,5,opt,name=index,proto3,oneof"`
+}
+
+type TTYLogEntry_IndexLocation struct {
+	IndexLocation *IndexLocation `protobuf:"bytes,6,opt,name=index_location,json=indexLocation,proto3,oneof"`
+}
+
 func (*TTYLogEntry_Io) isTTYLogEntry_Event() {}
 
 func (*TTYLogEntry_Close) isTTYLogEntry_Event() {}
 
+func (*TTYLogEntry_Resize) isTTYLogEntry_Event() {}
+
+func (*TTYLogEntry_Index) isTTYLogEntry_Event() {}
+
+func (*TTYLogEntry_IndexLocation) isTTYLogEntry_Event() {}
+
 // I/O event on an FD.
 type IO struct {
 	state         protoimpl.MessageState
@@ -263,29 +305,287 @@ func (x *Close) GetFd() FD {
 	return FD_STDIN
 }
 
+// Change of the terminal's size, recorded at the start of the session and on
+// every window change.
+type Resize struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Width of the terminal in characters.
+	Width int32 `protobuf:"varint,1,opt,name=width,proto3" json:"width,omitempty"`
+	// Height of the terminal in characters.
+	Height int32 `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	// Name of the connected terminal, set at the start of the session.
+	Term string `protobuf:"bytes,3,opt,name=term,proto3" json:"term,omitempty"`
+	// Shell the session runs, set at the start of the session.
+	Shell string `protobuf:"bytes,4,opt,name=shell,proto3" json:"shell,omitempty"`
+}
+
+func (x *Resize) Reset() {
+	*x = Resize{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ttylog_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Resize) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Resize) ProtoMessage() {}
+
+func (x *Resize) ProtoReflect() protoreflect.Message {
+	mi := &file_ttylog_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Resize.ProtoReflect.Descriptor instead.
+func (*Resize) Descriptor() ([]byte, []int) {
+	return file_ttylog_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Resize) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *Resize) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *Resize) GetTerm() string {
+	if x != nil {
+		return x.Term
+	}
+	return ""
+}
+
+func (x *Resize) GetShell() string {
+	if x != nil {
+		return x.Shell
+	}
+	return ""
+}
+
+// Index of a length delimited protobuf log, written after the last event.
+type Index struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Entries ordered by timestamp.
+	Entries []*Index_Entry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+}
+
+func (x *Index) Reset() {
+	*x = Index{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ttylog_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Index) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Index) ProtoMessage() {}
+
+func (x *Index) ProtoReflect() protoreflect.Message {
+	mi := &file_ttylog_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Index.ProtoReflect.Descriptor instead.
+func (*Index) Descriptor() ([]byte, []int) {
+	return file_ttylog_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Index) GetEntries() []*Index_Entry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+// Last entry of an indexed log. It has a fixed size so it can be read from the
+// end of the file.
+type IndexLocation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Size in bytes of the index entry preceding this one.
+	IndexSize uint64 `protobuf:"fixed64,1,opt,name=index_size,json=indexSize,proto3" json:"index_size,omitempty"`
+}
+
+func (x *IndexLocation) Reset() {
+	*x = IndexLocation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ttylog_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IndexLocation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IndexLocation) ProtoMessage() {}
+
+func (x *IndexLocation) ProtoReflect() protoreflect.Message {
+	mi := &file_ttylog_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IndexLocation.ProtoReflect.Descriptor instead.
+func (*IndexLocation) Descriptor() ([]byte, []int) {
+	return file_ttylog_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *IndexLocation) GetIndexSize() uint64 {
+	if x != nil {
+		return x.IndexSize
+	}
+	return 0
+}
+
+type Index_Entry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Timestamp of the event.
+	TimestampMicros int64 `protobuf:"varint,1,opt,name=timestamp_micros,json=timestampMicros,proto3" json:"timestamp_micros,omitempty"`
+	// Byte offset of the event from the start of the file.
+	Offset int64 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+}
+
+func (x *Index_Entry) Reset() {
+	*x = Index_Entry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ttylog_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Index_Entry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Index_Entry) ProtoMessage() {}
+
+func (x *Index_Entry) ProtoReflect() protoreflect.Message {
+	mi := &file_ttylog_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Index_Entry.ProtoReflect.Descriptor instead.
+func (*Index_Entry) Descriptor() ([]byte, []int) {
+	return file_ttylog_proto_rawDescGZIP(), []int{4, 0}
+}
+
+func (x *Index_Entry) GetTimestampMicros() int64 {
+	if x != nil {
+		return x.TimestampMicros
+	}
+	return 0
+}
+
+func (x *Index_Entry) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
 var File_ttylog_proto protoreflect.FileDescriptor
 
 var file_ttylog_proto_rawDesc = []byte{
-	0x0a, 0x0c, 0x74, 0x74, 0x79, 0x6c, 0x6f, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x78,
-	0x0a, 0x0b, 0x54, 0x54, 0x59, 0x4c, 0x6f, 0x67, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x29, 0x0a,
-	0x10, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x5f, 0x6d, 0x69, 0x63, 0x72, 0x6f,
-	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
-	0x6d, 0x70, 0x4d, 0x69, 0x63, 0x72, 0x6f, 0x73, 0x12, 0x15, 0x0a, 0x02, 0x69, 0x6f, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x03, 0x2e, 0x49, 0x4f, 0x48, 0x00, 0x52, 0x02, 0x69, 0x6f, 0x12,
-	0x1e, 0x0a, 0x05, 0x63, 0x6c, 0x6f, 0x73, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x06,
-	0x2e, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x48, 0x00, 0x52, 0x05, 0x63, 0x6c, 0x6f, 0x73, 0x65, 0x42,
-	0x07, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x22, 0x2d, 0x0a, 0x02, 0x49, 0x4f, 0x12, 0x13,
-	0x0a, 0x02, 0x66, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x03, 0x2e, 0x46, 0x44, 0x52,
-	0x02, 0x66, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x1c, 0x0a, 0x05, 0x43, 0x6c, 0x6f, 0x73, 0x65,
-	0x12, 0x13, 0x0a, 0x02, 0x66, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x03, 0x2e, 0x46,
-	0x44, 0x52, 0x02, 0x66, 0x64, 0x2a, 0x27, 0x0a, 0x02, 0x46, 0x44, 0x12, 0x09, 0x0a, 0x05, 0x53,
-	0x54, 0x44, 0x49, 0x4e, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x54, 0x44, 0x4f, 0x55, 0x54,
-	0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x54, 0x44, 0x45, 0x52, 0x52, 0x10, 0x02, 0x42, 0x2f,
-	0x5a, 0x2d, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6a, 0x6f, 0x73,
-	0x65, 0x70, 0x68, 0x6c, 0x65, 0x77, 0x69, 0x73, 0x34, 0x32, 0x2f, 0x68, 0x6f, 0x6e, 0x65, 0x79,
-	0x73, 0x73, 0x68, 0x2f, 0x63, 0x6f, 0x72, 0x65, 0x2f, 0x74, 0x74, 0x79, 0x6c, 0x6f, 0x67, 0x62,
-	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x0a, 0x0c, 0x74, 0x74, 0x79, 0x6c, 0x6f, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xf4,
+	0x01, 0x0a, 0x0b, 0x54, 0x54, 0x59, 0x4c, 0x6f, 0x67, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x29,
+	0x0a, 0x10, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x5f, 0x6d, 0x69, 0x63, 0x72,
+	0x6f, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x4d, 0x69, 0x63, 0x72, 0x6f, 0x73, 0x12, 0x15, 0x0a, 0x02, 0x69, 0x6f, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x03, 0x2e, 0x49, 0x4f, 0x48, 0x00, 0x52, 0x02, 0x69, 0x6f,
+	0x12, 0x1e, 0x0a, 0x05, 0x63, 0x6c, 0x6f, 0x73, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x06, 0x2e, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x48, 0x00, 0x52, 0x05, 0x63, 0x6c, 0x6f, 0x73, 0x65,
+	0x12, 0x21, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x07, 0x2e, 0x52, 0x65, 0x73, 0x69, 0x7a, 0x65, 0x48, 0x00, 0x52, 0x06, 0x72, 0x65, 0x73,
+	0x69, 0x7a, 0x65, 0x12, 0x1e, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x06, 0x2e, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x48, 0x00, 0x52, 0x05, 0x69, 0x6e,
+	0x64, 0x65, 0x78, 0x12, 0x37, 0x0a, 0x0e, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x6c, 0x6f, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x49, 0x6e,
+	0x64, 0x65, 0x78, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x0d, 0x69,
+	0x6e, 0x64, 0x65, 0x78, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x07, 0x0a, 0x05,
+	0x65, 0x76, 0x65, 0x6e, 0x74, 0x22, 0x2d, 0x0a, 0x02, 0x49, 0x4f, 0x12, 0x13, 0x0a, 0x02, 0x66,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x03, 0x2e, 0x46, 0x44, 0x52, 0x02, 0x66, 0x64,
+	0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04,
+	0x64, 0x61, 0x74, 0x61, 0x22, 0x1c, 0x0a, 0x05, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x12, 0x13, 0x0a,
+	0x02, 0x66, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x03, 0x2e, 0x46, 0x44, 0x52, 0x02,
+	0x66, 0x64, 0x22, 0x60, 0x0a, 0x06, 0x52, 0x65, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x14, 0x0a, 0x05,
+	0x77, 0x69, 0x64, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x77, 0x69, 0x64,
+	0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65,
+	0x72, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x72, 0x6d, 0x12, 0x14,
+	0x0a, 0x05, 0x73, 0x68, 0x65, 0x6c, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73,
+	0x68, 0x65, 0x6c, 0x6c, 0x22, 0x7b, 0x0a, 0x05, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x26, 0x0a,
+	0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c,
+	0x2e, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x2e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e,
+	0x74, 0x72, 0x69, 0x65, 0x73, 0x1a, 0x4a, 0x0a, 0x05, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x29,
+	0x0a, 0x10, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x5f, 0x6d, 0x69, 0x63, 0x72,
+	0x6f, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x4d, 0x69, 0x63, 0x72, 0x6f, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66,
+	0x73, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65,
+	0x74, 0x22, 0x2e, 0x0a, 0x0d, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x73, 0x69, 0x7a, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x06, 0x52, 0x09, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x53, 0x69, 0x7a,
+	0x65, 0x2a, 0x27, 0x0a, 0x02, 0x46, 0x44, 0x12, 0x09, 0x0a, 0x05, 0x53, 0x54, 0x44, 0x49, 0x4e,
+	0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x54, 0x44, 0x4f, 0x55, 0x54, 0x10, 0x01, 0x12, 0x0a,
+	0x0a, 0x06, 0x53, 0x54, 0x44, 0x45, 0x52, 0x52, 0x10, 0x02, 0x42, 0x2f, 0x5a, 0x2d, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6a, 0x6f, 0x73, 0x65, 0x70, 0x68, 0x6c,
+	0x65, 0x77, 0x69, 0x73, 0x34, 0x32, 0x2f, 0x68, 0x6f, 0x6e, 0x65, 0x79, 0x73, 0x73, 0x68, 0x2f,
+	0x63, 0x6f, 0x72, 0x65, 0x2f, 0x74, 0x74, 0x79, 0x6c, 0x6f, 0x67, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
 }
 
 var (
@@ -301,23 +601,31 @@ func file_ttylog_proto_rawDescGZIP() []byte {
 }
 
 var file_ttylog_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_ttylog_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_ttylog_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_ttylog_proto_goTypes = []interface{}{
-	(FD)(0),             // 0: FD
-	(*TTYLogEntry)(nil), // 1: TTYLogEntry
-	(*IO)(nil),          // 2: IO
-	(*Close)(nil),       // 3: Close
+	(FD)(0),               // 0: FD
+	(*TTYLogEntry)(nil),   // 1: TTYLogEntry
+	(*IO)(nil),            // 2: IO
+	(*Close)(nil),         // 3: Close
+	(*Resize)(nil),        // 4: Resize
+	(*Index)(nil),         // 5: Index
+	(*IndexLocation)(nil), // 6: IndexLocation
+	(*Index_Entry)(nil),   // 7: Index.Entry
 }
 var file_ttylog_proto_depIdxs = []int32{
 	2, // 0: TTYLogEntry.io:type_name -> IO
 	3, // 1: TTYLogEntry.close:type_name -> Close
-	0, // 2: IO.fd:type_name -> FD
-	0, // 3: Close.fd:type_name -> FD
-	4, // [4:4] is the sub-list for method output_type
-	4, // [4:4] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	4, // 2: TTYLogEntry.resize:type_name -> Resize
+	5, // 3: TTYLogEntry.index:type_name -> Index
+	6, // 4: TTYLogEntry.index_location:type_name -> IndexLocation
+	0, // 5: IO.fd:type_name -> FD
+	0, // 6: Close.fd:type_name -> FD
+	7, // 7: Index.entries:type_name -> Index.Entry
+	8, // [8:8] is the sub-list for method output_type
+	8, // [8:8] is the sub-list for method input_type
+	8, // [8:8] is the sub-list for extension type_name
+	8, // [8:8] is the sub-list for extension extendee
+	0, // [0:8] is the sub-list for field type_name
 }
 
 func init() { file_ttylog_proto_init() }
@@ -362,10 +670,61 @@ func file_ttylog_proto_init() {
 				return nil
 			}
 		}
+		file_ttylog_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Resize); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ttylog_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Index); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ttylog_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IndexLocation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_ttylog_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Index_Entry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	file_ttylog_proto_msgTypes[0].OneofWrappers = []interface{}{
 		(*TTYLogEntry_Io)(nil),
 		(*TTYLogEntry_Close)(nil),
+		(*TTYLogEntry_Resize)(nil),
+		(*TTYLogEntry_Index)(nil),
+		(*TTYLogEntry_IndexLocation)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -373,7 +732,7 @@ func file_ttylog_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_ttylog_proto_rawDesc,
 			NumEnums:      1,
-			NumMessages:   3,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -78,6 +78,9 @@ func NewUMLLogSink(w io.Writer) LogSink {
 			return logEvent(w, timestamp, event.Io.Fd, opWrite, event.Io.Data)
 		case *TTYLogEntry_Close:
 			return logEvent(w, timestamp, event.Close.Fd, opClose, nil)
+		case *TTYLogEntry_Resize:
+			// The format doesn't support terminal sizes.
+			return nil
 		default:
 			return fmt.Errorf("unknown event: %T", entry.GetEvent())
 		}
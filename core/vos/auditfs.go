@@ -0,0 +1,207 @@
+package vos
+
+import (
+	"os"
+	"path"
+	"sync/atomic"
+
+	"github.com/josephlewis42/honeyssh/core/logger"
+	"github.com/spf13/afero"
+)
+
+// auditFs records the changes a process makes to the underlying filesystem,
+// names must be absolute and resolved.
+type auditFs struct {
+	VFS
+
+	proc *TenantProcOS
+}
+
+var _ afero.Lstater = (*auditFs)(nil)
+var _ afero.Linker = (*auditFs)(nil)
+var _ afero.LinkReader = (*auditFs)(nil)
+
+// newAuditFs audits base for the process if auditing is enabled.
+func newAuditFs(base VFS, proc *TenantProcOS) VFS {
+	if !proc.config.FilesystemAudit.Enabled {
+		return base
+	}
+	return &auditFs{VFS: base, proc: proc}
+}
+
+// record logs the operation if the path isn't excluded.
+func (a *auditFs) record(op *logger.FilesystemOp) {
+	if !a.proc.config.FilesystemAudit.LogsWrite(path.Clean(op.Path)) {
+		return
+	}
+	op.Uid = int32(a.proc.UID)
+	op.Command = a.proc.ProcArgs
+	a.proc.eventRecorder.Record(&logger.LogEntry_FilesystemOperation{
+		FilesystemOperation: op,
+	})
+}
+
+func (a *auditFs) Create(name string) (afero.File, error) {
+	return a.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
+}
+
+func (a *auditFs) Open(name string) (afero.File, error) {
+	return a.OpenFile(name, os.O_RDONLY, 0)
+}
+
+func (a *auditFs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	_, statErr := a.VFS.Stat(name)
+	fd, err := a.VFS.OpenFile(name, flag, perm)
+	if err != nil {
+		return fd, err
+	}
+
+	switch {
+	case flag&(os.O_WRONLY|os.O_RDWR) == 0:
+		if a.proc.config.FilesystemAudit.LogsRead(path.Clean(name)) {
+			a.proc.eventRecorder.Record(&logger.LogEntry_OpenFile{
+				OpenFile: &logger.OpenFile{
+					Path:    name,
+					Uid:     int32(a.proc.UID),
+					Command: a.proc.ProcArgs,
+				},
+			})
+		}
+		return fd, nil
+
+	case os.IsNotExist(statErr):
+		return &auditFile{File: fd, fs: a, op: &logger.FilesystemOp{
+			Op:   logger.FilesystemOp_CREATE,
+			Path: name,
+			Mode: uint32(perm),
+		}}, nil
+
+	default:
+		return &auditFile{File: fd, fs: a, op: &logger.FilesystemOp{
+			Op:   logger.FilesystemOp_WRITE,
+			Path: name,
+		}}, nil
+	}
+}
+
+func (a *auditFs) Remove(name string) error {
+	err := a.VFS.Remove(name)
+	if err == nil {
+		a.record(&logger.FilesystemOp{Op: logger.FilesystemOp_REMOVE, Path: name})
+	}
+	return err
+}
+
+func (a *auditFs) RemoveAll(name string) error {
+	_, statErr := a.VFS.Stat(name)
+	err := a.VFS.RemoveAll(name)
+	if err == nil && statErr == nil {
+		a.record(&logger.FilesystemOp{Op: logger.FilesystemOp_REMOVE, Path: name})
+	}
+	return err
+}
+
+func (a *auditFs) Rename(oldname, newname string) error {
+	err := a.VFS.Rename(oldname, newname)
+	if err == nil {
+		a.record(&logger.FilesystemOp{Op: logger.FilesystemOp_RENAME, Path: oldname, NewPath: newname})
+	}
+	return err
+}
+
+func (a *auditFs) Chmod(name string, mode os.FileMode) error {
+	err := a.VFS.Chmod(name, mode)
+	if err == nil {
+		a.record(&logger.FilesystemOp{Op: logger.FilesystemOp_CHMOD, Path: name, Mode: uint32(mode)})
+	}
+	return err
+}
+
+func (a *auditFs) Chown(name string, uid, gid int) error {
+	err := a.VFS.Chown(name, uid, gid)
+	if err == nil {
+		a.record(&logger.FilesystemOp{Op: logger.FilesystemOp_CHOWN, Path: name, OwnerUid: int32(uid), OwnerGid: int32(gid)})
+	}
+	return err
+}
+
+func (a *auditFs) Mkdir(name string, perm os.FileMode) error {
+	err := a.VFS.Mkdir(name, perm)
+	if err == nil {
+		a.record(&logger.FilesystemOp{Op: logger.FilesystemOp_MKDIR, Path: name, Mode: uint32(perm)})
+	}
+	return err
+}
+
+func (a *auditFs) MkdirAll(name string, perm os.FileMode) error {
+	_, statErr := a.VFS.Stat(name)
+	err := a.VFS.MkdirAll(name, perm)
+	if err == nil && os.IsNotExist(statErr) {
+		a.record(&logger.FilesystemOp{Op: logger.FilesystemOp_MKDIR, Path: name, Mode: uint32(perm)})
+	}
+	return err
+}
+
+func (a *auditFs) LstatIfPossible(name string) (os.FileInfo, bool, error) {
+	if lstater, ok := a.VFS.(afero.Lstater); ok {
+		return lstater.LstatIfPossible(name)
+	}
+	fi, err := a.VFS.Stat(name)
+	return fi, false, err
+}
+
+func (a *auditFs) SymlinkIfPossible(oldname, newname string) error {
+	linker, ok := a.VFS.(afero.Linker)
+	if !ok {
+		return &os.LinkError{Op: FsOpSymlink, Old: oldname, New: newname, Err: afero.ErrNoSymlink}
+	}
+	err := linker.SymlinkIfPossible(oldname, newname)
+	if err == nil {
+		a.record(&logger.FilesystemOp{Op: logger.FilesystemOp_SYMLINK, Path: oldname, NewPath: newname})
+	}
+	return err
+}
+
+func (a *auditFs) ReadlinkIfPossible(name string) (string, error) {
+	if reader, ok := a.VFS.(afero.LinkReader); ok {
+		return reader.ReadlinkIfPossible(name)
+	}
+	return "", &os.PathError{Op: FsOpReadlink, Path: name, Err: afero.ErrNoReadlink}
+}
+
+// auditFile counts the bytes written to a file and records them when it's
+// closed.
+type auditFile struct {
+	afero.File
+
+	fs      *auditFs
+	op      *logger.FilesystemOp
+	written atomic.Int64
+	closed  atomic.Bool
+}
+
+func (f *auditFile) Write(p []byte) (int, error) {
+	n, err := f.File.Write(p)
+	f.written.Add(int64(n))
+	return n, err
+}
+
+func (f *auditFile) WriteAt(p []byte, off int64) (int, error) {
+	n, err := f.File.WriteAt(p, off)
+	f.written.Add(int64(n))
+	return n, err
+}
+
+func (f *auditFile) WriteString(s string) (int, error) {
+	n, err := f.File.WriteString(s)
+	f.written.Add(int64(n))
+	return n, err
+}
+
+func (f *auditFile) Close() error {
+	if !f.closed.Swap(true) {
+		f.op.BytesWritten = f.written.Load()
+		f.fs.record(f.op)
+	}
+	return f.File.Close()
+}
@@ -0,0 +1,186 @@
+package vos
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/josephlewis42/honeyssh/core/config"
+	"github.com/josephlewis42/honeyssh/core/logger"
+	"github.com/josephlewis42/honeyssh/third_party/memmapfs"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestAuditFs(t *testing.T) {
+	cfg := &config.Configuration{
+		FilesystemAudit: config.FilesystemAudit{
+			Enabled: true,
+			Reads:   []string{"/etc/shadow", "/root/.ssh"},
+			Exclude: []string{"/tmp/*.lock"},
+		},
+	}
+
+	cases := map[string]struct {
+		run        func(VOS) error
+		wantEvents []logger.LogType
+	}{
+		"create": {
+			run: func(virtOS VOS) error {
+				fd, err := virtOS.Create("/tmp/x")
+				if err != nil {
+					return err
+				}
+				fd.Write([]byte("hello"))
+				fd.WriteString(" world")
+				return fd.Close()
+			},
+			wantEvents: []logger.LogType{
+				&logger.LogEntry_FilesystemOperation{FilesystemOperation: &logger.FilesystemOp{
+					Op: logger.FilesystemOp_CREATE, Path: "/tmp/x", Mode: 0666, BytesWritten: 11, Uid: 1000, Command: []string{"test"},
+				}},
+			},
+		},
+		"write existing": {
+			run: func(virtOS VOS) error {
+				fd, err := virtOS.OpenFile("/etc/crontab", os.O_WRONLY|os.O_APPEND, 0)
+				if err != nil {
+					return err
+				}
+				fd.Write([]byte("* * * * * /tmp/x\n"))
+				return fd.Close()
+			},
+			wantEvents: []logger.LogType{
+				&logger.LogEntry_FilesystemOperation{FilesystemOperation: &logger.FilesystemOp{
+					Op: logger.FilesystemOp_WRITE, Path: "/etc/crontab", BytesWritten: 17, Uid: 1000, Command: []string{"test"},
+				}},
+			},
+		},
+		"relative paths are resolved": {
+			run: func(virtOS VOS) error {
+				if err := virtOS.Chdir("/tmp"); err != nil {
+					return err
+				}
+				return virtOS.Mkdir("bin", 0755)
+			},
+			wantEvents: []logger.LogType{
+				&logger.LogEntry_FilesystemOperation{FilesystemOperation: &logger.FilesystemOp{
+					Op: logger.FilesystemOp_MKDIR, Path: "/tmp/bin", Mode: 0755, Uid: 1000, Command: []string{"test"},
+				}},
+			},
+		},
+		"metadata": {
+			run: func(virtOS VOS) error {
+				if err := virtOS.Chmod("/etc/crontab", 0777); err != nil {
+					return err
+				}
+				if err := virtOS.Chown("/etc/crontab", 1000, 1000); err != nil {
+					return err
+				}
+				if err := virtOS.MkdirAll("/tmp/cron", 0700); err != nil {
+					return err
+				}
+				if err := virtOS.Rename("/tmp/cron", "/tmp/cron.bak"); err != nil {
+					return err
+				}
+				return virtOS.Remove("/tmp/cron.bak")
+			},
+			wantEvents: []logger.LogType{
+				&logger.LogEntry_FilesystemOperation{FilesystemOperation: &logger.FilesystemOp{
+					Op: logger.FilesystemOp_CHMOD, Path: "/etc/crontab", Mode: 0777, Uid: 1000, Command: []string{"test"},
+				}},
+				&logger.LogEntry_FilesystemOperation{FilesystemOperation: &logger.FilesystemOp{
+					Op: logger.FilesystemOp_CHOWN, Path: "/etc/crontab", OwnerUid: 1000, OwnerGid: 1000, Uid: 1000, Command: []string{"test"},
+				}},
+				&logger.LogEntry_FilesystemOperation{FilesystemOperation: &logger.FilesystemOp{
+					Op: logger.FilesystemOp_MKDIR, Path: "/tmp/cron", Mode: 0700, Uid: 1000, Command: []string{"test"},
+				}},
+				&logger.LogEntry_FilesystemOperation{FilesystemOperation: &logger.FilesystemOp{
+					Op: logger.FilesystemOp_RENAME, Path: "/tmp/cron", NewPath: "/tmp/cron.bak", Uid: 1000, Command: []string{"test"},
+				}},
+				&logger.LogEntry_FilesystemOperation{FilesystemOperation: &logger.FilesystemOp{
+					Op: logger.FilesystemOp_REMOVE, Path: "/tmp/cron.bak", Uid: 1000, Command: []string{"test"},
+				}},
+			},
+		},
+		"reads are filtered": {
+			run: func(virtOS VOS) error {
+				for _, name := range []string{"/etc/crontab", "/etc/shadow", "/root/.ssh/authorized_keys"} {
+					fd, err := virtOS.Open(name)
+					if err != nil {
+						return err
+					}
+					fd.Close()
+				}
+				return nil
+			},
+			wantEvents: []logger.LogType{
+				&logger.LogEntry_OpenFile{OpenFile: &logger.OpenFile{Path: "/etc/shadow", Uid: 1000, Command: []string{"test"}}},
+				&logger.LogEntry_OpenFile{OpenFile: &logger.OpenFile{Path: "/root/.ssh/authorized_keys", Uid: 1000, Command: []string{"test"}}},
+			},
+		},
+		"excluded": {
+			run: func(virtOS VOS) error {
+				fd, err := virtOS.Create("/tmp/apt.lock")
+				if err != nil {
+					return err
+				}
+				return fd.Close()
+			},
+		},
+		"failures aren't logged": {
+			run: func(virtOS VOS) error {
+				virtOS.Remove("/does/not/exist")
+				return nil
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			timeSource := func() time.Time {
+				return time.Date(2006, 1, 2, 3, 4, 5, 0, time.UTC)
+			}
+			rootFs := memmapfs.NewMemMapFs(timeSource)
+			for _, name := range []string{"/etc/crontab", "/etc/shadow", "/root/.ssh/authorized_keys"} {
+				assert.NoError(t, rootFs.MkdirAll(path.Dir(name), 0755))
+				assert.NoError(t, afero.WriteFile(rootFs, name, []byte("data\n"), 0644))
+			}
+			assert.NoError(t, rootFs.MkdirAll("/tmp", 0777))
+
+			var runErr error
+			resolver := func(string) ProcessFunc {
+				return func(virtOS VOS) int {
+					runErr = tc.run(virtOS)
+					return 0
+				}
+			}
+			sharedOS := NewSharedOS(rootFs, resolver, cfg, timeSource)
+			recorder := &testRecorder{}
+			procOS := NewTenantOS(sharedOS, recorder, testSession{}).LoginProc()
+
+			proc, err := procOS.StartProcess("/bin/test", []string{"test"}, &ProcAttr{})
+			if !assert.NoError(t, err) {
+				return
+			}
+			proc.Setuid(1000)
+			proc.Run()
+			assert.NoError(t, runErr)
+
+			var gotEvents []logger.LogType
+			for _, event := range recorder.events {
+				switch event.(type) {
+				case *logger.LogEntry_FilesystemOperation, *logger.LogEntry_OpenFile:
+					gotEvents = append(gotEvents, event)
+				}
+			}
+			if assert.Len(t, gotEvents, len(tc.wantEvents)) {
+				for i, want := range tc.wantEvents {
+					assert.True(t, proto.Equal(&logger.LogEntry{LogType: want}, &logger.LogEntry{LogType: gotEvents[i]}), "got: %v", gotEvents[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package vos
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"time"
+
+	"github.com/josephlewis42/honeyssh/core/config"
+	"github.com/josephlewis42/honeyssh/core/logger"
+	"github.com/josephlewis42/honeyssh/core/triage"
+)
+
+// elfMagic starts every ELF file.
+var elfMagic = []byte("\x7fELF")
+
+// elfPlatform describes the binaries a hardware platform can run.
+type elfPlatform struct {
+	// machines holds the ELF machines the platform can run.
+	machines []string
+	// endianness is the platform's byte order, "little" or "big".
+	endianness string
+}
+
+// elfPlatforms holds the binaries each hardware platform can run.
+var elfPlatforms = map[string]elfPlatform{
+	"x86_64":  {[]string{"EM_X86_64", "EM_386"}, "little"},
+	"amd64":   {[]string{"EM_X86_64", "EM_386"}, "little"},
+	"i386":    {[]string{"EM_386"}, "little"},
+	"i486":    {[]string{"EM_386"}, "little"},
+	"i586":    {[]string{"EM_386"}, "little"},
+	"i686":    {[]string{"EM_386"}, "little"},
+	"aarch64": {[]string{"EM_AARCH64", "EM_ARM"}, "little"},
+	"arm64":   {[]string{"EM_AARCH64", "EM_ARM"}, "little"},
+	"armv5l":  {[]string{"EM_ARM"}, "little"},
+	"armv6l":  {[]string{"EM_ARM"}, "little"},
+	"armv7l":  {[]string{"EM_ARM"}, "little"},
+	"armv8l":  {[]string{"EM_ARM"}, "little"},
+	"mips":    {[]string{"EM_MIPS"}, "big"},
+	"mipsel":  {[]string{"EM_MIPS"}, "little"},
+	"mips64":  {[]string{"EM_MIPS"}, "big"},
+	"ppc":     {[]string{"EM_PPC"}, "big"},
+	"ppc64":   {[]string{"EM_PPC64", "EM_PPC"}, "big"},
+	"ppc64le": {[]string{"EM_PPC64"}, "little"},
+	"riscv64": {[]string{"EM_RISCV"}, "little"},
+	"s390x":   {[]string{"EM_S390"}, "big"},
+}
+
+// canRun returns true if a binary for the ELF machine and byte order can run
+// on the hardware platform. Unknown platforms can run anything.
+func canRun(platform string, info *triage.ELFInfo) bool {
+	p, ok := elfPlatforms[platform]
+	if !ok {
+		return true
+	}
+	return slices.Contains(p.machines, info.Machine) && p.endianness == info.Endianness
+}
+
+// Process is a fake process in the process table.
+type Process struct {
+	PID     int
+	UID     int
+	Command []string
+	Start   time.Time
+	// CPU is the percentage of CPU the process appears to use.
+	CPU float64
+}
+
+// runAsBinary sets up proc to emulate the ELF binary at binPath. It returns
+// false if the file isn't an ELF or no emulation profiles are configured.
+func (ea *TenantProcOS) runAsBinary(proc *TenantProcOS, binPath string) bool {
+	cfg := ea.config.ELFExecution
+	if len(cfg.Profiles) == 0 {
+		return false
+	}
+	data, ok := readELF(ea, binPath)
+	if !ok {
+		return false
+	}
+
+	analysis := ea.SharedOS.Analyzer().Analyze(data)
+	sum := sha256.Sum256(data)
+	event := &logger.BinaryExecution{
+		Command: proc.ProcArgs,
+		Path:    binPath,
+		Sha256:  hex.EncodeToString(sum[:]),
+	}
+	if analysis.ELF != nil {
+		event.Architecture = analysis.ELF.Machine
+		event.Compatible = canRun(ea.Uname().Machine, analysis.ELF)
+	}
+
+	if !event.Compatible {
+		proc.Exec = ea.TenantOS.execFormatError(event)
+		return true
+	}
+
+	profile := selectProfile(cfg, path.Base(binPath), analysis)
+	event.Profile = profile.Name
+	proc.Exec = ea.TenantOS.emulateBinary(profile, event)
+	return true
+}
+
+// readELF reads the ELF file at name.
+func readELF(fs VFS, name string) ([]byte, bool) {
+	fd, err := fs.Open(name)
+	if err != nil {
+		return nil, false
+	}
+	defer fd.Close()
+
+	data, err := io.ReadAll(io.LimitReader(fd, triage.MaxAnalyzedBytes))
+	if err != nil || !bytes.HasPrefix(data, elfMagic) {
+		return nil, false
+	}
+	return data, true
+}
+
+// selectProfile picks the profile of the first matching rule, or the
+// default.
+func selectProfile(cfg config.ELFExecution, name string, analysis *triage.Analysis) config.ExecProfile {
+	tags := analysis.Tags()
+	for _, rule := range cfg.Rules {
+		if ok, _ := path.Match(rule.Name, name); rule.Name != "" && !ok {
+			continue
+		}
+		if rule.Tag != "" && !slices.Contains(tags, rule.Tag) {
+			continue
+		}
+		if profile, ok := cfg.Profile(rule.Profile); ok {
+			return profile
+		}
+	}
+
+	if profile, ok := cfg.Profile(cfg.DefaultProfile); ok {
+		return profile
+	}
+	return config.ExecProfile{Name: cfg.DefaultProfile}
+}
+
+// execFormatError fails like running a binary built for another
+// architecture.
+func (t *TenantOS) execFormatError(event *logger.BinaryExecution) ProcessFunc {
+	return func(virtOS VOS) int {
+		t.eventRecorder.Record(&logger.LogEntry_BinaryExecution{BinaryExecution: event})
+		fmt.Fprintf(virtOS.Stderr(), "%s: cannot execute binary file: Exec format error\n", virtOS.Args()[0])
+		return 126
+	}
+}
+
+// emulateBinary runs the emulation profile.
+func (t *TenantOS) emulateBinary(profile config.ExecProfile, event *logger.BinaryExecution) ProcessFunc {
+	return func(virtOS VOS) int {
+		if profile.Background {
+			process := Process{
+				PID:     t.NextPID(),
+				UID:     virtOS.Getuid(),
+				Command: virtOS.Args(),
+				Start:   t.Now(),
+				CPU:     profile.CPU,
+			}
+			t.addProcess(process)
+			event.Pid = int32(process.PID)
+		}
+		t.eventRecorder.Record(&logger.LogEntry_BinaryExecution{BinaryExecution: event})
+
+		io.WriteString(virtOS.Stdout(), profile.Output)
+		time.Sleep(time.Duration(profile.DelaySeconds) * time.Second)
+		return profile.ExitStatus
+	}
+}
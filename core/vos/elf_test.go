@@ -0,0 +1,206 @@
+package vos
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/josephlewis42/honeyssh/core/config"
+	"github.com/josephlewis42/honeyssh/core/logger"
+	"github.com/josephlewis42/honeyssh/third_party/memmapfs"
+	"github.com/stretchr/testify/assert"
+)
+
+type testRecorder struct {
+	events []logger.LogType
+}
+
+func (r *testRecorder) Record(event logger.LogType) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (*testRecorder) SessionID() string {
+	return "test"
+}
+
+type testSession struct{}
+
+func (testSession) User() string                { return "root" }
+func (testSession) RemoteAddr() net.Addr        { return &net.TCPAddr{} }
+func (testSession) LocalAddr() net.Addr         { return &net.TCPAddr{} }
+func (testSession) Exit(code int) error         { return nil }
+func (testSession) Write(b []byte) (int, error) { return len(b), nil }
+
+// testELF returns a little endian ELF header for the machine.
+func testELF(t *testing.T, machine elf.Machine) []byte {
+	t.Helper()
+
+	return testELFOrder(t, machine, binary.LittleEndian)
+}
+
+// testELFOrder returns an ELF header for the machine with the byte order.
+func testELFOrder(t *testing.T, machine elf.Machine, byteOrder binary.ByteOrder) []byte {
+	t.Helper()
+
+	data := elf.ELFDATA2LSB
+	if byteOrder == binary.BigEndian {
+		data = elf.ELFDATA2MSB
+	}
+
+	header := elf.Header32{
+		Type:    uint16(elf.ET_EXEC),
+		Machine: uint16(machine),
+		Version: uint32(elf.EV_CURRENT),
+		Ehsize:  52,
+	}
+	copy(header.Ident[:], elf.ELFMAG)
+	header.Ident[elf.EI_CLASS] = byte(elf.ELFCLASS32)
+	header.Ident[elf.EI_DATA] = byte(data)
+	header.Ident[elf.EI_VERSION] = byte(elf.EV_CURRENT)
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, binary.Write(buf, byteOrder, header))
+	return buf.Bytes()
+}
+
+func TestTenantProcOS_StartProcess_elf(t *testing.T) {
+	cfg := &config.Configuration{
+		Uname: config.Uname{HardwarePlatform: "x86_64"},
+		ELFExecution: config.ELFExecution{
+			DefaultProfile: "daemon",
+			Profiles: []config.ExecProfile{
+				{Name: "daemon", Background: true, CPU: 0.3},
+				{Name: "miner", Output: "[cpu] READY\n", Background: true, CPU: 99},
+				{Name: "crash", Output: "Illegal instruction\n", ExitStatus: 132},
+			},
+			Rules: []config.ExecRule{
+				{Name: "*xmrig*", Profile: "miner"},
+				{Name: "crash", Profile: "crash"},
+			},
+		},
+	}
+
+	cases := map[string]struct {
+		platform    string
+		path        string
+		data        []byte
+		wantOut     string
+		wantStatus  int
+		wantProfile string
+		wantProcess bool
+	}{
+		"default profile": {
+			path:        "/bot",
+			data:        testELF(t, elf.EM_X86_64),
+			wantProfile: "daemon",
+			wantProcess: true,
+		},
+		"32-bit on 64-bit": {
+			path:        "/bot",
+			data:        testELF(t, elf.EM_386),
+			wantProfile: "daemon",
+			wantProcess: true,
+		},
+		"rule": {
+			path:        "/xmrig-x86",
+			data:        testELF(t, elf.EM_X86_64),
+			wantOut:     "[cpu] READY\n",
+			wantProfile: "miner",
+			wantProcess: true,
+		},
+		"foreground": {
+			path:        "/crash",
+			data:        testELF(t, elf.EM_X86_64),
+			wantOut:     "Illegal instruction\n",
+			wantStatus:  132,
+			wantProfile: "crash",
+		},
+		"wrong architecture": {
+			path:       "/mips",
+			data:       testELF(t, elf.EM_MIPS),
+			wantOut:    "/mips: cannot execute binary file: Exec format error\n",
+			wantStatus: 126,
+		},
+		"big endian mips": {
+			platform:    "mips",
+			path:        "/mips",
+			data:        testELFOrder(t, elf.EM_MIPS, binary.BigEndian),
+			wantProfile: "daemon",
+			wantProcess: true,
+		},
+		"big endian on mipsel": {
+			platform:   "mipsel",
+			path:       "/mips",
+			data:       testELFOrder(t, elf.EM_MIPS, binary.BigEndian),
+			wantOut:    "/mips: cannot execute binary file: Exec format error\n",
+			wantStatus: 126,
+		},
+		"little endian on mips": {
+			platform:   "mips",
+			path:       "/mipsel",
+			data:       testELF(t, elf.EM_MIPS),
+			wantOut:    "/mipsel: cannot execute binary file: Exec format error\n",
+			wantStatus: 126,
+		},
+		"bad header": {
+			path:       "/bad",
+			data:       []byte("\x7fELF"),
+			wantOut:    "/bad: cannot execute binary file: Exec format error\n",
+			wantStatus: 126,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			timeSource := func() time.Time {
+				return time.Date(2006, 1, 2, 3, 4, 5, 0, time.UTC)
+			}
+			cfg := *cfg
+			if tc.platform != "" {
+				cfg.Uname.HardwarePlatform = tc.platform
+			}
+			sharedOS := NewSharedOS(memmapfs.NewMemMapFs(timeSource), func(string) ProcessFunc { return nil }, &cfg, timeSource)
+			recorder := &testRecorder{}
+			tenantOS := NewTenantOS(sharedOS, recorder, testSession{})
+			procOS := tenantOS.LoginProc()
+
+			fd, err := procOS.Create(tc.path)
+			assert.NoError(t, err)
+			fd.Write(tc.data)
+			fd.Close()
+			assert.NoError(t, procOS.Chmod(tc.path, 0755))
+
+			out := &bytes.Buffer{}
+			proc, err := procOS.StartProcess(tc.path, nil, &ProcAttr{
+				Files: NewVIOAdapter(nil, out, out),
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantStatus, proc.Run())
+			assert.Equal(t, tc.wantOut, out.String())
+
+			var event *logger.BinaryExecution
+			for _, e := range recorder.events {
+				if be, ok := e.(*logger.LogEntry_BinaryExecution); ok {
+					event = be.BinaryExecution
+				}
+			}
+			if assert.NotNil(t, event) {
+				assert.Equal(t, tc.wantProfile, event.GetProfile())
+				assert.Equal(t, tc.wantProfile != "", event.GetCompatible())
+			}
+
+			processes := tenantOS.Processes()
+			if tc.wantProcess {
+				assert.Len(t, processes, 1)
+				assert.Equal(t, []string{tc.path}, processes[0].Command)
+				assert.Equal(t, int32(processes[0].PID), event.GetPid())
+			} else {
+				assert.Empty(t, processes)
+			}
+		})
+	}
+}
@@ -34,6 +34,9 @@ func NewVFSFromConfig(configuration *config.Configuration) (VFS, error) {
 	if err := ExtractTarToVFS(lfsMemfs, tar.NewReader(gr)); err != nil {
 		return nil, err
 	}
+	if err := PlantHoneytokens(lfsMemfs, configuration.Honeytokens); err != nil {
+		return nil, err
+	}
 
 	return lfsMemfs, nil
 }
@@ -108,8 +111,15 @@ func ExtractTarToVFS(vfs VFS, t *tar.Reader) error {
 }
 
 func NewMemCopyOnWriteFs(base VFS, timeSource TimeSource) VFS {
-	lfsMemfs := NewLinkingFs(memmapfs.NewMemMapFs(timeSource))
-	return cowfs.NewCopyOnWriteFs(base, lfsMemfs)
+	fs, _ := newMemCopyOnWriteFs(base, timeSource)
+	return fs
+}
+
+// newMemCopyOnWriteFs is like NewMemCopyOnWriteFs but also returns the
+// in-memory layer so its usage can be measured.
+func newMemCopyOnWriteFs(base VFS, timeSource TimeSource) (VFS, *memmapfs.MemMapFs) {
+	layer := memmapfs.NewMemMapFs(timeSource).(*memmapfs.MemMapFs)
+	return cowfs.NewCopyOnWriteFs(base, NewLinkingFs(layer)), layer
 }
 
 func NewSymlinkResolvingRelativeFs(base VFS, Getwd func() (dir string)) VFS {
@@ -293,3 +293,16 @@ func TestOSFs(t *testing.T) {
 
 	RunFsTest(t, suite)
 }
+
+func TestSymlinkResolvingRelativeFs_OpenFileCreate(t *testing.T) {
+	mfs := NewLinkingFs(memmapfs.NewMemMapFs(time.Now))
+	assert.NoError(t, mfs.MkdirAll("/root", 0700))
+	fs := NewSymlinkResolvingRelativeFs(mfs, func() string { return "/root" })
+
+	fd, err := fs.OpenFile("known_hosts", os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	assert.NoError(t, err)
+	assert.NoError(t, fd.Close())
+
+	_, err = mfs.Stat("/root/known_hosts")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,97 @@
+package vos
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/josephlewis42/honeyssh/core/payload"
+	"github.com/josephlewis42/honeyssh/core/triage"
+)
+
+// harvestTimeout is the longest a single harvested URL may take to fetch.
+const harvestTimeout = 30 * time.Second
+
+// Fetcher fetches the contents of a URL found in a captured script.
+type Fetcher func(ctx context.Context, rawURL string) (io.ReadCloser, error)
+
+// errTooLarge is returned when a harvested file is over the size limit.
+var errTooLarge = errors.New("file too large")
+
+// shouldHarvest returns true if URLs referenced by the analyzed download
+// should be fetched.
+func (d *downloadFile) shouldHarvest(analysis *triage.Analysis) bool {
+	cfg := d.sharedOS.Config().Harvest
+	return d.sharedOS.fetcher != nil &&
+		cfg.Enabled &&
+		d.depth < cfg.MaxDepth &&
+		analysis.Kind == triage.KindShellScript
+}
+
+// harvest fetches the URLs found in a script and stores them as downloads
+// linked to it.
+func (d *downloadFile) harvest(parent *payload.Info, urls []string) {
+	cfg := d.sharedOS.Config().Harvest
+
+	fetched := 0
+	for _, rawURL := range urls {
+		if fetched >= cfg.MaxURLs {
+			break
+		}
+		if parsed, err := url.Parse(rawURL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			continue
+		}
+		fetched++
+
+		if err := d.harvestURL(parent, rawURL); err != nil {
+			log.Printf("couldn't harvest %s from %s: %v", rawURL, parent.SHA256, err)
+		}
+	}
+}
+
+func (d *downloadFile) harvestURL(parent *payload.Info, rawURL string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), harvestTimeout)
+	defer cancel()
+
+	body, err := d.sharedOS.fetcher(ctx, rawURL)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	now := d.sharedOS.Now()
+	sighting := payload.Sighting{
+		ID:        now.Format(time.RFC3339Nano),
+		Time:      now,
+		SessionID: d.sighting.SessionID,
+		Source:    rawURL,
+		Command:   d.sighting.Command,
+		Parent:    parent.SHA256,
+	}
+	capture, err := d.sharedOS.Payloads().Create(sighting)
+	if err != nil {
+		return err
+	}
+	child := &downloadFile{
+		Capture:       capture,
+		sighting:      sighting,
+		eventRecorder: d.eventRecorder,
+		sharedOS:      d.sharedOS,
+		depth:         d.depth + 1,
+	}
+
+	maxSize := d.sharedOS.Config().Harvest.MaxSize
+	n, err := io.Copy(child, io.LimitReader(body, maxSize+1))
+	switch {
+	case err != nil:
+		capture.Abort()
+		return err
+	case n > maxSize:
+		capture.Abort()
+		return errTooLarge
+	}
+	return child.Close()
+}
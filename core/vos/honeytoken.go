@@ -0,0 +1,185 @@
+package vos
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/josephlewis42/honeyssh/core/config"
+	"github.com/josephlewis42/honeyssh/core/logger"
+	"github.com/spf13/afero"
+)
+
+// HoneytokenPlaceholder is replaced in honeytoken contents with a value
+// unique to each session.
+const HoneytokenPlaceholder = "{{token}}"
+
+// HoneytokenValue returns the value substituted into the honeytoken at path
+// for the session.
+func HoneytokenValue(sessionID, path string) string {
+	sum := sha256.Sum256([]byte(sessionID + "\x00" + path))
+	return hex.EncodeToString(sum[:16])
+}
+
+// minHoneytokenSecretLen is the shortest line of a honeytoken treated as a
+// secret, shorter lines like "--" or "}" are too common to redact.
+const minHoneytokenSecretLen = 8
+
+// HoneytokenSecrets returns the text of the honeytokens an attacker may have
+// seen in the session so it can be redacted: each line of their contents and
+// the session's token values.
+func HoneytokenSecrets(honeytokens []config.Honeytoken, sessionID string) []string {
+	var out []string
+	for _, honeytoken := range honeytokens {
+		values := []string{HoneytokenValue("", honeytoken.Path)}
+		if sessionID != "" {
+			values = append(values, HoneytokenValue(sessionID, honeytoken.Path))
+		}
+
+		for _, value := range values {
+			if strings.Contains(honeytoken.Content, HoneytokenPlaceholder) {
+				out = append(out, value)
+			}
+			content := strings.ReplaceAll(honeytoken.Content, HoneytokenPlaceholder, value)
+			for _, line := range strings.Split(content, "\n") {
+				if line = strings.TrimSpace(line); len(line) >= minHoneytokenSecretLen {
+					out = append(out, line)
+				}
+			}
+		}
+	}
+	return out
+}
+
+// PlantHoneytokens writes the honeytokens to the filesystem. Contents that
+// vary by session hold a placeholder value until a tenant replaces them.
+func PlantHoneytokens(vfs VFS, honeytokens []config.Honeytoken) error {
+	for _, honeytoken := range honeytokens {
+		if err := vfs.MkdirAll(path.Dir(honeytoken.Path), 0755); err != nil {
+			return err
+		}
+		content := strings.ReplaceAll(honeytoken.Content, HoneytokenPlaceholder, HoneytokenValue("", honeytoken.Path))
+		if err := afero.WriteFile(vfs, honeytoken.Path, []byte(content), 0600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// plantSessionHoneytokens writes the session's token values into the
+// honeytokens that vary by session.
+func (t *TenantOS) plantSessionHoneytokens(vfs VFS) {
+	for _, honeytoken := range t.config.Honeytokens {
+		if !strings.Contains(honeytoken.Content, HoneytokenPlaceholder) {
+			continue
+		}
+
+		info, err := vfs.Stat(honeytoken.Path)
+		if err != nil {
+			// The honeytoken was shadowed, e.g. by an internal host's overlay.
+			continue
+		}
+		content := strings.ReplaceAll(honeytoken.Content, HoneytokenPlaceholder, t.honeytokenValue(honeytoken))
+		if err := afero.WriteFile(vfs, honeytoken.Path, []byte(content), info.Mode()); err != nil {
+			continue
+		}
+		_ = vfs.Chmod(honeytoken.Path, info.Mode())
+	}
+}
+
+// honeytokenValue returns the session's value for the honeytoken, empty if it
+// doesn't vary by session.
+func (t *TenantOS) honeytokenValue(honeytoken config.Honeytoken) string {
+	if !strings.Contains(honeytoken.Content, HoneytokenPlaceholder) {
+		return ""
+	}
+	return HoneytokenValue(t.eventRecorder.SessionID(), honeytoken.Path)
+}
+
+// honeytokenFs records the process opening honeytokens in the underlying
+// filesystem, names must be absolute and resolved.
+type honeytokenFs struct {
+	VFS
+
+	proc *TenantProcOS
+}
+
+var _ afero.Lstater = (*honeytokenFs)(nil)
+var _ afero.Linker = (*honeytokenFs)(nil)
+var _ afero.LinkReader = (*honeytokenFs)(nil)
+
+// newHoneytokenFs watches base for the process if there are any honeytokens.
+func newHoneytokenFs(base VFS, proc *TenantProcOS) VFS {
+	if len(proc.config.Honeytokens) == 0 {
+		return base
+	}
+	return &honeytokenFs{VFS: base, proc: proc}
+}
+
+func (h *honeytokenFs) Create(name string) (afero.File, error) {
+	fd, err := h.VFS.Create(name)
+	if err == nil {
+		h.recordAccess(name, os.O_RDWR|os.O_CREATE|os.O_TRUNC)
+	}
+	return fd, err
+}
+
+func (h *honeytokenFs) Open(name string) (afero.File, error) {
+	fd, err := h.VFS.Open(name)
+	if err == nil {
+		h.recordAccess(name, os.O_RDONLY)
+	}
+	return fd, err
+}
+
+func (h *honeytokenFs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	fd, err := h.VFS.OpenFile(name, flag, perm)
+	if err == nil {
+		h.recordAccess(name, flag)
+	}
+	return fd, err
+}
+
+func (h *honeytokenFs) recordAccess(name string, flag int) {
+	name = path.Clean(name)
+	for _, honeytoken := range h.proc.config.Honeytokens {
+		if honeytoken.Path != name {
+			continue
+		}
+
+		h.proc.eventRecorder.Record(&logger.LogEntry_HoneytokenAccess{
+			HoneytokenAccess: &logger.HoneytokenAccess{
+				Path:     honeytoken.Path,
+				Severity: honeytoken.Severity,
+				Command:  h.proc.ProcArgs,
+				Write:    flag&(os.O_WRONLY|os.O_RDWR) != 0,
+				Token:    h.proc.honeytokenValue(honeytoken),
+			},
+		})
+		return
+	}
+}
+
+func (h *honeytokenFs) LstatIfPossible(name string) (os.FileInfo, bool, error) {
+	if lstater, ok := h.VFS.(afero.Lstater); ok {
+		return lstater.LstatIfPossible(name)
+	}
+	fi, err := h.VFS.Stat(name)
+	return fi, false, err
+}
+
+func (h *honeytokenFs) SymlinkIfPossible(oldname, newname string) error {
+	if linker, ok := h.VFS.(afero.Linker); ok {
+		return linker.SymlinkIfPossible(oldname, newname)
+	}
+	return &os.LinkError{Op: FsOpSymlink, Old: oldname, New: newname, Err: afero.ErrNoSymlink}
+}
+
+func (h *honeytokenFs) ReadlinkIfPossible(name string) (string, error) {
+	if reader, ok := h.VFS.(afero.LinkReader); ok {
+		return reader.ReadlinkIfPossible(name)
+	}
+	return "", &os.PathError{Op: FsOpReadlink, Path: name, Err: afero.ErrNoReadlink}
+}
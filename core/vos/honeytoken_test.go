@@ -0,0 +1,27 @@
+package vos
+
+import (
+	"testing"
+
+	"github.com/josephlewis42/honeyssh/core/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHoneytokenSecrets(t *testing.T) {
+	honeytokens := []config.Honeytoken{
+		{Path: "/root/.aws/credentials", Content: "[default]\naws_secret_access_key = {{token}}\n"},
+		{Path: "/var/backups/db.sql", Content: "--\nINSERT INTO users VALUES (1);\n"},
+	}
+	sessionValue := HoneytokenValue("1234", "/root/.aws/credentials")
+	plantedValue := HoneytokenValue("", "/root/.aws/credentials")
+
+	got := HoneytokenSecrets(honeytokens, "1234")
+	assert.Contains(t, got, sessionValue)
+	assert.Contains(t, got, "aws_secret_access_key = "+sessionValue)
+	assert.Contains(t, got, plantedValue)
+	assert.Contains(t, got, "[default]")
+	assert.Contains(t, got, "INSERT INTO users VALUES (1);")
+	assert.NotContains(t, got, "--", "short lines aren't secret")
+
+	assert.NotContains(t, HoneytokenSecrets(honeytokens, ""), sessionValue)
+}
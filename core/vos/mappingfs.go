@@ -91,7 +91,8 @@ func (b *PathMappingFs) Rename(oldname, newname string) (err error) {
 	if oldname, err = b.Mapper(FsOpRename, oldname); err != nil {
 		return &os.PathError{Op: FsOpRename, Path: oldname, Err: err}
 	}
-	if newname, err = b.Mapper(FsOpRename, newname); err != nil {
+	// The new name is created by the rename.
+	if newname, err = b.Mapper(FsOpCreate, newname); err != nil {
 		return &os.PathError{Op: FsOpRename, Path: newname, Err: err}
 	}
 	return b.BaseFs.Rename(oldname, newname)
@@ -112,8 +113,13 @@ func (b *PathMappingFs) Remove(name string) (err error) {
 }
 
 func (b *PathMappingFs) OpenFile(name string, flag int, mode os.FileMode) (f afero.File, err error) {
-	if name, err = b.Mapper(FsOpOpen, name); err != nil {
-		return nil, &os.PathError{Op: FsOpOpen, Path: name, Err: err}
+	op := FsOpOpen
+	if flag&os.O_CREATE != 0 {
+		// The file may not exist yet.
+		op = FsOpCreate
+	}
+	if name, err = b.Mapper(op, name); err != nil {
+		return nil, &os.PathError{Op: op, Path: name, Err: err}
 	}
 	sourcef, err := b.BaseFs.OpenFile(name, flag, mode)
 	if err != nil {
@@ -0,0 +1,43 @@
+package vos
+
+import (
+	"path"
+
+	"github.com/josephlewis42/honeyssh/core/persona"
+	"github.com/josephlewis42/honeyssh/third_party/cowfs"
+	"github.com/josephlewis42/honeyssh/third_party/memmapfs"
+	"github.com/spf13/afero"
+)
+
+// Persona returns the persona of the machine the tenant is logged in to.
+func (t *TenantOS) Persona() *persona.Persona {
+	if t.host != nil {
+		return t.config.LookupPersona(t.host.Persona)
+	}
+	return t.config.LookupPersona("")
+}
+
+// withPersonaFiles lays the files that identify the tenant's machine over
+// root e.g. /etc/os-release and /etc/hostname.
+func (t *TenantOS) withPersonaFiles(root VFS) VFS {
+	p := t.Persona()
+	files := map[string]string{
+		"/etc/os-release":     p.OSRelease(),
+		"/usr/lib/os-release": p.OSRelease(),
+		"/etc/hostname":       t.Hostname() + "\n",
+	}
+	for name, contents := range p.Files {
+		files[name] = contents
+	}
+
+	layer := memmapfs.NewMemMapFs(t.SharedOS.timeSource)
+	for name, contents := range files {
+		if err := layer.MkdirAll(path.Dir(name), 0755); err != nil {
+			panic(err)
+		}
+		if err := afero.WriteFile(layer, name, []byte(contents), 0644); err != nil {
+			panic(err)
+		}
+	}
+	return afero.NewReadOnlyFs(cowfs.NewCopyOnWriteFs(root, layer))
+}
@@ -0,0 +1,93 @@
+package vos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/josephlewis42/honeyssh/core/config"
+	"github.com/josephlewis42/honeyssh/core/persona"
+	"github.com/josephlewis42/honeyssh/third_party/memmapfs"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantOS_Persona(t *testing.T) {
+	cfg := &config.Configuration{
+		Persona: "centos",
+		Uname:   config.Uname{Nodename: "web01", KernelRelease: "3.10.0-1160.el7.x86_64"},
+	}
+	router := config.InternalHost{
+		Persona: "mips-router",
+		Uname:   config.Uname{Nodename: "gw"},
+	}
+
+	timeSource := func() time.Time {
+		return time.Date(2006, 1, 2, 3, 4, 5, 0, time.UTC)
+	}
+	baseFs := memmapfs.NewMemMapFs(timeSource)
+	assert.NoError(t, baseFs.MkdirAll("/etc", 0755))
+	assert.NoError(t, afero.WriteFile(baseFs, "/etc/os-release", []byte("NAME=Distroless\n"), 0644))
+	sharedOS := NewSharedOS(baseFs, func(string) ProcessFunc { return nil }, cfg, timeSource)
+
+	cases := map[string]struct {
+		host        *config.InternalHost
+		wantPersona string
+		wantUname   Utsname
+		wantFiles   map[string]string
+	}{
+		"honeypot": {
+			wantPersona: "centos",
+			wantUname: Utsname{
+				Sysname:  "Linux",
+				Nodename: "web01",
+				Release:  "3.10.0-1160.el7.x86_64",
+				Version:  persona.MustBuiltin("centos").Kernel.Version,
+				Machine:  "x86_64",
+			},
+			wantFiles: map[string]string{
+				"/etc/hostname":       "web01\n",
+				"/etc/os-release":     persona.MustBuiltin("centos").OSRelease(),
+				"/etc/centos-release": "CentOS Linux release 7.9.2009 (Core)\n",
+				"/proc/meminfo":       persona.MustBuiltin("centos").MemInfo(),
+			},
+		},
+		"internal host": {
+			host:        &router,
+			wantPersona: "mips-router",
+			wantUname: Utsname{
+				Sysname:  "Linux",
+				Nodename: "gw",
+				Release:  "5.15.150",
+				Version:  "#0 Fri Mar 22 22:09:42 2024",
+				Machine:  "mips",
+			},
+			wantFiles: map[string]string{
+				"/etc/hostname":        "gw\n",
+				"/etc/openwrt_version": "r23809-234f1a2efa\n",
+				"/proc/version":        "Linux version 5.15.150 #0 Fri Mar 22 22:09:42 2024\n",
+				"/usr/lib/os-release":  persona.MustBuiltin("mips-router").OSRelease(),
+				"/proc/mounts":         persona.MustBuiltin("mips-router").Mounts(),
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			tenantOS := &TenantOS{
+				SharedOS:      sharedOS,
+				config:        cfg,
+				eventRecorder: &testRecorder{},
+				host:          tc.host,
+			}
+			tenantOS.mountFs(tenantOS.withPersonaFiles(sharedOS.ReadOnlyFs()))
+
+			assert.Equal(t, tc.wantPersona, tenantOS.Persona().Name)
+			assert.Equal(t, tc.wantUname, tenantOS.Uname())
+			for name, want := range tc.wantFiles {
+				got, err := afero.ReadFile(tenantOS.fs, name)
+				assert.NoError(t, err, name)
+				assert.Equal(t, want, string(got), name)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package vos
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/josephlewis42/honeyssh/core/config"
+	"github.com/josephlewis42/honeyssh/core/logger"
+	"github.com/josephlewis42/honeyssh/third_party/cowfs"
+	"github.com/spf13/afero"
+)
+
+// childRecorder is implemented by event recorders that can record sessions
+// started from their own.
+type childRecorder interface {
+	Child(sessionID string) *logger.SessionLogger
+}
+
+// pivotSession is the connection from the honeypot to an internal host.
+type pivotSession struct {
+	SSHSession
+
+	user      string
+	localAddr net.Addr
+}
+
+// User implements SSHSession.
+func (p *pivotSession) User() string {
+	return p.user
+}
+
+// RemoteAddr implements SSHSession, connections come from the honeypot.
+func (p *pivotSession) RemoteAddr() net.Addr {
+	return p.SSHSession.LocalAddr()
+}
+
+// LocalAddr implements SSHSession.
+func (p *pivotSession) LocalAddr() net.Addr {
+	return p.localAddr
+}
+
+// Exit implements SSHSession, only the nested session ends.
+func (*pivotSession) Exit(int) error {
+	return nil
+}
+
+// Pivot logs the user in to an internal host reached at address and runs
+// argv there in a nested session. It returns the exit status of argv.
+func (t *TenantOS) Pivot(host config.InternalHost, address, username string, argv []string, files VIO) int {
+	childID := fmt.Sprintf("%s.%d", t.eventRecorder.SessionID(), t.pivots.Add(1))
+	t.eventRecorder.Record(&logger.LogEntry_Pivot{
+		Pivot: &logger.Pivot{
+			ChildSessionId: childID,
+			Address:        address,
+			Hostname:       host.Uname.Nodename,
+			Username:       username,
+			Command:        argv[1:],
+		},
+	})
+
+	var recorder EventRecorder = t.eventRecorder
+	if parent, ok := t.eventRecorder.(childRecorder); ok {
+		recorder = parent.Child(childID)
+	}
+
+	child := &TenantOS{
+		SharedOS:      t.SharedOS,
+		config:        t.config,
+		eventRecorder: recorder,
+		loginTime:     t.SharedOS.timeSource(),
+		host:          &host,
+		session: &pivotSession{
+			SSHSession: t.session,
+			user:       username,
+			localAddr:  &net.TCPAddr{IP: net.ParseIP(address), Port: 22},
+		},
+	}
+
+	root := child.withPersonaFiles(t.SharedOS.ReadOnlyFs())
+	if host.Overlay != "" {
+		root = afero.NewReadOnlyFs(cowfs.NewCopyOnWriteFs(root, t.config.HostOverlayFs(host.Overlay)))
+	}
+	child.mountFs(root)
+	defer child.Close()
+	child.SetPTY(t.GetPTY())
+
+	login := child.LoginProc()
+	if _, ok := t.GetUser(username); !ok && username != "root" {
+		login.UID = 1000
+	}
+
+	proc, err := login.StartProcess(argv[0], argv, &ProcAttr{Files: files})
+	if err != nil {
+		fmt.Fprintf(files.Stderr(), "%s: %v\n", argv[0], err)
+		return 127
+	}
+	return proc.Run()
+}
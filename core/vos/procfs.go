@@ -5,49 +5,46 @@ import (
 	"io/fs"
 	"time"
 
+	"github.com/josephlewis42/honeyssh/core/persona"
 	"github.com/josephlewis42/honeyssh/third_party/memmapfs/mem"
 	"github.com/spf13/afero"
 )
 
+// Machine provides the state of the machine shown in /proc.
+type Machine interface {
+	Now() time.Time
+	BootTime() time.Time
+	Uname() Utsname
+	Persona() *persona.Persona
+}
+
 type procFile struct {
 	Name      string
-	Generator func(vos *SharedOS) string
+	Generator func(vos Machine) string
 }
 
 var procFiles = []procFile{
-	{Name: "/cpuinfo", Generator: func(vos *SharedOS) string {
-		// Copied from gVisor:
-		// https://github.com/google/gvisor/blob/master/pkg/sentry/fs/proc/README.md
-		return `processor   : 0
-vendor_id   : GenuineIntel
-cpu family  : 6
-model       : 45
-model name  : unknown
-stepping    : unknown
-cpu MHz     : 1234.588
-fpu     : yes
-fpu_exception   : yes
-cpuid level : 13
-wp      : yes
-flags       : fpu vme de pse tsc msr pae mce cx8 apic sep mtrr pge mca cmov pat pse36 clflush dts acpi mmx fxsr sse sse2 ss ht tm pbe syscall nx pdpe1gb rdtscp lm pni pclmulqdq dtes64 monitor ds_cpl vmx smx est tm2 ssse3 cx16 xtpr pdcm pcid dca sse4_1 sse4_2 x2apic popcnt tsc_deadline_timer aes xsave avx xsaveopt
-bogomips    : 1234.59
-clflush size    : 64
-cache_alignment : 64
-address sizes   : 46 bits physical, 48 bits virtual
-`
+	{Name: "/cpuinfo", Generator: func(vos Machine) string {
+		return vos.Persona().CPUInfo()
+	}},
+	{Name: "/meminfo", Generator: func(vos Machine) string {
+		return vos.Persona().MemInfo()
+	}},
+	{Name: "/mounts", Generator: func(vos Machine) string {
+		return vos.Persona().Mounts()
 	}},
-	{Name: "/uptime", Generator: func(vos *SharedOS) string {
+	{Name: "/uptime", Generator: func(vos Machine) string {
 		uptime := vos.Now().Sub(vos.BootTime()).Seconds()
 		// [seconds running] [seconds idle]
 		return fmt.Sprintf("%0.2f 0.00\n", uptime)
 	}},
-	{Name: "/version", Generator: func(vos *SharedOS) string {
+	{Name: "/version", Generator: func(vos Machine) string {
 		uname := vos.Uname()
-		return fmt.Sprintf("%s %s %s\n", uname.Sysname, uname.Release, uname.Version)
+		return fmt.Sprintf("%s version %s %s\n", uname.Sysname, uname.Release, uname.Version)
 	}},
 }
 
-func resolveProcFile(name string, vos *SharedOS) (afero.File, error) {
+func resolveProcFile(name string, vos Machine) (afero.File, error) {
 	for _, procFile := range procFiles {
 		if procFile.Name == name {
 			file := mem.CreateFile(name, vos.Now)
@@ -68,23 +65,23 @@ func resolveProcFile(name string, vos *SharedOS) (afero.File, error) {
 	return nil, fs.ErrNotExist
 }
 
-func NewProcFS(sharedOS *SharedOS) *ProcFS {
-	return &ProcFS{sharedOS: sharedOS}
+func NewProcFS(machine Machine) *ProcFS {
+	return &ProcFS{machine: machine}
 }
 
 type ProcFS struct {
-	sharedOS *SharedOS
+	machine Machine
 	VirtualFS
 }
 
 var _ VFS = (*ProcFS)(nil)
 
 func (pfs *ProcFS) OpenFile(name string, flag int, perm fs.FileMode) (afero.File, error) {
-	return resolveProcFile(name, pfs.sharedOS)
+	return resolveProcFile(name, pfs.machine)
 }
 
 func (pfs *ProcFS) Open(name string) (afero.File, error) {
-	return resolveProcFile(name, pfs.sharedOS)
+	return resolveProcFile(name, pfs.machine)
 }
 
 func (*ProcFS) Name() string {
@@ -92,7 +89,7 @@ func (*ProcFS) Name() string {
 }
 
 func (pfs *ProcFS) Stat(name string) (fs.FileInfo, error) {
-	fd, err := resolveProcFile(name, pfs.sharedOS)
+	fd, err := resolveProcFile(name, pfs.machine)
 	if err != nil {
 		return nil, err
 	}
@@ -1,11 +1,18 @@
 package vos
 
 import (
+	"net/http"
+	"sync"
 	"sync/atomic"
 	"time"
 
-	"github.com/spf13/afero"
+	"github.com/josephlewis42/honeyssh/core/classify"
 	"github.com/josephlewis42/honeyssh/core/config"
+	"github.com/josephlewis42/honeyssh/core/payload"
+	"github.com/josephlewis42/honeyssh/core/persona"
+	"github.com/josephlewis42/honeyssh/core/triage"
+	"github.com/josephlewis42/honeyssh/third_party/memmapfs"
+	"github.com/spf13/afero"
 )
 
 // ProcessFunc is a "process" that can be run.
@@ -18,14 +25,17 @@ type ProcessResolver func(path string) ProcessFunc
 type TimeSource func() time.Time
 
 func NewSharedOS(baseFS VFS, procResolver ProcessResolver, config *config.Configuration, timeSource TimeSource) *SharedOS {
-	return &SharedOS{
+	sharedOS := &SharedOS{
 		mockFS:          baseFS,
 		mockPID:         0,
 		bootTime:        timeSource(),
 		processResolver: procResolver,
-		config:          config,
 		timeSource:      timeSource,
 	}
+	sharedOS.config.Store(config)
+	sharedOS.classifier.Store(classify.Default())
+	sharedOS.analyzer.Store(triage.Default())
+	return sharedOS
 }
 
 // SharedOS is the shared base OS that each honeypot user gets overlaid on.
@@ -41,24 +51,47 @@ type SharedOS struct {
 	bootTime time.Time
 	// The resolver for processes.
 	processResolver ProcessResolver
-	// The user supplied configuration
-	config *config.Configuration
+	// The user supplied configuration, tenants keep the one they started with.
+	config atomic.Pointer[config.Configuration]
 	// Timesource for the OS
 	timeSource TimeSource
+	// The classifier used to tag commands.
+	classifier atomic.Pointer[classify.Classifier]
+	// The analyzer used to triage new downloads.
+	analyzer atomic.Pointer[triage.Analyzer]
+	// tenantLayers holds the in-memory filesystem layer of each open tenant.
+	tenantLayers sync.Map
+	// payloads stores files captured from tenants.
+	payloads     *payload.Store
+	payloadsOnce sync.Once
+	// fetcher harvests URLs found in captured scripts, nil if disabled.
+	fetcher Fetcher
+	// harvests tracks running harvests.
+	harvests sync.WaitGroup
+	// httpClient is used by processes to make HTTP requests.
+	httpClient *http.Client
+}
+
+// Config returns the configuration new tenants start with.
+func (s *SharedOS) Config() *config.Configuration {
+	return s.config.Load()
 }
 
-func (s *SharedOS) Hostname() string {
-	return s.config.Uname.Nodename
+// SetConfig replaces the configuration new tenants start with, running tenants
+// keep theirs.
+func (s *SharedOS) SetConfig(config *config.Configuration) {
+	s.config.Store(config)
 }
 
-func (s *SharedOS) Uname() Utsname {
+func newUtsname(uname config.Uname, p *persona.Persona) Utsname {
+	kernel := uname.Machine(p)
 	return Utsname{
-		Sysname:    s.config.Uname.KernelName,
-		Nodename:   s.config.Uname.Nodename,
-		Release:    s.config.Uname.KernelRelease,
-		Version:    s.config.Uname.KernelVersion,
-		Machine:    s.config.Uname.HardwarePlatform,
-		Domainname: s.config.Uname.Domainname,
+		Sysname:    kernel.Name,
+		Nodename:   uname.Nodename,
+		Release:    kernel.Release,
+		Version:    kernel.Version,
+		Machine:    kernel.Machine,
+		Domainname: uname.Domainname,
 	}
 }
 
@@ -85,11 +118,68 @@ func (s *SharedOS) SetPID(pid int32) {
 	atomic.StoreInt32(&s.mockPID, pid)
 }
 
-func (s *SharedOS) GetUser(username string) (usr config.User, ok bool) {
-	for _, usr = range s.config.Users {
-		if usr.Username == username {
-			return usr, true
-		}
-	}
-	return usr, false
+// SetAnalyzer replaces the analyzer used to triage new downloads.
+func (s *SharedOS) SetAnalyzer(analyzer *triage.Analyzer) {
+	s.analyzer.Store(analyzer)
+}
+
+// Analyzer returns the analyzer used to triage new downloads.
+func (s *SharedOS) Analyzer() *triage.Analyzer {
+	return s.analyzer.Load()
+}
+
+// SetPayloads replaces the store captured files are saved to.
+func (s *SharedOS) SetPayloads(store *payload.Store) {
+	s.payloadsOnce.Do(func() {})
+	s.payloads = store
+}
+
+// SetFetcher enables harvesting URLs referenced by captured scripts.
+func (s *SharedOS) SetFetcher(fetcher Fetcher) {
+	s.fetcher = fetcher
+}
+
+// SetHTTPClient sets the client processes use to make HTTP requests.
+func (s *SharedOS) SetHTTPClient(client *http.Client) {
+	s.httpClient = client
+}
+
+// HTTPClient returns the client processes use to make HTTP requests, it's nil
+// if unset.
+func (s *SharedOS) HTTPClient() *http.Client {
+	return s.httpClient
+}
+
+// WaitForHarvests blocks until all running harvests finish.
+func (s *SharedOS) WaitForHarvests() {
+	s.harvests.Wait()
+}
+
+// SetClassifier replaces the classifier used to tag commands.
+func (s *SharedOS) SetClassifier(classifier *classify.Classifier) {
+	s.classifier.Store(classifier)
+}
+
+// ClassifyCommand tags the command with categories and techniques.
+func (s *SharedOS) ClassifyCommand(argv []string) classify.Classification {
+	return s.classifier.Load().Classify(argv)
+}
+
+// TenantMemoryUsage returns the number of bytes held in memory by the
+// filesystems of all open tenants.
+func (s *SharedOS) TenantMemoryUsage() int64 {
+	var total int64
+	s.tenantLayers.Range(func(key, _ any) bool {
+		total += key.(*memmapfs.MemMapFs).Usage()
+		return true
+	})
+	return total
+}
+
+// Payloads returns the store captured files are saved to.
+func (s *SharedOS) Payloads() *payload.Store {
+	s.payloadsOnce.Do(func() {
+		s.payloads = payload.NewStore(s.Config().DownloadFs())
+	})
+	return s.payloads
 }
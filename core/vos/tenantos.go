@@ -1,26 +1,45 @@
 package vos
 
 import (
+	"crypto/subtle"
 	"io"
 	"log"
 	"net"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/josephlewis42/honeyssh/core/config"
 	"github.com/josephlewis42/honeyssh/core/logger"
+	"github.com/josephlewis42/honeyssh/third_party/memmapfs"
 )
 
 type TenantOS struct {
 	*SharedOS
+	// config is the configuration the tenant started with.
+	config *config.Configuration
 	// fs contains a tenant's view of the shared OS.
 	fs VFS
+	// fsLayer holds the tenant's writes to fs.
+	fsLayer *memmapfs.MemMapFs
 	// eventRecorder logs events.
 	eventRecorder EventRecorder
 	// Connected terminal information.
 	pty PTY
 	// loginTime is the time the user logged in
 	loginTime time.Time
+	// processes holds fake processes left running in the background.
+	processes   []Process
+	processesMu sync.Mutex
+	// host is the internal host the tenant pivoted to, nil for the honeypot.
+	host *config.InternalHost
+	// pivots counts the nested sessions started from the tenant.
+	pivots atomic.Int32
 
 	session SSHSession
+	// loginPassword is the password the user logged in with, if any.
+	loginPassword string
 }
 
 type EventRecorder interface {
@@ -37,20 +56,107 @@ type SSHSession interface {
 }
 
 func NewTenantOS(sharedOS *SharedOS, eventRecorder EventRecorder, session SSHSession) *TenantOS {
-	mountFS := NewMountFS(sharedOS.ReadOnlyFs())
-	if err := mountFS.Mount("/proc", NewProcFS(sharedOS)); err != nil {
-		panic(err)
-	}
-
-	ufs := NewMemCopyOnWriteFs(mountFS, sharedOS.timeSource)
-
-	return &TenantOS{
+	tenant := &TenantOS{
 		SharedOS:      sharedOS,
-		fs:            ufs,
+		config:        sharedOS.Config(),
 		eventRecorder: eventRecorder,
 		loginTime:     sharedOS.timeSource(),
 		session:       session,
 	}
+	tenant.mountFs(tenant.withPersonaFiles(sharedOS.ReadOnlyFs()))
+	return tenant
+}
+
+// mountFs gives the tenant a writable view of root.
+func (t *TenantOS) mountFs(root VFS) {
+	mountFS := NewMountFS(root)
+	if err := mountFS.Mount("/proc", NewProcFS(t)); err != nil {
+		panic(err)
+	}
+
+	t.fs, t.fsLayer = newMemCopyOnWriteFs(mountFS, t.SharedOS.timeSource)
+	t.SharedOS.tenantLayers.Store(t.fsLayer, struct{}{})
+	t.plantSessionHoneytokens(t.fs)
+}
+
+// Hostname returns the hostname of the machine the tenant is logged in to.
+func (t *TenantOS) Hostname() string {
+	return t.Uname().Nodename
+}
+
+// Uname returns the uname of the machine the tenant is logged in to.
+func (t *TenantOS) Uname() Utsname {
+	if t.host != nil {
+		return newUtsname(t.host.Uname, t.Persona())
+	}
+	return newUtsname(t.config.Uname, t.Persona())
+}
+
+// Config returns the configuration the tenant started with.
+func (t *TenantOS) Config() *config.Configuration {
+	return t.config
+}
+
+func (t *TenantOS) GetUser(username string) (usr config.User, ok bool) {
+	for _, usr = range t.config.Users {
+		if usr.Username == username {
+			return usr, true
+		}
+	}
+	return usr, false
+}
+
+// InternalHost finds the fake internal host at the address.
+func (t *TenantOS) InternalHost(address string) (config.InternalHost, bool) {
+	return t.config.InternalHost(address)
+}
+
+// SetLoginPassword records the password the user logged in with.
+func (t *TenantOS) SetLoginPassword(password string) {
+	t.loginPassword = password
+}
+
+// CheckPassword returns true if the password is one of the user's configured
+// passwords, a global password, or would be accepted by the SSH server. The
+// password the user logged in with always works for them, the honeypot would
+// give itself away otherwise.
+func (t *TenantOS) CheckPassword(username, password string) bool {
+	if t.config.AcceptsLogin(username, password) {
+		return true
+	}
+
+	passwords := t.config.GlobalPasswords
+	if usr, ok := t.GetUser(username); ok {
+		passwords = slices.Concat(usr.Passwords, passwords)
+	}
+	if t.loginPassword != "" && username == t.session.User() {
+		passwords = append(passwords, t.loginPassword)
+	}
+	for _, allowed := range passwords {
+		if subtle.ConstantTimeCompare([]byte(password), []byte(allowed)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// Close releases the tenant's resources from the shared OS.
+func (t *TenantOS) Close() error {
+	t.SharedOS.tenantLayers.Delete(t.fsLayer)
+	return nil
+}
+
+func (t *TenantOS) addProcess(process Process) {
+	t.processesMu.Lock()
+	defer t.processesMu.Unlock()
+	t.processes = append(t.processes, process)
+}
+
+// Processes returns the fake processes left running in the background.
+func (t *TenantOS) Processes() []Process {
+	t.processesMu.Lock()
+	defer t.processesMu.Unlock()
+	return slices.Clone(t.processes)
 }
 
 func (t *TenantOS) SetPTY(pty PTY) {
@@ -72,7 +178,7 @@ func (t *TenantOS) GetPTY() PTY {
 
 func (t *TenantOS) LoginProc() *TenantProcOS {
 	env := NewMapEnvFromEnvList(t.loginEnv())
-	usr, _ := t.SharedOS.GetUser(t.SSHUser())
+	usr, _ := t.GetUser(t.SSHUser())
 	return &TenantProcOS{
 		TenantOS:       t,
 		VFS:            t.fs,
@@ -130,8 +236,8 @@ func (t *TenantOS) LogCreds(creds *logger.Credentials) {
 func (t *TenantOS) loginEnv() []string {
 	mapEnv := NewMapEnv()
 
-	mapEnv.Setenv("SHELL", t.SharedOS.config.OS.DefaultShell)
-	mapEnv.Setenv("PATH", t.SharedOS.config.OS.DefaultPath)
+	mapEnv.Setenv("SHELL", t.config.OS.DefaultShell)
+	mapEnv.Setenv("PATH", t.config.OS.DefaultPath)
 	mapEnv.Setenv("PWD", "/")
 	mapEnv.Setenv("HOME", "/")
 
@@ -139,7 +245,7 @@ func (t *TenantOS) loginEnv() []string {
 	mapEnv.Setenv("USER", username)
 	mapEnv.Setenv("LOGNAME", username)
 
-	if usr, ok := t.SharedOS.GetUser(username); ok {
+	if usr, ok := t.GetUser(username); ok {
 		if usr.Shell != "" {
 			mapEnv.Setenv("SHELL", usr.Shell)
 		}
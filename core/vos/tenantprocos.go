@@ -1,18 +1,23 @@
 package vos
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net/url"
 	"path"
 	"path/filepath"
 	"runtime"
 	"runtime/debug"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/josephlewis42/honeyssh/core/classify"
 	"github.com/josephlewis42/honeyssh/core/logger"
+	"github.com/josephlewis42/honeyssh/core/payload"
+	"github.com/josephlewis42/honeyssh/core/triage"
 	"github.com/josephlewis42/honeyssh/jsonlog"
 	"github.com/spf13/afero"
 )
@@ -159,7 +164,8 @@ func (ea *TenantProcOS) StartProcess(name string, argv []string, attr *ProcAttr)
 		Dir:            ea.Dir,
 	}
 
-	out.VFS = NewSymlinkResolvingRelativeFs(ea.TenantOS.fs, out.Getwd)
+	// The process's own view of the filesystem, so accesses are attributed to it.
+	out.VFS = NewSymlinkResolvingRelativeFs(newAuditFs(newHoneytokenFs(ea.TenantOS.fs, out), out), out.Getwd)
 
 	if attr.Files == nil {
 		out.VIO = NewNullIO()
@@ -179,11 +185,17 @@ func (ea *TenantProcOS) StartProcess(name string, argv []string, attr *ProcAttr)
 	shellCmd, shellPath, shellErr := ea.findHoneypotCommand(out.ExecutablePath)
 	execFsPath, execFsErr := LookPath(ea, out.ExecutablePath)
 
+	classification := ea.ClassifyCommand(argv)
+
 	extend := make(map[string]any)
 
 	extend["cmd"] = argv
 	extend["EnvironmentVariables"] = env.Environ()
 	extend["ResolvedCommandPath"] = out.ExecutablePath
+	if !classification.IsEmpty() {
+		extend["Categories"] = classification.Categories
+		extend["AttackTechniques"] = classification.Techniques
+	}
 
 	jsonlog.GlobalLog.HoneyLog(ea.SSHLocalAddr().String(), ea.SSHRemoteAddr().String(), "op", extend)
 
@@ -192,11 +204,22 @@ func (ea *TenantProcOS) StartProcess(name string, argv []string, attr *ProcAttr)
 		// Command found everywhere.
 		out.Exec = shellCmd
 		out.ExecutablePath = execFsPath
+		ea.recordRunCommand(argv, env.Environ(), out.ExecutablePath, classification)
 
 	case shellErr == nil && errors.Is(execFsErr, ErrNotFound):
 		// Honeypot command found, but FS didn't have it. Run command anyway.
 		out.Exec = shellCmd
 		out.ExecutablePath = shellPath
+		ea.recordRunCommand(argv, env.Environ(), out.ExecutablePath, classification)
+	case errors.Is(shellErr, ErrNotFound) && execFsErr == nil && ea.runAsScript(out, execFsPath):
+		// The FS found a shell script, run it with the interpreter like the
+		// kernel would.
+		ea.recordRunCommand(argv, env.Environ(), execFsPath, classification)
+
+	case errors.Is(shellErr, ErrNotFound) && execFsErr == nil && ea.runAsBinary(out, execFsPath):
+		// The FS found an ELF binary, emulate it.
+		ea.recordRunCommand(argv, env.Environ(), execFsPath, classification)
+
 	case errors.Is(shellErr, ErrNotFound) && execFsErr == nil:
 		// The FS found the path but the honeypot didn't, fake a segfault
 		out.Exec = segfault
@@ -204,24 +227,30 @@ func (ea *TenantProcOS) StartProcess(name string, argv []string, attr *ProcAttr)
 
 		ea.TenantOS.eventRecorder.Record(&logger.LogEntry_UnknownCommand{
 			UnknownCommand: &logger.UnknownCommand{
-				Command: argv,
-				Status:  logger.UnknownCommand_NOT_IMPLEMENTED,
+				Command:          argv,
+				Status:           logger.UnknownCommand_NOT_IMPLEMENTED,
+				Categories:       classification.Categories,
+				AttackTechniques: classification.Techniques,
 			},
 		})
 	case errors.Is(execFsErr, ErrNotFound):
 		ea.TenantOS.eventRecorder.Record(&logger.LogEntry_UnknownCommand{
 			UnknownCommand: &logger.UnknownCommand{
-				Command: argv,
-				Status:  logger.UnknownCommand_NOT_FOUND,
+				Command:          argv,
+				Status:           logger.UnknownCommand_NOT_FOUND,
+				Categories:       classification.Categories,
+				AttackTechniques: classification.Techniques,
 			},
 		})
 		return nil, fmt.Errorf("%s: command not found", out.ExecutablePath)
 	default:
 		ea.TenantOS.eventRecorder.Record(&logger.LogEntry_UnknownCommand{
 			UnknownCommand: &logger.UnknownCommand{
-				Command:      argv,
-				Status:       logger.UnknownCommand_LOOKUP_ERROR,
-				ErrorMessage: fmt.Sprintf("honeypot err: %v FS err: %v", shellErr, execFsErr),
+				Command:          argv,
+				Status:           logger.UnknownCommand_LOOKUP_ERROR,
+				ErrorMessage:     fmt.Sprintf("honeypot err: %v FS err: %v", shellErr, execFsErr),
+				Categories:       classification.Categories,
+				AttackTechniques: classification.Techniques,
 			},
 		})
 		return nil, fmt.Errorf("%s: permission denied", out.ExecutablePath)
@@ -230,6 +259,34 @@ func (ea *TenantProcOS) StartProcess(name string, argv []string, attr *ProcAttr)
 	return out, nil
 }
 
+// LogBuiltin records a shell builtin being run.
+func (ea *TenantProcOS) LogBuiltin(argv []string) {
+	ea.recordRunCommand(argv, ea.Environ(), "shell:"+argv[0], ea.ClassifyCommand(argv))
+}
+
+// LogScriptLine records a line of a shell script being run.
+func (ea *TenantProcOS) LogScriptLine(path string, line int, text string) {
+	ea.TenantOS.eventRecorder.Record(&logger.LogEntry_ScriptLine{
+		ScriptLine: &logger.ScriptLine{
+			Path: path,
+			Line: int32(line),
+			Text: text,
+		},
+	})
+}
+
+func (ea *TenantProcOS) recordRunCommand(argv, environ []string, resolvedPath string, classification classify.Classification) {
+	ea.TenantOS.eventRecorder.Record(&logger.LogEntry_RunCommand{
+		RunCommand: &logger.RunCommand{
+			Command:              argv,
+			EnvironmentVariables: environ,
+			ResolvedCommandPath:  resolvedPath,
+			Categories:           classification.Categories,
+			AttackTechniques:     classification.Techniques,
+		},
+	})
+}
+
 func (ea *TenantProcOS) LogInvalidInvocation(err error) {
 	invalidInvocationPtr := &logger.InvalidInvocation{
 		Command: ea.Args(),
@@ -282,48 +339,226 @@ func (ea *TenantProcOS) findHoneypotCommand(execPath string) (ProcessFunc, strin
 	}
 }
 
+// DownloadInfo is the metadata written alongside downloads before they were
+// kept in a payload.Store.
 type DownloadInfo struct {
 	Source    string   `json:"source"`
 	SessionID string   `json:"session_id"`
 	Cmd       []string `json:"cmd"`
 }
 
+// DownloadMetadataSuffix is appended to a legacy download's name to get the
+// name of its DownloadInfo file.
+const DownloadMetadataSuffix = "_metadata.json"
+
 func (t *TenantProcOS) DownloadPath(source string) (afero.File, error) {
-	base := t.Now().Format(time.RFC3339Nano)
-	// Write metadata with the download to prevent data loss.
-	{
-		di := &DownloadInfo{
-			Source:    source,
-			SessionID: t.TenantOS.eventRecorder.SessionID(),
-			Cmd:       t.Args(),
-		}
-		metadata, err := json.MarshalIndent(di, "", "    ")
-		if err != nil {
-			return nil, err
+	now := t.Now()
+	sighting := payload.Sighting{
+		ID:        now.Format(time.RFC3339Nano),
+		Time:      now,
+		SessionID: t.TenantOS.eventRecorder.SessionID(),
+		Source:    source,
+		Command:   t.Args(),
+	}
+
+	capture, err := t.SharedOS.Payloads().Create(sighting)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &downloadFile{
+		Capture:       capture,
+		sighting:      sighting,
+		eventRecorder: t.eventRecorder,
+		sharedOS:      t.SharedOS,
+	}
+	// Responses are fake outside of live mode so they're only logged.
+	if network := t.config.Network; !network.IsLive() && isURL(source) {
+		out.networkMode = network.Mode
+	}
+	return out, nil
+}
+
+// isURL returns true if the source is a URL fetched over the network.
+func isURL(source string) bool {
+	u, err := url.Parse(source)
+	return err == nil && u.Host != "" && u.Scheme != "scp_upload"
+}
+
+// downloadFile records a Download event when the file is closed so the final
+// size and hashes are known.
+type downloadFile struct {
+	*payload.Capture
+	sighting      payload.Sighting
+	eventRecorder EventRecorder
+	sharedOS      *SharedOS
+	// depth is the number of scripts followed to find the download.
+	depth int
+	// networkMode is set if the download is discarded because it was made
+	// outside of live mode.
+	networkMode string
+	once        sync.Once
+}
+
+func (d *downloadFile) Close() error {
+	if d.networkMode != "" {
+		d.once.Do(func() {
+			d.Capture.Abort()
+			d.eventRecorder.Record(&logger.LogEntry_Download{
+				Download: &logger.Download{
+					Name:        d.sighting.ID,
+					Source:      d.sighting.Source,
+					Command:     d.sighting.Command,
+					NetworkMode: d.networkMode,
+				},
+			})
+		})
+		return nil
+	}
+
+	err := d.Capture.Close()
+	d.once.Do(func() {
+		info := d.Capture.Info()
+		if info == nil {
+			return
 		}
+		d.eventRecorder.Record(&logger.LogEntry_Download{
+			Download: &logger.Download{
+				Name:         d.sighting.ID,
+				Source:       d.sighting.Source,
+				Command:      d.sighting.Command,
+				Size:         info.Size,
+				Sha256:       info.SHA256,
+				Md5:          info.MD5,
+				Sha1:         info.SHA1,
+				Ssdeep:       info.SSDEEP,
+				ParentSha256: d.sighting.Parent,
+			},
+		})
 
-		dfd, err := t.SharedOS.config.CreateDownload(base + "_metadata.json")
-		if err != nil {
-			return nil, err
+		// Payloads are triaged and harvested the first time they're seen.
+		if !d.Capture.IsNew() {
+			return
 		}
-		defer dfd.Close()
-		dfd.Write(metadata)
+		analysis := d.triage(info)
+		if analysis != nil && d.shouldHarvest(analysis) {
+			if d.depth > 0 {
+				// Already running in a harvest.
+				d.harvest(info, analysis.URLs)
+				return
+			}
+			d.sharedOS.harvests.Add(1)
+			go func() {
+				defer d.sharedOS.harvests.Done()
+
+				// Harvests run outside of any session, so a panic here would
+				// bring down the whole process.
+				defer func() {
+					if r := recover(); r != nil {
+						d.eventRecorder.Record(&logger.LogEntry_Panic{
+							Panic: &logger.Panic{
+								Context:    fmt.Sprintf("Harvesting %s got panic: %v", info.SHA256, r),
+								Stacktrace: string(debug.Stack()),
+							},
+						})
+					}
+				}()
+
+				d.harvest(info, analysis.URLs)
+			}()
+		}
+	})
+	return err
+}
+
+func (d *downloadFile) triage(info *payload.Info) *triage.Analysis {
+	store := d.sharedOS.Payloads()
+	fd, err := store.Open(info.SHA256)
+	if err != nil {
+		log.Printf("couldn't open payload %s: %v", info.SHA256, err)
+		return nil
 	}
+	defer fd.Close()
 
-	fd, err := t.SharedOS.config.CreateDownload(base + ".download")
+	analysis, err := d.sharedOS.Analyzer().AnalyzeReader(fd)
 	if err != nil {
-		return nil, err
+		log.Printf("couldn't analyze payload %s: %v", info.SHA256, err)
+		return nil
+	}
+	if err := store.SetAnalysis(info.SHA256, analysis); err != nil {
+		log.Printf("couldn't save analysis of payload %s: %v", info.SHA256, err)
 	}
 
-	t.eventRecorder.Record(&logger.LogEntry_Download{
-		Download: &logger.Download{
-			Name:    base,
-			Source:  source,
-			Command: t.Args(),
-		},
-	})
+	event := &logger.PayloadAnalysis{
+		Sha256:      info.SHA256,
+		Kind:        analysis.Kind,
+		FileType:    analysis.FileType,
+		Urls:        analysis.URLs,
+		Ips:         analysis.IPs,
+		Wallets:     analysis.WalletAddresses(),
+		RuleMatches: analysis.RuleNames(),
+		Tags:        analysis.Tags(),
+	}
+	if analysis.ELF != nil {
+		event.Architecture = analysis.ELF.Machine
+		event.Bits = int32(analysis.ELF.Bits)
+	}
+	d.eventRecorder.Record(&logger.LogEntry_PayloadAnalysis{PayloadAnalysis: event})
+	return analysis
+}
 
-	return fd, err
+// scriptInterpreters holds the names of shells the honeypot runs scripts
+// with.
+var scriptInterpreters = map[string]bool{
+	"sh":   true,
+	"bash": true,
+	"dash": true,
+}
+
+// runAsScript sets up proc to run scriptPath with its interpreter if it's a
+// shell script. It returns false if the file isn't a script the honeypot can
+// run.
+func (ea *TenantProcOS) runAsScript(proc *TenantProcOS, scriptPath string) bool {
+	interpreter, ok := readShebang(ea, scriptPath)
+	if !ok {
+		return false
+	}
+	cmd, interpreterPath, err := ea.findHoneypotCommand(interpreter)
+	if err != nil {
+		return false
+	}
+
+	proc.Exec = cmd
+	proc.ExecutablePath = interpreterPath
+	proc.ProcArgs = append([]string{interpreter}, proc.ProcArgs...)
+	return true
+}
+
+// readShebang returns the shell named in the script's #! line.
+func readShebang(fs VFS, name string) (interpreter string, ok bool) {
+	fd, err := fs.Open(name)
+	if err != nil {
+		return "", false
+	}
+	defer fd.Close()
+
+	buf := make([]byte, 128)
+	n, _ := io.ReadFull(fd, buf)
+	line, found := strings.CutPrefix(string(buf[:n]), "#!")
+	if !found {
+		return "", false
+	}
+	line, _, _ = strings.Cut(line, "\n")
+
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return "", false
+	}
+	interpreter = fields[0]
+	if path.Base(interpreter) == "env" && len(fields) > 1 {
+		interpreter = path.Join("/bin", fields[1])
+	}
+	return interpreter, scriptInterpreters[path.Base(interpreter)]
 }
 
 func segfault(virtOS VOS) int {
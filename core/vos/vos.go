@@ -3,10 +3,13 @@ package vos
 import (
 	"io"
 	"net"
+	"net/http"
 	"os"
 	"time"
 
+	"github.com/josephlewis42/honeyssh/core/config"
 	"github.com/josephlewis42/honeyssh/core/logger"
+	"github.com/josephlewis42/honeyssh/core/persona"
 	"github.com/spf13/afero"
 )
 
@@ -25,6 +28,8 @@ type VKernel interface {
 	Hostname() string
 	// Uname mimics the uname syscall.
 	Uname() Utsname
+	// Persona describes the hardware and software of the machine.
+	Persona() *persona.Persona
 }
 
 type PTY struct {
@@ -97,6 +102,9 @@ type VProc interface {
 	// Run executes the command, waits for it to finish and returns the status
 	// code.
 	Run() int
+
+	// Processes returns the fake processes left running in the background.
+	Processes() []Process
 }
 
 // VFS implements a virtual filesystem and is the second layer of the virtual OS.
@@ -177,12 +185,33 @@ type Honeypot interface {
 	// Record when credentials are used by the attacker.
 	LogCreds(*logger.Credentials)
 
+	// GetUser looks up a configured user by name.
+	GetUser(username string) (config.User, bool)
+	// CheckPassword returns true if the password is valid for the user.
+	CheckPassword(username, password string) bool
+
+	// Record when a shell builtin is run by the attacker.
+	LogBuiltin(argv []string)
+
+	// Record a line of a shell script being run.
+	LogScriptLine(path string, line int, text string)
+
 	// Get a unique path in the downloads folder that the session can write a
 	// file to.
 	DownloadPath(source string) (afero.File, error)
 
 	// Now is the current honeypot time.
 	Now() time.Time
+
+	// HTTPClient returns the client processes use to make HTTP requests, it's
+	// nil if the process should use its own.
+	HTTPClient() *http.Client
+
+	// InternalHost finds the fake internal host at the address.
+	InternalHost(address string) (config.InternalHost, bool)
+	// Pivot logs the user in to an internal host reached at address and runs
+	// argv there in a nested session, returning its exit status.
+	Pivot(host config.InternalHost, address, username string, argv []string, files VIO) int
 }
 
 // /proc/sys/kernel/{ostype, hostname, osrelease, version, domainname}.
@@ -48,7 +48,13 @@ type LogEntry struct {
 	Extend              map[string]any `json:"extend,omitempty"`
 }
 
+// Log writes the entry to the log file, it's a no-op if the logger hasn't been
+// initialized.
 func (l *Logger) Log(entry LogEntry) {
+	if l == nil {
+		return
+	}
+
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
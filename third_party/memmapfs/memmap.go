@@ -442,6 +442,21 @@ func (m *MemMapFs) Chtimes(name string, atime time.Time, mtime time.Time) error
 	return nil
 }
 
+// Usage returns the number of bytes of file content held in memory.
+func (m *MemMapFs) Usage() int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var total int64
+	for _, x := range m.getData() {
+		info := mem.FileInfo{FileData: x}
+		if !info.IsDir() {
+			total += info.Size()
+		}
+	}
+	return total
+}
+
 func (m *MemMapFs) List() {
 	for _, x := range m.data {
 		y := mem.FileInfo{FileData: x}